@@ -32,6 +32,11 @@ type Config struct {
 	Dsn_DBu             string
 	ApiKey              string
 	JWTSecret           string
+	JwtKeyFiles         string
+	JwtPrivateKey       string
+	JwtKeyID            string
+	JwtSigningKeyID     string
+	JwtAcceptHS256      bool
 	JwtAccessTokenTime  int
 	JwtRefreshTokenTime int
 	OtpExpired          int
@@ -61,6 +66,29 @@ type Config struct {
 	PEANotificationToken         string
 	PEAWorkDNotificationEndPoint string
 	PEAWorkDNotificationToken    string
+
+	WaitlistHoldMinutes int
+	DraftPurgeDays      int
+
+	RideShareWindowMinutes int
+
+	ActionLinkUrl         string
+	ActionLinkExpireHours int
+
+	OtpProvider             string
+	OtpLimiterStore         string
+	OtpRequestLimitPhone    int
+	OtpRequestLimitIP       int
+	OtpRequestWindowMinutes int
+	OtpVerifyMaxAttempts    int
+	OtpLockoutMinutes       int
+	OtpLockoutMaxMinutes    int
+
+	ImpersonationMaxMinutes int
+
+	ApiKeyScopes string
+
+	SessionLegacyGraceUntil string
 }
 
 // AppConfig is a globally accessible configuration variable
@@ -86,6 +114,11 @@ func InitConfig() {
 		Dsn_DBu:             os.Getenv("DSN_DB_USER"),
 		ApiKey:              os.Getenv("API_KEY"),
 		JWTSecret:           os.Getenv("JWT_SECRET"),
+		JwtKeyFiles:         os.Getenv("JWT_KEY_FILES"),
+		JwtPrivateKey:       os.Getenv("JWT_PRIVATE_KEY"),
+		JwtKeyID:            os.Getenv("JWT_KEY_ID"),
+		JwtSigningKeyID:     os.Getenv("JWT_SIGNING_KEY_ID"),
+		JwtAcceptHS256:      os.Getenv("JWT_ACCEPT_HS256") == "true",
 		JwtAccessTokenTime:  600,  // Default: 60 minutes
 		JwtRefreshTokenTime: 1440, // Default: 1440 minutes
 		OtpExpired:          1,    // Default: 1 minutes
@@ -114,6 +147,29 @@ func InitConfig() {
 		PEANotificationToken:         os.Getenv("PEA_NOTIFICATION_TOKEN"),
 		PEAWorkDNotificationEndPoint: os.Getenv("PEA_WORK_D_NOTIFICATION_END_POINT"),
 		PEAWorkDNotificationToken:    os.Getenv("PEA_WORK_D_NOTIFICATION_TOKEN"),
+
+		WaitlistHoldMinutes: getEnvAsInt("WAITLIST_HOLD_MINUTES", 30),
+		DraftPurgeDays:      getEnvAsInt("DRAFT_PURGE_DAYS", 30),
+
+		RideShareWindowMinutes: getEnvAsInt("RIDE_SHARE_WINDOW_MINUTES", 60),
+
+		ActionLinkUrl:         os.Getenv("ACTION_LINK_URL"),
+		ActionLinkExpireHours: getEnvAsInt("ACTION_LINK_EXPIRE_HOURS", 72),
+
+		OtpProvider:             os.Getenv("OTP_PROVIDER"),
+		OtpLimiterStore:         os.Getenv("OTP_LIMITER_STORE"),
+		OtpRequestLimitPhone:    getEnvAsInt("OTP_REQUEST_LIMIT_PHONE", 3),
+		OtpRequestLimitIP:       getEnvAsInt("OTP_REQUEST_LIMIT_IP", 20),
+		OtpRequestWindowMinutes: getEnvAsInt("OTP_REQUEST_WINDOW_MINUTES", 15),
+		OtpVerifyMaxAttempts:    getEnvAsInt("OTP_VERIFY_MAX_ATTEMPTS", 5),
+		OtpLockoutMinutes:       getEnvAsInt("OTP_LOCKOUT_MINUTES", 5),
+		OtpLockoutMaxMinutes:    getEnvAsInt("OTP_LOCKOUT_MAX_MINUTES", 1440),
+
+		ImpersonationMaxMinutes: getEnvAsInt("IMPERSONATION_MAX_MINUTES", 60),
+
+		ApiKeyScopes: os.Getenv("API_KEY_SCOPES"),
+
+		SessionLegacyGraceUntil: os.Getenv("SESSION_LEGACY_GRACE_UNTIL"),
 	}
 	fmt.Printf("load AppConfig: %s %d\n", AppConfig.AppName, AppConfig.Port)
 
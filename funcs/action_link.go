@@ -0,0 +1,201 @@
+package funcs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type ActionLinkClaims struct {
+	RecordUID            string `json:"record_uid"`
+	ActionStep           string `json:"action_step"`
+	ActionCode           string `json:"action_code"`
+	RefRequestStatusCode string `json:"ref_request_status_code"`
+	jwt.RegisteredClaims
+}
+
+// actionLinkSecret is derived from the JWT secret so an action token is never accepted as a login token
+func actionLinkSecret() []byte {
+	return []byte(config.AppConfig.JWTSecret + ":action-link")
+}
+
+// ActionLinkStepRoles are the approver role each action step acts as, the role of the notification the link was sent with
+var ActionLinkStepRoles = map[string]string{
+	"booking-confirmer": "level1-approval",
+	"booking-final":     "approval-carpool",
+	"license-confirmer": "level1-approval",
+	"license-approver":  "license-approval",
+}
+
+// GetActionLinkStep returns the approval step a notification can act on by link, or "" when it has none
+func GetActionLinkStep(notify models.Notification) string {
+	switch {
+	case notify.NotifyType == "request-booking" && notify.NotifyRole == "level1-approval" && notify.RefRequestStatusCode == "20":
+		return "booking-confirmer"
+	case notify.NotifyType == "request-booking" && notify.NotifyRole == "approval-carpool" && notify.RefRequestStatusCode == "40":
+		return "booking-final"
+	case notify.NotifyType == "request-annual-driver" && notify.NotifyRole == "level1-approval" && notify.RefRequestStatusCode == "10":
+		return "license-confirmer"
+	case notify.NotifyType == "request-annual-driver" && notify.NotifyRole == "license-approval" && notify.RefRequestStatusCode == "20":
+		return "license-approver"
+	}
+	return ""
+}
+
+// GetActionRecordStatus returns the current status and number of the request behind an action step
+func GetActionRecordStatus(actionStep, recordUID string) (string, string, error) {
+	var record struct {
+		StatusCode string
+		RequestNo  string
+	}
+	switch actionStep {
+	case "booking-confirmer", "booking-final":
+		if err := config.DB.Table("vms_trn_request").
+			Select("ref_request_status_code as status_code, request_no").
+			Where("trn_request_uid = ? and is_deleted = '0'", recordUID).
+			Take(&record).Error; err != nil {
+			return "", "", err
+		}
+	case "license-confirmer", "license-approver":
+		if err := config.DB.Table("vms_trn_request_annual_driver").
+			Select("ref_request_annual_driver_status_code as status_code, request_annual_driver_no as request_no").
+			Where("trn_request_annual_driver_uid = ? and is_deleted = '0'", recordUID).
+			Take(&record).Error; err != nil {
+			return "", "", err
+		}
+	default:
+		return "", "", messages.ErrActionLinkInvalid
+	}
+	return record.StatusCode, record.RequestNo, nil
+}
+
+// CreateActionLink issues a signed single-use token for one approver, request and decision and returns the link to act with it
+func CreateActionLink(notify models.Notification, actionStep, actionCode string) (string, error) {
+	expire := time.Now().Add(time.Duration(config.AppConfig.ActionLinkExpireHours) * time.Hour)
+	actionToken := models.VmsTrnActionToken{
+		TrnActionTokenUID:    uuid.New().String(),
+		RecordUID:            notify.RecordUID,
+		ActionStep:           actionStep,
+		ActionCode:           actionCode,
+		EmpID:                notify.EmpID,
+		RefRequestStatusCode: notify.RefRequestStatusCode,
+		ExpireDatetime:       models.TimeWithZone{Time: expire},
+		IsUsed:               "0",
+		CreatedAt:            time.Now(),
+	}
+	if err := config.DB.Create(&actionToken).Error; err != nil {
+		return "", err
+	}
+
+	claims := ActionLinkClaims{
+		RecordUID:            actionToken.RecordUID,
+		ActionStep:           actionToken.ActionStep,
+		ActionCode:           actionToken.ActionCode,
+		RefRequestStatusCode: actionToken.RefRequestStatusCode,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        actionToken.TrnActionTokenUID,
+			Subject:   actionToken.EmpID,
+			ExpiresAt: jwt.NewNumericDate(expire),
+		},
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(actionLinkSecret())
+	if err != nil {
+		return "", err
+	}
+	return config.AppConfig.ActionLinkUrl + "?token=" + url.QueryEscape(tokenString), nil
+}
+
+// GetActionLinkContent builds the approve and reject links of a notification, or "" when the notification has no action by link
+func GetActionLinkContent(notify models.Notification) string {
+	actionStep := GetActionLinkStep(notify)
+	if actionStep == "" || config.AppConfig.ActionLinkUrl == "" {
+		return ""
+	}
+	approveLink, err := CreateActionLink(notify, actionStep, "approve")
+	if err != nil {
+		fmt.Println("Error creating action link:", err)
+		return ""
+	}
+	rejectLink, err := CreateActionLink(notify, actionStep, "reject")
+	if err != nil {
+		fmt.Println("Error creating action link:", err)
+		return ""
+	}
+	return "อนุมัติ: " + approveLink + " | ตีกลับ: " + rejectLink
+}
+
+// ParseActionToken checks the signature, expiry and single use of an action token and that the request is still in the status it was issued for
+func ParseActionToken(tokenString string) (models.VmsTrnActionToken, string, error) {
+	var actionToken models.VmsTrnActionToken
+	claims := &ActionLinkClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return actionLinkSecret(), nil
+	})
+	if err != nil || !token.Valid {
+		return actionToken, "", messages.ErrActionLinkInvalid
+	}
+	if err := config.DB.First(&actionToken, "trn_action_token_uid = ?", claims.ID).Error; err != nil {
+		return actionToken, "", messages.ErrActionLinkInvalid
+	}
+	if actionToken.EmpID != claims.Subject || actionToken.RecordUID != claims.RecordUID ||
+		actionToken.ActionStep != claims.ActionStep || actionToken.ActionCode != claims.ActionCode ||
+		actionToken.RefRequestStatusCode != claims.RefRequestStatusCode || actionToken.ExpireDatetime.Before(time.Now()) {
+		return actionToken, "", messages.ErrActionLinkInvalid
+	}
+	if actionToken.IsUsed == "1" {
+		return actionToken, "", messages.ErrActionLinkUsed
+	}
+	statusCode, requestNo, err := GetActionRecordStatus(actionToken.ActionStep, actionToken.RecordUID)
+	if err != nil {
+		return actionToken, "", messages.ErrActionLinkInvalid
+	}
+	if statusCode != actionToken.RefRequestStatusCode {
+		return actionToken, requestNo, messages.ErrActionLinkUsed
+	}
+	return actionToken, requestNo, nil
+}
+
+// UseActionToken marks a token as used, returning false when another request used it first
+func UseActionToken(actionToken models.VmsTrnActionToken, usedIP, usedUserAgent string) bool {
+	result := config.DB.Model(&models.VmsTrnActionToken{}).
+		Where("trn_action_token_uid = ? and is_used = '0'", actionToken.TrnActionTokenUID).
+		UpdateColumns(map[string]interface{}{
+			"is_used":         "1",
+			"used_datetime":   time.Now(),
+			"used_ip":         usedIP,
+			"used_user_agent": usedUserAgent,
+		})
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// ReleaseActionToken makes a token usable again after the action could not be applied
+func ReleaseActionToken(actionToken models.VmsTrnActionToken) {
+	config.DB.Model(&models.VmsTrnActionToken{}).
+		Where("trn_action_token_uid = ?", actionToken.TrnActionTokenUID).
+		UpdateColumns(map[string]interface{}{
+			"is_used":         "0",
+			"used_datetime":   nil,
+			"used_ip":         "",
+			"used_user_agent": "",
+		})
+}
+
+// ExpireActionTokens closes the other links sent for the same request and status once one of them is used
+func ExpireActionTokens(actionToken models.VmsTrnActionToken) {
+	config.DB.Model(&models.VmsTrnActionToken{}).
+		Where("record_uid = ? and ref_request_status_code = ? and is_used = '0'", actionToken.RecordUID, actionToken.RefRequestStatusCode).
+		UpdateColumns(map[string]interface{}{
+			"is_used":       "1",
+			"used_datetime": time.Now(),
+		})
+}
@@ -0,0 +1,223 @@
+package funcs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnvApiClientCode is the client calls made with API_KEY are counted against, API_KEY may call the route groups in API_KEY_SCOPES only
+const EnvApiClientCode = "env"
+
+// ApiClient is a registered key of an API client as the middleware checks it
+type ApiClient struct {
+	MasApiClientUID    string
+	MasApiClientKeyUID string
+	ClientCode         string
+	Scopes             []string
+	ExpiresAt          *time.Time
+}
+
+type apiClientUsageKey struct {
+	MasApiClientUID    string
+	MasApiClientKeyUID string
+	UsageDate          string
+	RouteGroup         string
+}
+
+var (
+	apiClientMu sync.RWMutex
+	apiClients  = map[string]ApiClient{}
+
+	apiClientUsageMu sync.Mutex
+	apiClientUsage   = map[apiClientUsageKey]int64{}
+)
+
+// HashApiKey returns the hash an API key is kept and looked up by
+func HashApiKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateApiKey returns a new API key and the prefix it is shown by, the key itself is shown once
+func GenerateApiKey() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	apiKey := "vms_" + hex.EncodeToString(b)
+	return apiKey, apiKey[:10], nil
+}
+
+// ReloadApiClients reads the active keys of the active clients, call it after changing the registry.
+// RunCronJobReloadApiClients calls it every minute so a change reaches the other replicas.
+func ReloadApiClients() error {
+	type apiClientKeyRow struct {
+		MasApiClientUID    string
+		MasApiClientKeyUID string
+		ClientCode         string
+		Scopes             string
+		KeyHash            string
+		ExpiresAt          *time.Time
+	}
+	var rows []apiClientKeyRow
+	if err := config.DB.Table("vms_mas_api_client_key k").
+		Select("c.mas_api_client_uid, k.mas_api_client_key_uid, c.client_code, c.scopes, k.key_hash, k.expires_at").
+		Joins("INNER JOIN vms_mas_api_client c ON c.mas_api_client_uid = k.mas_api_client_uid").
+		Where("k.is_active = ? AND c.is_active = ? AND c.is_deleted = ?", "1", "1", "0").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	clients := make(map[string]ApiClient, len(rows))
+	for _, row := range rows {
+		clients[row.KeyHash] = ApiClient{
+			MasApiClientUID:    row.MasApiClientUID,
+			MasApiClientKeyUID: row.MasApiClientKeyUID,
+			ClientCode:         row.ClientCode,
+			Scopes:             GetApiScopes(row.Scopes),
+			ExpiresAt:          row.ExpiresAt,
+		}
+	}
+	// API_KEY is kept by its hash like the registered keys, it is refused unless API_KEY_SCOPES names what it may call
+	if envScopes := GetApiScopes(config.AppConfig.ApiKeyScopes); config.AppConfig.ApiKey != "" && len(envScopes) > 0 {
+		clients[HashApiKey(config.AppConfig.ApiKey)] = ApiClient{
+			MasApiClientUID: EnvApiClientCode,
+			ClientCode:      EnvApiClientCode,
+			Scopes:          envScopes,
+		}
+	}
+	apiClientMu.Lock()
+	apiClients = clients
+	apiClientMu.Unlock()
+	return nil
+}
+
+// GetApiScopes splits the comma-separated scopes of a client
+func GetApiScopes(scopes string) []string {
+	result := []string{}
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+// FindApiClient returns the client the API key belongs to, expired keys belong to none
+func FindApiClient(apiKey string) (ApiClient, bool) {
+	apiClientMu.RLock()
+	client, ok := apiClients[HashApiKey(apiKey)]
+	apiClientMu.RUnlock()
+	if !ok || (client.ExpiresAt != nil && client.ExpiresAt.Before(time.Now())) {
+		return ApiClient{}, false
+	}
+	return client, true
+}
+
+// GetRouteGroup returns the route group of the call, the path segment after /api/, such as booking-user
+func GetRouteGroup(c *gin.Context) string {
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	group, _, _ := strings.Cut(strings.TrimPrefix(path, "/api/"), "/")
+	return group
+}
+
+// HasApiScope tells whether one of the scopes covers the route group, a scope ending in * covers the groups it prefixes
+func HasApiScope(scopes []string, group string) bool {
+	for _, scope := range scopes {
+		if scope == group || (strings.HasSuffix(scope, "*") && strings.HasPrefix(group, strings.TrimSuffix(scope, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckApiClient answers 401 or 403 and aborts unless X-ApiKey is a key of a client whose scopes cover the route group.
+// Allowed calls are counted per client.
+func CheckApiClient(c *gin.Context) bool {
+	apiKey := c.GetHeader("X-ApiKey")
+	if apiKey == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing API key", "message": "กรุณาระบุ API key"})
+		c.Abort()
+		return false
+	}
+	client, ok := FindApiClient(apiKey)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid API key", "message": "API key ไม่ถูกต้อง"})
+		c.Abort()
+		return false
+	}
+	group := GetRouteGroup(c)
+	if !HasApiScope(client.Scopes, group) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key is not allowed to call " + group, "message": "API key นี้ไม่มีสิทธิ์เรียกใช้งานส่วนนี้"})
+		c.Abort()
+		return false
+	}
+
+	RecordApiClientUsage(client, group)
+	return true
+}
+
+// RecordApiClientUsage counts a call in memory, FlushApiClientUsage writes the counts
+func RecordApiClientUsage(client ApiClient, group string) {
+	key := apiClientUsageKey{
+		MasApiClientUID:    client.MasApiClientUID,
+		MasApiClientKeyUID: client.MasApiClientKeyUID,
+		UsageDate:          time.Now().Format("2006-01-02"),
+		RouteGroup:         group,
+	}
+	apiClientUsageMu.Lock()
+	apiClientUsage[key]++
+	apiClientUsageMu.Unlock()
+}
+
+// FlushApiClientUsage adds the calls counted since the last flush to vms_trn_api_client_usage and stamps the keys used
+func FlushApiClientUsage() {
+	apiClientUsageMu.Lock()
+	usage := apiClientUsage
+	apiClientUsage = map[apiClientUsageKey]int64{}
+	apiClientUsageMu.Unlock()
+
+	now := time.Now()
+	keyUIDs := []string{}
+	for key, count := range usage {
+		row := models.VmsTrnApiClientUsage{
+			MasApiClientUID: key.MasApiClientUID,
+			UsageDate:       key.UsageDate,
+			RouteGroup:      key.RouteGroup,
+			RequestCount:    count,
+			LastUsedAt:      now,
+		}
+		if err := config.DB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "mas_api_client_uid"}, {Name: "usage_date"}, {Name: "route_group"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"request_count": gorm.Expr("vms_trn_api_client_usage.request_count + ?", count),
+				"last_used_at":  now,
+			}),
+		}).Create(&row).Error; err != nil {
+			log.Println("Error recording API client usage:", err)
+		}
+		if key.MasApiClientKeyUID != "" && !Contains(keyUIDs, key.MasApiClientKeyUID) {
+			keyUIDs = append(keyUIDs, key.MasApiClientKeyUID)
+		}
+	}
+	if len(keyUIDs) > 0 {
+		if err := config.DB.Model(&models.VmsMasApiClientKey{}).Where("mas_api_client_key_uid in (?)", keyUIDs).UpdateColumn("last_used_at", now).Error; err != nil {
+			log.Println("Error recording API key usage:", err)
+		}
+	}
+}
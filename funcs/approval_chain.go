@@ -0,0 +1,214 @@
+package funcs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ApprovalChainStatusRoles are the outward statuses a chain step can run in and the approver role acting in them
+var ApprovalChainStatusRoles = map[string]string{
+	"20": "level1-approval",
+	"40": "approval-carpool",
+}
+
+// ApprovalModes are how the approvers of a step decide: one manager at the level, any one listed approver, or all of them
+var ApprovalModes = []string{"level", "any", "all"}
+
+// IsApprovalStepMatched checks the step conditions against the request, an empty condition always matches
+func IsApprovalStepMatched(step models.VmsMasApprovalChainStep, request models.VmsTrnRequestApprovalChainCheck) bool {
+	if step.ConditionRefTripTypeCode != nil && (request.RefTripTypeCode == nil || *request.RefTripTypeCode != *step.ConditionRefTripTypeCode) {
+		return false
+	}
+	if step.ConditionRefCostTypeCode != nil && (request.RefCostTypeCode == nil || *request.RefCostTypeCode != *step.ConditionRefCostTypeCode) {
+		return false
+	}
+	if step.ConditionIsOutOfProvince == "1" && request.IsOutOfProvince != "1" {
+		return false
+	}
+	if step.ConditionMinDurationHours > 0 &&
+		request.ReserveEndDatetime.Sub(request.ReserveStartDatetime.Time).Hours() < float64(step.ConditionMinDurationHours) {
+		return false
+	}
+	return true
+}
+
+// GetApprovalStepApprovers returns the approvers of the step for the request, managers of the vehicle user at the step level for a level step
+func GetApprovalStepApprovers(step models.VmsMasApprovalChainStep, request models.VmsTrnRequestApprovalChainCheck) []models.VmsTrnRequestApprovalStepApprover {
+	var approvers []models.VmsTrnRequestApprovalStepApprover
+	if step.ApprovalMode != "level" {
+		for _, approver := range step.Approvers {
+			approvers = append(approvers, models.VmsTrnRequestApprovalStepApprover{
+				ApproverEmpID:    approver.ApproverEmpID,
+				ApproverEmpName:  approver.ApproverEmpName,
+				ApproverPosition: approver.ApproverPosition,
+			})
+		}
+		return approvers
+	}
+
+	managers := GetUserManager(request.VehicleUserDeptSAP)
+	if len(managers) > 0 {
+		managers = append(managers, GetUserManager(strconv.Itoa(managers[0].DeptUpper))...)
+	}
+	for _, manager := range managers {
+		if manager.Type != "L" || !IsLevelCodeAtLeast(manager.LevelCode, step.LevelCode) {
+			continue
+		}
+		approvers = append(approvers, models.VmsTrnRequestApprovalStepApprover{
+			ApproverEmpID:    strconv.Itoa(manager.EmpIDLeader),
+			ApproverEmpName:  manager.EmpName,
+			ApproverPosition: manager.PlansTextShort,
+		})
+		break
+	}
+	return approvers
+}
+
+// BuildRequestApprovalSteps creates the chain steps that apply to a request when it is sent for approval, replacing steps of an earlier round
+func BuildRequestApprovalSteps(trnRequestUID string) {
+	var request models.VmsTrnRequestApprovalChainCheck
+	if err := config.DB.Where("trn_request_uid = ? and is_deleted = '0'", trnRequestUID).First(&request).Error; err != nil {
+		return
+	}
+	if request.RefRequestStatusCode != "20" || request.MasCarpoolUID == nil || *request.MasCarpoolUID == "" {
+		return
+	}
+
+	config.DB.Where("trn_request_approval_step_uid in (?)",
+		config.DB.Model(&models.VmsTrnRequestApprovalStep{}).Select("trn_request_approval_step_uid").Where("trn_request_uid = ?", trnRequestUID)).
+		Delete(&models.VmsTrnRequestApprovalStepApprover{})
+	config.DB.Where("trn_request_uid = ?", trnRequestUID).Delete(&models.VmsTrnRequestApprovalStep{})
+
+	var chain models.VmsMasApprovalChain
+	if err := config.DB.Preload("Steps.Approvers").
+		Where("mas_carpool_uid = ? and is_active = '1' and is_deleted = '0'", request.MasCarpoolUID).
+		First(&chain).Error; err != nil {
+		return
+	}
+	sort.Slice(chain.Steps, func(i, j int) bool { return chain.Steps[i].StepNo < chain.Steps[j].StepNo })
+
+	for _, step := range chain.Steps {
+		if !IsApprovalStepMatched(step, request) {
+			continue
+		}
+		approvers := GetApprovalStepApprovers(step, request)
+		if len(approvers) == 0 {
+			fmt.Println("No approver for approval step:", step.StepName)
+			continue
+		}
+		requestStep := models.VmsTrnRequestApprovalStep{
+			TrnRequestApprovalStepUID: uuid.New().String(),
+			TrnRequestUID:             trnRequestUID,
+			MasApprovalChainStepUID:   step.MasApprovalChainStepUID,
+			StepNo:                    step.StepNo,
+			StepName:                  step.StepName,
+			RefRequestStatusCode:      step.RefRequestStatusCode,
+			ApprovalMode:              step.ApprovalMode,
+			StepStatusCode:            "0",
+			CreatedAt:                 time.Now(),
+		}
+		for i := range approvers {
+			approvers[i].TrnRequestApprovalStepApproverUID = uuid.New().String()
+			approvers[i].IsApproved = "0"
+		}
+		requestStep.Approvers = approvers
+		if err := config.DB.Create(&requestStep).Error; err != nil {
+			fmt.Println("Error creating approval step:", err)
+		}
+	}
+}
+
+// GetRequestApprovalSteps returns the chain steps of a request in order
+func GetRequestApprovalSteps(trnRequestUID string) []models.VmsTrnRequestApprovalStep {
+	steps := []models.VmsTrnRequestApprovalStep{}
+	config.DB.Preload("Approvers").Where("trn_request_uid = ?", trnRequestUID).Order("step_no").Find(&steps)
+	return steps
+}
+
+// HasPendingApprovalStep reports whether the request still has chain steps to approve in the status
+func HasPendingApprovalStep(trnRequestUID, refRequestStatusCode string) bool {
+	var count int64
+	config.DB.Model(&models.VmsTrnRequestApprovalStep{}).
+		Where("trn_request_uid = ? and ref_request_status_code = ? and step_status_code = '0'", trnRequestUID, refRequestStatusCode).
+		Count(&count)
+	return count > 0
+}
+
+// ApproveRequestApprovalStep records the user's approval of the current step of the status.
+// It returns true when no step of the status is left, so the caller can move the request to the next status.
+func ApproveRequestApprovalStep(user *models.AuthenUserEmp, trnRequestUID, refRequestStatusCode string) (bool, error) {
+	var step models.VmsTrnRequestApprovalStep
+	if err := config.DB.Preload("Approvers").
+		Where("trn_request_uid = ? and ref_request_status_code = ? and step_status_code = '0'", trnRequestUID, refRequestStatusCode).
+		Order("step_no").First(&step).Error; err != nil {
+		return true, nil
+	}
+
+	role := ApprovalChainStatusRoles[refRequestStatusCode]
+	empIDs := GetApprovalEmpIDs(user, role)
+	approverUID := ""
+	for _, approver := range step.Approvers {
+		if Contains(empIDs, approver.ApproverEmpID) && approver.IsApproved != "1" {
+			approverUID = approver.TrnRequestApprovalStepApproverUID
+			break
+		}
+	}
+	if approverUID == "" {
+		return false, fmt.Errorf("%w: not an approver of step %s", messages.ErrBookingCannotUpdate, step.StepName)
+	}
+
+	now := time.Now()
+	if err := config.DB.Model(&models.VmsTrnRequestApprovalStepApprover{}).
+		Where("trn_request_approval_step_approver_uid = ?", approverUID).
+		UpdateColumns(map[string]interface{}{"is_approved": "1", "approved_datetime": now}).Error; err != nil {
+		return false, fmt.Errorf("failed to update : %v", err)
+	}
+	var pending int64
+	if step.ApprovalMode == "all" {
+		config.DB.Model(&models.VmsTrnRequestApprovalStepApprover{}).
+			Where("trn_request_approval_step_uid = ? and is_approved <> '1'", step.TrnRequestApprovalStepUID).
+			Count(&pending)
+	}
+	if pending == 0 {
+		if err := config.DB.Model(&step).UpdateColumns(map[string]interface{}{"step_status_code": "1", "approved_datetime": now}).Error; err != nil {
+			return false, fmt.Errorf("failed to update : %v", err)
+		}
+	}
+
+	if HasPendingApprovalStep(trnRequestUID, refRequestStatusCode) {
+		CreateTrnRequestEventLog(trnRequestUID, refRequestStatusCode, "อนุมัติขั้นตอน "+step.StepName, user.EmpID, role, "")
+		return false, nil
+	}
+	return true, nil
+}
+
+// SetQueryApprovalStepApprover selects the requests where the user, or someone the user acts for, approves a pending step of the status
+func SetQueryApprovalStepApprover(user *models.AuthenUserEmp, refRequestStatusCode string) *gorm.DB {
+	return config.DB.Table("vms_trn_request_approval_step s").
+		Select("s.trn_request_uid").
+		Joins("INNER JOIN vms_trn_request_approval_step_approver a ON a.trn_request_approval_step_uid = s.trn_request_approval_step_uid").
+		Where("s.ref_request_status_code = ? and s.step_status_code = '0' and a.approver_emp_id in (?)",
+			refRequestStatusCode, GetApprovalEmpIDs(user, ApprovalChainStatusRoles[refRequestStatusCode]))
+}
+
+// CheckApprovalChainRole adds the approver role of the statuses where the user approves a pending chain step
+func CheckApprovalChainRole(user *models.AuthenUserEmp) {
+	for status, role := range ApprovalChainStatusRoles {
+		var count int64
+		config.DB.Table("vms_trn_request_approval_step s").
+			Joins("INNER JOIN vms_trn_request_approval_step_approver a ON a.trn_request_approval_step_uid = s.trn_request_approval_step_uid").
+			Where("s.ref_request_status_code = ? and s.step_status_code = '0' and a.approver_emp_id = ?", status, user.EmpID).
+			Count(&count)
+		if count > 0 && !Contains(user.Roles, role) {
+			user.Roles = append(user.Roles, role)
+		}
+	}
+}
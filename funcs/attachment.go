@@ -0,0 +1,134 @@
+package funcs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RequestAttachmentTypeOfficialLetter is the type the single doc_no/doc_file of a request is listed as
+const RequestAttachmentTypeOfficialLetter = 1
+
+// SetQueryAttachmentCanUpdate limits attachment changes to requests whose trip has not started
+func SetQueryAttachmentCanUpdate(query *gorm.DB) *gorm.DB {
+	return query.Where("ref_request_status_code in ('10','20','21','30','31','40','41','50','51') and is_deleted = '0'")
+}
+
+// GetRequestAttachments returns the attachments of a request, a document saved only in doc_file is listed first as an official letter
+func GetRequestAttachments(trnRequestUID string) []models.VmsTrnRequestAttachment {
+	attachments := []models.VmsTrnRequestAttachment{}
+	if err := config.DB.Preload("RefRequestAttachmentType").
+		Where("trn_request_uid = ? AND is_deleted = ?", trnRequestUID, "0").
+		Order("created_at").
+		Find(&attachments).Error; err != nil {
+		fmt.Println("Error getting attachments:", err)
+	}
+
+	var document models.VmsTrnRequestDocument
+	if err := config.DB.First(&document, "trn_request_uid = ?", trnRequestUID).Error; err != nil || document.DocFile == "" {
+		return attachments
+	}
+	for _, attachment := range attachments {
+		if attachment.FileURL == document.DocFile {
+			return attachments
+		}
+	}
+	legacy := models.VmsTrnRequestAttachment{
+		TrnRequestUID:                trnRequestUID,
+		RefRequestAttachmentTypeCode: RequestAttachmentTypeOfficialLetter,
+		DocNo:                        document.DocNo,
+		FileName:                     document.DocFileName,
+		FileURL:                      document.DocFile,
+		IsLegacyDocument:             true,
+	}
+	config.DB.First(&legacy.RefRequestAttachmentType, "ref_request_attachment_type_code = ?", RequestAttachmentTypeOfficialLetter)
+	return append([]models.VmsTrnRequestAttachment{legacy}, attachments...)
+}
+
+// GetAttachmentChangeValues lists the attachments of a request as the values recorded in the change log
+func GetAttachmentChangeValues(attachments []models.VmsTrnRequestAttachment) map[string]interface{} {
+	fileNames := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		fileNames = append(fileNames, attachment.FileName)
+	}
+	return map[string]interface{}{
+		"attachments": strings.Join(fileNames, ", "),
+	}
+}
+
+// AddRequestAttachment saves an attachment of a request, the first attachment also fills doc_no/doc_file when they are empty
+func AddRequestAttachment(input models.VmsTrnRequestAttachmentRequest, empID string) (models.VmsTrnRequestAttachment, error) {
+	var count int64
+	config.DB.Model(&models.VmsRefRequestAttachmentType{}).
+		Where("ref_request_attachment_type_code = ?", input.RefRequestAttachmentTypeCode).
+		Count(&count)
+	if count == 0 {
+		return models.VmsTrnRequestAttachment{}, messages.ErrAttachmentInvalid
+	}
+
+	attachment := models.VmsTrnRequestAttachment{
+		TrnRequestAttachmentUID:      uuid.New().String(),
+		TrnRequestUID:                input.TrnRequestUID,
+		RefRequestAttachmentTypeCode: input.RefRequestAttachmentTypeCode,
+		DocNo:                        input.DocNo,
+		FileName:                     input.FileName,
+		FileURL:                      input.FileURL,
+		CreatedAt:                    time.Now(),
+		CreatedBy:                    empID,
+		UpdatedAt:                    time.Now(),
+		UpdatedBy:                    empID,
+		IsDeleted:                    "0",
+	}
+	if err := config.DB.Create(&attachment).Error; err != nil {
+		return attachment, err
+	}
+	if err := config.DB.Model(&models.VmsTrnRequestDocument{}).
+		Where("trn_request_uid = ? and coalesce(doc_file, '') = ''", input.TrnRequestUID).
+		UpdateColumns(map[string]interface{}{
+			"doc_no":        attachment.DocNo,
+			"doc_file":      attachment.FileURL,
+			"doc_file_name": attachment.FileName,
+			"updated_by":    empID,
+			"updated_at":    time.Now(),
+		}).Error; err != nil {
+		return attachment, err
+	}
+	config.DB.First(&attachment.RefRequestAttachmentType, "ref_request_attachment_type_code = ?", attachment.RefRequestAttachmentTypeCode)
+	return attachment, nil
+}
+
+// DeleteRequestAttachment removes an attachment of a request, doc_no/doc_file pointing to it move to the next attachment
+func DeleteRequestAttachment(attachment models.VmsTrnRequestAttachment, empID string) error {
+	if err := config.DB.Model(&attachment).UpdateColumns(map[string]interface{}{
+		"is_deleted": "1",
+		"updated_by": empID,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+
+	var document models.VmsTrnRequestDocument
+	if err := config.DB.First(&document, "trn_request_uid = ?", attachment.TrnRequestUID).Error; err != nil || document.DocFile != attachment.FileURL {
+		return nil
+	}
+	var next models.VmsTrnRequestAttachment
+	config.DB.Where("trn_request_uid = ? AND is_deleted = ?", attachment.TrnRequestUID, "0").
+		Order("created_at").
+		Limit(1).
+		Find(&next)
+	return config.DB.Model(&models.VmsTrnRequestDocument{}).
+		Where("trn_request_uid = ?", attachment.TrnRequestUID).
+		UpdateColumns(map[string]interface{}{
+			"doc_no":        next.DocNo,
+			"doc_file":      next.FileURL,
+			"doc_file_name": next.FileName,
+			"updated_by":    empID,
+			"updated_at":    time.Now(),
+		}).Error
+}
@@ -0,0 +1,156 @@
+package funcs
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	AuditOperationCreate = "create"
+	AuditOperationUpdate = "update"
+	AuditOperationDelete = "delete"
+)
+
+// auditLogSkipColumns are bookkeeping columns, the actor and time of the change are on the audit entry itself,
+// and secrets that have no place in the log
+var auditLogSkipColumns = map[string]bool{
+	"created_at":   true,
+	"created_by":   true,
+	"updated_at":   true,
+	"updated_by":   true,
+	"key_hash":     true,
+	"last_used_at": true,
+}
+
+// GetRequestID returns the X-Request-ID of the call, or a new one echoed back to the client, shared by every audit entry of the call
+func GetRequestID(c *gin.Context) string {
+	if requestID := c.GetString("request_id"); requestID != "" {
+		return requestID
+	}
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	c.Set("request_id", requestID)
+	c.Header("X-Request-ID", requestID)
+	return requestID
+}
+
+// GetMasterDataSnapshot reads a master-data row with all of its columns, nil when there is no such row
+func GetMasterDataSnapshot(table, keyColumn, key string) map[string]interface{} {
+	if key == "" {
+		return nil
+	}
+	row := map[string]interface{}{}
+	if err := config.DB.Table(table).Where(keyColumn+" = ?", key).Take(&row).Error; err != nil {
+		return nil
+	}
+	return row
+}
+
+// GetMasterDataSnapshots reads the master-data rows where column is value, a list or a subquery, keyed by keyColumn
+func GetMasterDataSnapshots(table, keyColumn, column string, value interface{}) map[string]map[string]interface{} {
+	rows := []map[string]interface{}{}
+	snapshots := make(map[string]map[string]interface{})
+	if err := config.DB.Table(table).Where(column+" in (?)", value).Find(&rows).Error; err != nil {
+		log.Println("Error reading master data:", err)
+		return snapshots
+	}
+	for _, row := range rows {
+		snapshots[formatRequestChangeValue(row[keyColumn])] = row
+	}
+	return snapshots
+}
+
+// GetMasterDataOperation tells from the row read before and after a change whether it created, updated or deleted the row
+func GetMasterDataOperation(before, after map[string]interface{}) string {
+	if before == nil {
+		return AuditOperationCreate
+	}
+	if after == nil || (formatRequestChangeValue(after["is_deleted"]) == "1" && formatRequestChangeValue(before["is_deleted"]) != "1") {
+		return AuditOperationDelete
+	}
+	return AuditOperationUpdate
+}
+
+// AuditMasterDataRow records the change of one master-data row against the snapshot read before it, before is nil on create
+func AuditMasterDataRow(c *gin.Context, user *models.AuthenUserEmp, table, keyColumn, key string, before map[string]interface{}) {
+	after := GetMasterDataSnapshot(table, keyColumn, key)
+	CreateMasterDataAuditLog(c, user, table, key, GetMasterDataOperation(before, after), before, after)
+}
+
+// AuditMasterDataRows records the change of every row where column is value against the rows read by GetMasterDataSnapshots,
+// rows that appeared are recorded as created, rows that left are read again by their key
+func AuditMasterDataRows(c *gin.Context, user *models.AuthenUserEmp, table, keyColumn, column string, value interface{}, befores map[string]map[string]interface{}) {
+	afters := GetMasterDataSnapshots(table, keyColumn, column, value)
+	keys := make([]string, 0, len(befores)+len(afters))
+	for key := range befores {
+		keys = append(keys, key)
+	}
+	for key := range afters {
+		if _, ok := befores[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		after, ok := afters[key]
+		if !ok {
+			after = GetMasterDataSnapshot(table, keyColumn, key)
+		}
+		CreateMasterDataAuditLog(c, user, table, key, GetMasterDataOperation(befores[key], after), befores[key], after)
+	}
+}
+
+// CreateMasterDataAuditLog records a change of master data. before and after are model structs or update maps,
+// before is nil on create. Only the columns of after are compared; a call that changes nothing is not recorded.
+func CreateMasterDataAuditLog(c *gin.Context, user *models.AuthenUserEmp, entityType, entityUID, operation string, before, after interface{}) {
+	oldColumns, oldValues := GetModelColumnValues(before)
+	columns, newValues := GetModelColumnValues(after)
+	if len(columns) == 0 {
+		// the row is gone, every column it had is recorded as cleared
+		columns = oldColumns
+	}
+	fields := []models.VmsLogMasterDataAuditField{}
+	for _, column := range columns {
+		if auditLogSkipColumns[column] || oldValues[column] == newValues[column] {
+			continue
+		}
+		fields = append(fields, models.VmsLogMasterDataAuditField{
+			LogMasterDataAuditFieldUID: uuid.New().String(),
+			FieldName:                  column,
+			OldValue:                   oldValues[column],
+			NewValue:                   newValues[column],
+		})
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	audit := models.VmsLogMasterDataAudit{
+		LogMasterDataAuditUID: uuid.New().String(),
+		EntityType:            entityType,
+		EntityUID:             entityUID,
+		Operation:             operation,
+		ActionDatetime:        models.TimeWithZone{Time: time.Now()},
+		ActionByPersonalID:    user.EmpID,
+		ActionByFullname:      user.FullName,
+		ActionByRole:          strings.Join(user.Roles, ","),
+		IPAddress:             c.ClientIP(),
+		RequestID:             GetRequestID(c),
+		Fields:                fields,
+	}
+	for i := range audit.Fields {
+		audit.Fields[i].LogMasterDataAuditUID = audit.LogMasterDataAuditUID
+	}
+	if err := config.DB.Create(&audit).Error; err != nil {
+		log.Println("Error inserting audit log:", err)
+	}
+}
@@ -0,0 +1,239 @@
+package funcs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AutoApprovalStatusCodes are the statuses a rule can approve and the status the request moves to
+var AutoApprovalStatusCodes = map[string]string{
+	"30": "40",
+	"40": "50",
+}
+
+// IsLevelCodeAtLeast compares employee levels such as S7 or M2, management levels rank above staff levels
+func IsLevelCodeAtLeast(levelCode, minLevelCode string) bool {
+	if minLevelCode == "" {
+		return true
+	}
+	if len(levelCode) < 2 || len(minLevelCode) < 2 {
+		return false
+	}
+	if levelCode[:1] != minLevelCode[:1] {
+		return levelCode[:1] == "M"
+	}
+	level, err := strconv.Atoi(levelCode[1:])
+	if err != nil {
+		return false
+	}
+	minLevel, err := strconv.Atoi(minLevelCode[1:])
+	if err != nil {
+		return false
+	}
+	return level >= minLevel
+}
+
+// GetAutoApprovalRequestCheck loads the request fields the rule conditions are checked against
+func GetAutoApprovalRequestCheck(trnRequestUID string) (models.VmsTrnRequestAutoApprovalCheck, error) {
+	var request models.VmsTrnRequestAutoApprovalCheck
+	result := config.DB.Table("vms_trn_request r").
+		Select("r.trn_request_uid, r.ref_request_status_code, r.mas_carpool_uid, cp.carpool_main_business_area, r.vehicle_user_emp_id, r.vehicle_user_dept_sap, r.is_pea_employee_driver, r.ref_trip_type_code, r.reserve_start_datetime, r.reserve_end_datetime").
+		Joins("LEFT JOIN vms_mas_carpool cp ON cp.mas_carpool_uid = r.mas_carpool_uid").
+		Where("r.trn_request_uid = ? and r.is_deleted = '0'", trnRequestUID).
+		Scan(&request)
+	if result.Error != nil {
+		return request, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return request, messages.ErrNotfound
+	}
+	request.VehicleUserLevelCode = GetUserEmpInfo(request.VehicleUserEmpID).LevelCode
+	return request, nil
+}
+
+// EvaluateAutoApprovalRule checks every condition set on the rule against the request, an unset condition is left out
+func EvaluateAutoApprovalRule(rule models.VmsMasAutoApprovalRule, request models.VmsTrnRequestAutoApprovalCheck) []models.AutoApprovalConditionResult {
+	results := []models.AutoApprovalConditionResult{}
+	if rule.MaxDurationHours > 0 {
+		hours := request.ReserveEndDatetime.Sub(request.ReserveStartDatetime.Time).Hours()
+		results = append(results, models.AutoApprovalConditionResult{
+			Condition: "max_duration_hours",
+			Expected:  strconv.Itoa(rule.MaxDurationHours),
+			Actual:    strconv.FormatFloat(hours, 'f', 2, 64),
+			IsMatched: hours < float64(rule.MaxDurationHours),
+		})
+	}
+	if rule.IsSameBusinessArea == "1" {
+		businessArea := GetBusinessAreaCodeFromDeptSap(request.VehicleUserDeptSAP)
+		results = append(results, models.AutoApprovalConditionResult{
+			Condition: "is_same_business_area",
+			Expected:  request.CarpoolMainBusinessArea,
+			Actual:    businessArea,
+			IsMatched: businessArea != "" && businessArea == request.CarpoolMainBusinessArea,
+		})
+	}
+	if rule.IsNoDriver == "1" {
+		results = append(results, models.AutoApprovalConditionResult{
+			Condition: "is_no_driver",
+			Expected:  "1",
+			Actual:    request.IsPEAEmployeeDriver,
+			IsMatched: request.IsPEAEmployeeDriver == "1",
+		})
+	}
+	if rule.MinLevelCode != "" {
+		results = append(results, models.AutoApprovalConditionResult{
+			Condition: "min_level_code",
+			Expected:  rule.MinLevelCode,
+			Actual:    request.VehicleUserLevelCode,
+			IsMatched: IsLevelCodeAtLeast(request.VehicleUserLevelCode, rule.MinLevelCode),
+		})
+	}
+	if rule.RefTripTypeCode != nil {
+		actual := ""
+		if request.RefTripTypeCode != nil {
+			actual = strconv.Itoa(*request.RefTripTypeCode)
+		}
+		results = append(results, models.AutoApprovalConditionResult{
+			Condition: "ref_trip_type_code",
+			Expected:  strconv.Itoa(*rule.RefTripTypeCode),
+			Actual:    actual,
+			IsMatched: request.RefTripTypeCode != nil && *request.RefTripTypeCode == *rule.RefTripTypeCode,
+		})
+	}
+	return results
+}
+
+// IsAutoApprovalRuleMatched reports whether every checked condition matched
+func IsAutoApprovalRuleMatched(results []models.AutoApprovalConditionResult) bool {
+	for _, result := range results {
+		if !result.IsMatched {
+			return false
+		}
+	}
+	return true
+}
+
+// FindAutoApprovalRule returns the first active rule of the request's carpool that approves the status and matches the request
+func FindAutoApprovalRule(trnRequestUID, refRequestStatusCode string) *models.VmsMasAutoApprovalRule {
+	if _, ok := AutoApprovalStatusCodes[refRequestStatusCode]; !ok {
+		return nil
+	}
+	var rules []models.VmsMasAutoApprovalRule
+	if err := config.DB.
+		Where("mas_carpool_uid in (?) and is_auto_approve_"+refRequestStatusCode+" = '1' and is_active = '1' and is_deleted = '0'",
+			config.DB.Table("vms_trn_request").Select("mas_carpool_uid").Where("trn_request_uid = ? and ref_request_status_code = ?", trnRequestUID, refRequestStatusCode)).
+		Order("created_at").
+		Find(&rules).Error; err != nil || len(rules) == 0 {
+		return nil
+	}
+
+	request, err := GetAutoApprovalRequestCheck(trnRequestUID)
+	if err != nil {
+		return nil
+	}
+	for i := range rules {
+		if IsAutoApprovalRuleMatched(EvaluateAutoApprovalRule(rules[i], request)) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// CreateTrnRequestAutoApproval records the rule that moved the request on so the approval can be traced and reverted
+func CreateTrnRequestAutoApproval(trnRequestUID string, rule models.VmsMasAutoApprovalRule, fromStatusCode, toStatusCode string) {
+	autoApproval := models.VmsTrnRequestAutoApproval{
+		TrnRequestAutoApprovalUID: uuid.New().String(),
+		TrnRequestUID:             trnRequestUID,
+		MasAutoApprovalRuleUID:    rule.MasAutoApprovalRuleUID,
+		RuleName:                  rule.RuleName,
+		FromRequestStatusCode:     fromStatusCode,
+		ToRequestStatusCode:       toStatusCode,
+		ApprovedDatetime:          models.TimeWithZone{Time: time.Now()},
+		IsReverted:                "0",
+	}
+	if err := config.DB.Create(&autoApproval).Error; err != nil {
+		fmt.Println("Error creating auto approval:", err)
+	}
+	CreateTrnRequestEventLog(trnRequestUID, fromStatusCode, "อนุมัติอัตโนมัติตามกฎ "+rule.RuleName, "system", "system", "")
+}
+
+// RevertAutoApproval moves the request back to the status the rule approved, as long as it has not moved on since.
+// What the approval did on its way is undone with it: the approver recorded for a final approval, the notifications
+// of the status it moved to and the SLA tracking started for that status. role is the role the user reverts as.
+func RevertAutoApproval(user *models.AuthenUserEmp, role string, trnRequestAutoApprovalUID string) (models.VmsTrnRequestAutoApproval, error) {
+	var autoApproval models.VmsTrnRequestAutoApproval
+	if err := config.DB.First(&autoApproval, "trn_request_auto_approval_uid = ? and is_reverted = '0'", trnRequestAutoApprovalUID).Error; err != nil {
+		return autoApproval, messages.ErrNotfound
+	}
+
+	now := time.Now()
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		columns := map[string]interface{}{
+			"ref_request_status_code": autoApproval.FromRequestStatusCode,
+			"updated_at":              now,
+			"updated_by":              user.EmpID,
+		}
+		if autoApproval.ToRequestStatusCode == "50" {
+			columns["approved_request_emp_id"] = ""
+			columns["approved_request_emp_name"] = ""
+			columns["approved_request_dept_sap"] = ""
+			columns["approved_request_dept_name_short"] = ""
+			columns["approved_request_dept_name_full"] = ""
+			columns["approved_request_desk_phone"] = ""
+			columns["approved_request_mobile_phone"] = ""
+			columns["approved_request_position"] = ""
+			columns["approved_request_datetime"] = nil
+		}
+		result := tx.Table("vms_trn_request").
+			Where("trn_request_uid = ? and ref_request_status_code = ? and is_deleted = '0'", autoApproval.TrnRequestUID, autoApproval.ToRequestStatusCode).
+			UpdateColumns(columns)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update : %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: request is no longer in status %s", messages.ErrBookingCannotUpdate, autoApproval.ToRequestStatusCode)
+		}
+
+		if err := tx.Where("record_uid = ? and notify_type = ? and ref_request_status_code = ? and created_at >= ?",
+			autoApproval.TrnRequestUID, "request-booking", autoApproval.ToRequestStatusCode, autoApproval.ApprovedDatetime).
+			Delete(&models.Notification{}).Error; err != nil {
+			return fmt.Errorf("failed to delete notifications : %v", err)
+		}
+		if err := tx.Where("trn_request_uid = ? and ref_request_status_code = ? and status_start_datetime >= ?",
+			autoApproval.TrnRequestUID, autoApproval.ToRequestStatusCode, autoApproval.ApprovedDatetime).
+			Delete(&models.VmsTrnRequestSla{}).Error; err != nil {
+			return fmt.Errorf("failed to delete SLA tracking : %v", err)
+		}
+
+		if err := tx.Model(&autoApproval).UpdateColumns(map[string]interface{}{
+			"is_reverted":       "1",
+			"reverted_emp_id":   user.EmpID,
+			"reverted_datetime": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to update : %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return autoApproval, err
+	}
+	autoApproval.IsReverted = "1"
+	autoApproval.RevertedEmpID = user.EmpID
+	autoApproval.RevertedDatetime = models.TimeWithZone{Time: now}
+
+	CreateTrnRequestActionLog(autoApproval.TrnRequestUID,
+		autoApproval.FromRequestStatusCode,
+		"ยกเลิกการอนุมัติอัตโนมัติตามกฎ "+autoApproval.RuleName,
+		user.EmpID,
+		role,
+		"",
+	)
+	return autoApproval, nil
+}
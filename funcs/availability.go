@@ -0,0 +1,76 @@
+package funcs
+
+import (
+	"time"
+	"vms_plus_be/config"
+
+	"gorm.io/gorm"
+)
+
+// BusyInterval is a window in which a vehicle or driver is already taken
+type BusyInterval struct {
+	UID   string    `gorm:"column:uid"`
+	Start time.Time `gorm:"column:start_datetime"`
+	End   time.Time `gorm:"column:end_datetime"`
+}
+
+// IsIntervalFree checks that none of the busy intervals overlap the window
+func IsIntervalFree(intervals []BusyInterval, start, end time.Time) bool {
+	for _, interval := range intervals {
+		if interval.Start.Before(end) && start.Before(interval.End) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetVehicleBusyIntervals loads the reservations and the waitlist holds of other users overlapping the range, keyed by vehicle
+func GetVehicleBusyIntervals(masVehicleUIDs []string, from, to time.Time, empID string, excludeRequestUIDs ...string) (map[string][]BusyInterval, error) {
+	busy := make(map[string][]BusyInterval)
+	if len(masVehicleUIDs) == 0 {
+		return busy, nil
+	}
+	var intervals []BusyInterval
+	query := busyRequestQuery(from, to, excludeRequestUIDs).
+		Select("mas_vehicle_uid AS uid, reserve_start_datetime AS start_datetime, reserve_end_datetime AS end_datetime").
+		Where("mas_vehicle_uid IN (?)", masVehicleUIDs)
+	if err := query.Scan(&intervals).Error; err != nil {
+		return nil, err
+	}
+	var holds []BusyInterval
+	if err := config.DB.Table("vms_trn_waitlist").
+		Select("offered_mas_vehicle_uid AS uid, start_datetime, end_datetime").
+		Where("offered_mas_vehicle_uid IN (?) AND waitlist_status_code = ? AND is_deleted = ?", masVehicleUIDs, "1", "0").
+		Where("hold_expire_datetime > ? AND emp_id <> ?", time.Now(), empID).
+		Where("start_datetime < ? AND end_datetime > ?", to, from).
+		Scan(&holds).Error; err != nil {
+		return nil, err
+	}
+	for _, interval := range append(intervals, holds...) {
+		busy[interval.UID] = append(busy[interval.UID], interval)
+	}
+	return busy, nil
+}
+
+// GetDriverBusyIntervals loads the reservations of a carpool driver overlapping the range
+func GetDriverBusyIntervals(masCarpoolDriverUID string, from, to time.Time, excludeRequestUIDs ...string) ([]BusyInterval, error) {
+	var intervals []BusyInterval
+	query := busyRequestQuery(from, to, excludeRequestUIDs).
+		Select("mas_carpool_driver_uid AS uid, reserve_start_datetime AS start_datetime, reserve_end_datetime AS end_datetime").
+		Where("mas_carpool_driver_uid = ?", masCarpoolDriverUID)
+	if err := query.Scan(&intervals).Error; err != nil {
+		return nil, err
+	}
+	return intervals, nil
+}
+
+// busyRequestQuery selects the requests holding a vehicle or driver between submit and return
+func busyRequestQuery(from, to time.Time, excludeRequestUIDs []string) *gorm.DB {
+	query := config.DB.Table("vms_trn_request").
+		Where("is_deleted = ? AND ref_request_status_code >= ? AND ref_request_status_code < ?", "0", "20", "80").
+		Where("reserve_start_datetime < ? AND reserve_end_datetime > ?", to, from)
+	if len(excludeRequestUIDs) > 0 {
+		query = query.Where("trn_request_uid NOT IN (?)", excludeRequestUIDs)
+	}
+	return query
+}
@@ -0,0 +1,36 @@
+package funcs
+
+import (
+	"errors"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+)
+
+// RunBulkAction runs action for each distinct uid and collects the outcome of every item, so one failure does not stop the rest
+func RunBulkAction(uids []string, action func(uid string) error) ([]models.VmsTrnBulkResult, int) {
+	results := make([]models.VmsTrnBulkResult, 0, len(uids))
+	seen := make(map[string]bool)
+	success := 0
+	for _, uid := range uids {
+		if uid == "" || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		result := models.VmsTrnBulkResult{UID: uid, Success: true}
+		if err := action(uid); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			switch {
+			case errors.Is(err, messages.ErrBookingCannotUpdate), errors.Is(err, messages.ErrAnnualCannotUpdate),
+				errors.Is(err, messages.ErrBookingNotFound), errors.Is(err, messages.ErrInvalidRequest):
+				result.Message = err.Error()
+			default:
+				result.Message = messages.ErrInternalServer.Error()
+			}
+		} else {
+			success++
+		}
+		results = append(results, result)
+	}
+	return results, success
+}
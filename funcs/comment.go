@@ -0,0 +1,104 @@
+package funcs
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+	"vms_plus_be/userhub"
+
+	"github.com/google/uuid"
+)
+
+// GetRequestCommentParticipants returns the people taking part in a request: the requester, the vehicle user, the driver,
+// the approvers, the main admin of the carpool and everyone who has commented
+func GetRequestCommentParticipants(trnRequestUID string) (models.VmsTrnRequestCommentParticipantCheck, []models.VmsTrnRequestCommentParticipant, error) {
+	var request models.VmsTrnRequestCommentParticipantCheck
+	if err := config.DB.First(&request, "trn_request_uid = ? and is_deleted = '0'", trnRequestUID).Error; err != nil {
+		return request, nil, err
+	}
+
+	participants := []models.VmsTrnRequestCommentParticipant{}
+	add := func(empID, fullName, role string) {
+		if empID == "" {
+			return
+		}
+		for _, participant := range participants {
+			if participant.EmpID == empID {
+				return
+			}
+		}
+		participants = append(participants, models.VmsTrnRequestCommentParticipant{EmpID: empID, FullName: fullName, Role: role})
+	}
+
+	add(request.CreatedRequestEmpID, request.CreatedRequestEmpName, "vehicle-user")
+	add(request.VehicleUserEmpID, request.VehicleUserEmpName, "vehicle-user")
+	add(request.DriverEmpID, request.DriverEmpName, "driver")
+	add(request.ConfirmedRequestEmpID, request.ConfirmedRequestEmpName, "level1-approval")
+	if request.MasCarpoolUID != nil {
+		var admins []models.VmsMasCarpoolAdmin
+		config.DB.Where("mas_carpool_uid = ? and is_main_admin = '1' and is_active = '1' and is_deleted = '0'", request.MasCarpoolUID).Find(&admins)
+		for _, admin := range admins {
+			add(admin.AdminEmpNo, admin.AdminEmpName, "admin-department")
+		}
+		add(request.ApprovedRequestEmpID, request.ApprovedRequestEmpName, "approval-carpool")
+	} else {
+		add(request.ApprovedRequestEmpID, request.ApprovedRequestEmpName, "approval-department")
+	}
+
+	var commenters []models.VmsTrnRequestComment
+	config.DB.Select("comment_by_emp_id, comment_by_full_name, comment_by_role").
+		Where("trn_request_uid = ? and is_deleted = '0'", trnRequestUID).
+		Order("created_at").
+		Find(&commenters)
+	for _, commenter := range commenters {
+		add(commenter.CommentByEmpID, commenter.CommentByFullName, commenter.CommentByRole)
+	}
+	return request, participants, nil
+}
+
+// CreateRequestCommentNotification notifies the other participants of a new comment, mentioned participants get a mention title
+func CreateRequestCommentNotification(request models.VmsTrnRequestCommentParticipantCheck, participants []models.VmsTrnRequestCommentParticipant, comment models.VmsTrnRequestComment) {
+	commentText := comment.CommentText
+	if utf8.RuneCountInString(commentText) > 100 {
+		commentText = string([]rune(commentText)[:100]) + "..."
+	}
+	message := "คำขอ " + request.RequestNo + " " + comment.CommentByFullName + ": " + commentText
+
+	for _, participant := range participants {
+		if participant.EmpID == comment.CommentByEmpID {
+			continue
+		}
+		title := "มีความคิดเห็นใหม่ในคำขอใช้ยานพาหนะ"
+		for _, mention := range comment.Mentions {
+			if mention.EmpID == participant.EmpID {
+				title = "มีผู้กล่าวถึงคุณในความคิดเห็น"
+				break
+			}
+		}
+		notification := models.Notification{
+			TrnNotifyUID:         uuid.New().String(),
+			EmpID:                participant.EmpID,
+			Title:                title,
+			Message:              message,
+			RecordUID:            request.TrnRequestUID,
+			NotifyType:           "request-comment",
+			NotifyRole:           participant.Role,
+			RefRequestStatusCode: request.RefRequestStatusCode,
+			IsRead:               false,
+			CreatedAt:            time.Now(),
+		}
+		if err := config.DB.Create(&notification).Error; err != nil {
+			fmt.Println("Error creating notification:", err)
+			return
+		}
+		userInfo, err := userhub.GetUserInfo(participant.EmpID)
+		if err != nil {
+			fmt.Println("Error getting user info:", err)
+			continue
+		}
+		go SendNotificationWorkD(participant.EmpID, title, message, "", GetNotifyURL(notification), userInfo.DeptSAP, userInfo.DeptSAPShort)
+		go SendNotificationPEA(participant.EmpID, title+" "+message)
+	}
+}
@@ -0,0 +1,120 @@
+package funcs
+
+import (
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+)
+
+// DelegableRoles are the approver roles that can be handed to another employee for a period
+var DelegableRoles = []string{"level1-approval", "approval-department", "approval-carpool", "license-approval"}
+
+func getActiveDelegations(column, empID string) []models.VmsTrnDelegation {
+	var delegations []models.VmsTrnDelegation
+	now := time.Now()
+	if err := config.DB.
+		Where(column+" = ? and is_deleted = '0' and start_datetime <= ? and end_datetime > ?", empID, now, now).
+		Find(&delegations).Error; err != nil {
+		return nil
+	}
+	return delegations
+}
+
+func isDelegatedRole(delegation models.VmsTrnDelegation, role string) bool {
+	return Contains(strings.Split(delegation.DelegatedRoles, ","), role)
+}
+
+// CheckDelegatedRoles adds the roles delegated to the user that are active now, run it after every other role check
+// so DelegatedRoles holds only the roles the user has through delegation alone
+func CheckDelegatedRoles(user *models.AuthenUserEmp) {
+	for _, delegation := range getActiveDelegations("delegate_emp_id", user.EmpID) {
+		for _, role := range strings.Split(delegation.DelegatedRoles, ",") {
+			if role != "" && !Contains(user.Roles, role) {
+				user.Roles = append(user.Roles, role)
+				user.DelegatedRoles = append(user.DelegatedRoles, role)
+			}
+		}
+	}
+}
+
+// GetOwnRoles returns the roles the user holds in their own right, without the delegated ones
+func GetOwnRoles(user *models.AuthenUserEmp) []string {
+	roles := []string{}
+	for _, role := range user.Roles {
+		if !Contains(user.DelegatedRoles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GetDelegatorEmpIDs returns the employees who currently delegate the role to empID
+func GetDelegatorEmpIDs(empID, role string) []string {
+	var empIDs []string
+	for _, delegation := range getActiveDelegations("delegate_emp_id", empID) {
+		if isDelegatedRole(delegation, role) {
+			empIDs = append(empIDs, delegation.DelegatorEmpID)
+		}
+	}
+	return empIDs
+}
+
+// GetDelegateEmpIDs returns the employees who currently act for empID in the role
+func GetDelegateEmpIDs(empID, role string) []string {
+	var empIDs []string
+	for _, delegation := range getActiveDelegations("delegator_emp_id", empID) {
+		if isDelegatedRole(delegation, role) {
+			empIDs = append(empIDs, delegation.DelegateEmpID)
+		}
+	}
+	return empIDs
+}
+
+// GetApprovalEmpIDs returns the user and everyone the user acts for in the role, for scoping approver queries
+func GetApprovalEmpIDs(user *models.AuthenUserEmp, role string) []string {
+	return append([]string{user.EmpID}, GetDelegatorEmpIDs(user.EmpID, role)...)
+}
+
+// GetOnBehalfOfName returns the name of the assigned approver the actor stands in for, or "" when the actor is assigned
+func GetOnBehalfOfName(trnRequestUID, actorEmpID, actionByRole string) string {
+	if actionByRole != "level1-approval" && actionByRole != "approval-department" {
+		return ""
+	}
+	var request struct {
+		ConfirmedRequestEmpID string
+		ApprovedRequestEmpID  string
+		MasCarpoolUID         *string
+	}
+	if err := config.DB.Table("vms_trn_request").
+		Select("confirmed_request_emp_id, approved_request_emp_id, mas_carpool_uid").
+		Where("trn_request_uid = ?", trnRequestUID).
+		Take(&request).Error; err != nil {
+		return ""
+	}
+
+	var assignedEmpIDs []string
+	delegatedRole := actionByRole
+	switch actionByRole {
+	case "level1-approval":
+		assignedEmpIDs = []string{request.ConfirmedRequestEmpID}
+	case "approval-department":
+		if request.MasCarpoolUID == nil || *request.MasCarpoolUID == "" {
+			assignedEmpIDs = []string{request.ApprovedRequestEmpID}
+		} else {
+			delegatedRole = "approval-carpool"
+			config.DB.Model(&models.VmsMasCarpoolApprover{}).
+				Where("mas_carpool_uid = ? and is_deleted = '0' and is_active = '1'", request.MasCarpoolUID).
+				Pluck("approver_emp_no", &assignedEmpIDs)
+		}
+	}
+	if Contains(assignedEmpIDs, actorEmpID) {
+		return ""
+	}
+	for _, delegation := range getActiveDelegations("delegate_emp_id", actorEmpID) {
+		if isDelegatedRole(delegation, delegatedRole) && Contains(assignedEmpIDs, delegation.DelegatorEmpID) {
+			return delegation.DelegatorEmpName
+		}
+	}
+	return ""
+}
@@ -0,0 +1,213 @@
+package funcs
+
+import (
+	"fmt"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+	"vms_plus_be/userhub"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GenerateRequestNo returns the next request number of a business area, e.g. VZ68RA000001
+func GenerateRequestNo(businessArea string) (string, error) {
+	if businessArea == "" {
+		return "", fmt.Errorf("business area is required")
+	}
+	YY := time.Now().Year() + 543
+	BCode := businessArea[0:1]
+	var running int
+	if err := config.DB.Raw("SELECT nextval('vehicle_request_seq_' || lower(?))", BCode).Scan(&running).Error; err != nil {
+		return "", err
+	}
+	//'V' + BCode + 'YY' + 'RA' + Running 6 หลัก เช่น VZ68RA000001
+	return "V" + BCode + fmt.Sprintf("%02d", YY%100) + "RA" + fmt.Sprintf("%06d", running), nil
+}
+
+// ValidateRequestDraft lists the missing fields of each booking step so a draft can be completed step by step
+func ValidateRequestDraft(request models.VmsTrnRequestRequest) models.VmsTrnRequestDraftValidation {
+	validation := models.VmsTrnRequestDraftValidation{
+		TrnRequestUID: request.TrnRequestUID,
+		IsComplete:    true,
+	}
+	addStep := func(step string, missingFields []string) {
+		if missingFields == nil {
+			missingFields = []string{}
+		}
+		validation.Steps = append(validation.Steps, models.VmsTrnRequestDraftStep{
+			Step:          step,
+			IsComplete:    len(missingFields) == 0,
+			MissingFields: missingFields,
+		})
+		if len(missingFields) > 0 {
+			validation.IsComplete = false
+		}
+	}
+
+	//Step1
+	var missing []string
+	if request.VehicleUserEmpID == "" {
+		missing = append(missing, "vehicle_user_emp_id")
+	}
+	addStep("vehicle_user", missing)
+
+	missing = nil
+	if request.ReserveStartDatetime.IsZero() {
+		missing = append(missing, "start_datetime")
+	}
+	if request.ReserveEndDatetime.IsZero() || !request.ReserveEndDatetime.After(request.ReserveStartDatetime.Time) {
+		missing = append(missing, "end_datetime")
+	}
+	if request.WorkPlace == "" {
+		missing = append(missing, "work_place")
+	}
+	if request.WorkDescription == "" {
+		missing = append(missing, "work_description")
+	}
+	if request.NumberOfPassengers <= 0 {
+		missing = append(missing, "number_of_passengers")
+	}
+	addStep("trip", missing)
+
+	missing = nil
+	if request.RefCostTypeCode == 0 {
+		missing = append(missing, "ref_cost_type_code")
+	}
+	addStep("cost", missing)
+
+	//Step 2
+	missing = nil
+	if (request.MasCarpoolUID == nil || *request.MasCarpoolUID == "") &&
+		(request.MasVehicleUID == nil || *request.MasVehicleUID == "") &&
+		request.RequestedVehicleType == "" {
+		missing = append(missing, "mas_vehicle_uid")
+	}
+	addStep("vehicle", missing)
+
+	//Step 3
+	missing = nil
+	if request.IsPEAEmployeeDriver == "1" && request.DriverEmpID == "" {
+		missing = append(missing, "driver_emp_id")
+	}
+	addStep("driver", missing)
+
+	//Step 4
+	missing = nil
+	if request.ConfirmedRequestEmpID == "" {
+		missing = append(missing, "confirmed_request_emp_id")
+	}
+	addStep("confirmer", missing)
+
+	return validation
+}
+
+// RevalidateRequestCopy checks a request copied to new dates and clears the vehicle, driver or people that can no longer be used
+func RevalidateRequestCopy(request *models.VmsTrnRequestRequest) []string {
+	clearedFields := []string{}
+	isEmployed := func(empID string) bool {
+		empUser, err := userhub.GetUserInfo(empID)
+		return err == nil && empUser.EmpID != ""
+	}
+
+	if request.VehicleUserEmpID != "" && !isEmployed(request.VehicleUserEmpID) {
+		request.VehicleUserEmpID = ""
+		clearedFields = append(clearedFields, "vehicle_user_emp_id")
+	}
+	if request.ConfirmedRequestEmpID != "" && !isEmployed(request.ConfirmedRequestEmpID) {
+		request.ConfirmedRequestEmpID = ""
+		clearedFields = append(clearedFields, "confirmed_request_emp_id")
+	}
+	if request.IsPEAEmployeeDriver == "1" && request.DriverEmpID != "" && !isEmployed(request.DriverEmpID) {
+		request.DriverEmpID = ""
+		clearedFields = append(clearedFields, "driver_emp_id")
+	}
+
+	if request.ReserveStartDatetime.IsZero() || !request.ReserveEndDatetime.After(request.ReserveStartDatetime.Time) {
+		return clearedFields
+	}
+	vehicleUser := GetUserEmpInfo(request.VehicleUserEmpID)
+	if request.MasVehicleUID != nil && *request.MasVehicleUID != "" {
+		var count int64
+		if err := config.DB.Table("fn_get_available_vehicles_view (?, ?, ?, ?) av",
+			request.ReserveStartDatetime, request.ReserveEndDatetime, vehicleUser.BureauDeptSap, vehicleUser.BusinessArea).
+			Where("av.mas_vehicle_uid = ?", request.MasVehicleUID).
+			Count(&count).Error; err != nil || count == 0 ||
+			IsVehicleOnHold(*request.MasVehicleUID, request.ReserveStartDatetime.Time, request.ReserveEndDatetime.Time, request.CreatedRequestEmpID) {
+			request.MasVehicleUID = nil
+			request.MasVehicleDepartmentUID = nil
+			clearedFields = append(clearedFields, "mas_vehicle_uid")
+		}
+	}
+	if request.MasCarPoolDriverUID != nil && *request.MasCarPoolDriverUID != "" {
+		var count int64
+		if err := config.DB.Table("fn_get_available_drivers_view (?, ?, ?, ?, ?) ad",
+			request.ReserveStartDatetime, request.ReserveEndDatetime, vehicleUser.BureauDeptSap, vehicleUser.BusinessArea, request.RefTripTypeCode).
+			Where("ad.mas_driver_uid = ?", request.MasCarPoolDriverUID).
+			Count(&count).Error; err != nil || count == 0 {
+			request.MasCarPoolDriverUID = nil
+			request.DriverEmpID = ""
+			request.DriverEmpName = ""
+			clearedFields = append(clearedFields, "mas_carpool_driver_uid")
+		}
+	}
+	return clearedFields
+}
+
+// DeleteRequestDrafts soft-deletes the drafts together with their passengers and attachments
+func DeleteRequestDrafts(trnRequestUIDs []string, deletedBy string) error {
+	if len(trnRequestUIDs) == 0 {
+		return nil
+	}
+	columns := map[string]interface{}{
+		"is_deleted": "1",
+		"updated_by": deletedBy,
+		"updated_at": time.Now(),
+	}
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		//delete the drafts first so a request submitted in the meantime keeps its passengers and attachments
+		var drafts []models.VmsTrnRequestDraft
+		if err := tx.Model(&drafts).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "trn_request_uid"}}}).
+			Where("trn_request_uid in (?) AND ref_request_status_code = ? AND is_deleted = ?", trnRequestUIDs, "10", "0").
+			UpdateColumns(columns).Error; err != nil {
+			return err
+		}
+		if len(drafts) == 0 {
+			return nil
+		}
+		deletedUIDs := make([]string, 0, len(drafts))
+		for _, draft := range drafts {
+			deletedUIDs = append(deletedUIDs, draft.TrnRequestUID)
+		}
+		if err := tx.Model(&models.VmsTrnRequestPassenger{}).
+			Where("trn_request_uid in (?) AND is_deleted = ?", deletedUIDs, "0").
+			UpdateColumns(columns).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.VmsTrnRequestAttachment{}).
+			Where("trn_request_uid in (?) AND is_deleted = ?", deletedUIDs, "0").
+			UpdateColumns(columns).Error
+	})
+}
+
+// JobPurgeDrafts soft-deletes draft requests that were not touched for config.AppConfig.DraftPurgeDays days
+func JobPurgeDrafts() {
+	if config.AppConfig.DraftPurgeDays <= 0 {
+		return
+	}
+	expired := time.Now().AddDate(0, 0, -config.AppConfig.DraftPurgeDays)
+	var trnRequestUIDs []string
+	if err := config.DB.Model(&models.VmsTrnRequestDraft{}).
+		Where("ref_request_status_code = ? AND is_deleted = ? AND updated_at < ?", "10", "0", expired).
+		Pluck("trn_request_uid", &trnRequestUIDs).Error; err != nil {
+		fmt.Println("Error getting expired drafts:", err)
+		return
+	}
+	if err := DeleteRequestDrafts(trnRequestUIDs, "system"); err != nil {
+		fmt.Println("Error purging drafts:", err)
+		return
+	}
+	fmt.Println("Purged drafts:", len(trnRequestUIDs))
+}
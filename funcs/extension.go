@@ -0,0 +1,228 @@
+package funcs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+	"vms_plus_be/userhub"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ExtensionStatusNameMap = map[string]string{
+	"0": "รออนุมัติ",
+	"1": "อนุมัติแล้ว",
+	"2": "ไม่อนุมัติ",
+}
+
+// CheckExtensionAvailable checks that the vehicle and carpool driver of a request are still free from the current reserve end to endDatetime
+func CheckExtensionAvailable(reservation models.VmsTrnRequestReservation, endDatetime time.Time) (bool, error) {
+	startDatetime := reservation.ReserveEndDatetime.Time
+	vehicleUser := GetUserEmpInfo(reservation.VehicleUserEmpID)
+
+	if reservation.MasVehicleUID != nil && *reservation.MasVehicleUID != "" {
+		if IsVehicleOnHold(*reservation.MasVehicleUID, startDatetime, endDatetime, reservation.CreatedRequestEmpID) {
+			return false, nil
+		}
+		var count int64
+		if err := config.DB.Table("fn_get_available_vehicles_view (?, ?, ?, ?) av",
+			models.TimeWithZone{Time: startDatetime}, models.TimeWithZone{Time: endDatetime}, vehicleUser.BureauDeptSap, vehicleUser.BusinessArea).
+			Where("av.mas_vehicle_uid = ?", reservation.MasVehicleUID).
+			Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count == 0 {
+			return false, nil
+		}
+	}
+
+	if reservation.MasCarPoolDriverUID != nil && *reservation.MasCarPoolDriverUID != "" {
+		var count int64
+		if err := config.DB.Table("fn_get_available_drivers_view (?, ?, ?, ?, ?) ad",
+			models.TimeWithZone{Time: startDatetime}, models.TimeWithZone{Time: endDatetime}, vehicleUser.BureauDeptSap, vehicleUser.BusinessArea, reservation.RefTripTypeCode).
+			Where("ad.mas_driver_uid = ?", reservation.MasCarPoolDriverUID).
+			Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// IsExtensionAutoApprove checks the carpool rule that allows short extensions without the carpool admin
+func IsExtensionAutoApprove(reservation models.VmsTrnRequestReservation, endDatetime time.Time) bool {
+	if reservation.MasCarpoolUID == nil || *reservation.MasCarpoolUID == "" {
+		return false
+	}
+	var autoApproveMinutes int
+	if err := config.DB.Table("vms_mas_carpool").
+		Where("mas_carpool_uid = ? AND is_deleted = '0'", reservation.MasCarpoolUID).
+		Select("coalesce(extension_auto_approve_minutes, 0)").
+		Scan(&autoApproveMinutes).Error; err != nil {
+		return false
+	}
+	extraMinutes := endDatetime.Sub(reservation.ReserveEndDatetime.Time).Minutes()
+	return autoApproveMinutes > 0 && extraMinutes <= float64(autoApproveMinutes)
+}
+
+// ApplyRequestExtension moves the reserve end of the request and approves the extension.
+// It returns messages.ErrExtensionChanged when the extension was already decided or the trip is no longer active.
+func ApplyRequestExtension(extension *models.VmsTrnRequestExtension, empID, role string) error {
+	actionDetail := "ขยายเวลาการใช้ยานพาหนะ"
+	if extension.IsAutoApproved == "1" {
+		actionDetail = "ขยายเวลาการใช้ยานพาหนะ (อนุมัติอัตโนมัติ)"
+	} else {
+		approvedUser := GetUserEmpInfo(empID)
+		extension.ApprovedEmpID = approvedUser.EmpID
+		extension.ApprovedEmpName = approvedUser.FullName
+	}
+	extension.ExtensionStatusCode = "1"
+	extension.ApprovedDatetime = models.TimeWithZone{Time: time.Now()}
+	extension.UpdatedAt = time.Now()
+	extension.UpdatedBy = empID
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.VmsTrnRequestExtension{}).
+			Where("trn_request_extension_uid = ? AND extension_status_code = ?", extension.TrnRequestExtensionUID, "0").
+			UpdateColumns(map[string]interface{}{
+				"extension_status_code": extension.ExtensionStatusCode,
+				"is_auto_approved":      extension.IsAutoApproved,
+				"approved_emp_id":       extension.ApprovedEmpID,
+				"approved_emp_name":     extension.ApprovedEmpName,
+				"approved_datetime":     extension.ApprovedDatetime,
+				"updated_at":            extension.UpdatedAt,
+				"updated_by":            extension.UpdatedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return messages.ErrExtensionChanged
+		}
+		result = tx.Model(&models.VmsTrnRequestReservation{}).
+			Where("trn_request_uid = ? AND ref_request_status_code = ?", extension.TrnRequestUID, "60").
+			UpdateColumns(map[string]interface{}{
+				"reserve_end_datetime": extension.RequestedEndDatetime,
+				"updated_at":           time.Now(),
+				"updated_by":           empID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return messages.ErrExtensionChanged
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	CreateTrnRequestChangeLog(extension.TrnRequestUID, "extension",
+		map[string]interface{}{"reserve_end_datetime": extension.OriginalEndDatetime},
+		map[string]interface{}{"reserve_end_datetime": extension.RequestedEndDatetime},
+		empID, role)
+	CreateTrnRequestEventLog(extension.TrnRequestUID,
+		"60",
+		actionDetail,
+		empID,
+		role,
+		GetDateTimeBuddhistYear(extension.OriginalEndDatetime.Time)+" - "+GetDateTimeBuddhistYear(extension.RequestedEndDatetime.Time)+" "+extension.ExtensionReason,
+	)
+	CreateRequestExtensionNotification(extension.TrnRequestExtensionUID)
+	return nil
+}
+
+// ReleaseRemainingReservation ends the reservation of an early returned request now so the rest of the window can be booked
+func ReleaseRemainingReservation(trnRequestUID, empID, role string) {
+	var reservation models.VmsTrnRequestReservation
+	if err := config.DB.First(&reservation, "trn_request_uid = ?", trnRequestUID).Error; err != nil {
+		fmt.Println("Error getting reservation:", err)
+		return
+	}
+	now := time.Now()
+	if !now.Before(reservation.ReserveEndDatetime.Time) {
+		return
+	}
+	if err := config.DB.Model(&models.VmsTrnRequestReservation{}).
+		Where("trn_request_uid = ?", trnRequestUID).
+		Updates(map[string]interface{}{
+			"original_reserve_end_datetime": reservation.ReserveEndDatetime,
+			"reserve_end_datetime":          models.TimeWithZone{Time: now},
+		}).Error; err != nil {
+		fmt.Println("Error releasing reservation:", err)
+		return
+	}
+	CreateTrnRequestEventLog(trnRequestUID,
+		reservation.RefRequestStatusCode,
+		"คืนยานพาหนะก่อนกำหนด",
+		empID,
+		role,
+		GetDateTimeBuddhistYear(now)+" - "+GetDateTimeBuddhistYear(reservation.ReserveEndDatetime.Time),
+	)
+}
+
+func CreateRequestExtensionNotification(trnRequestExtensionUID string) {
+	var extension models.VmsTrnRequestExtension
+	if err := config.DB.Where("trn_request_extension_uid = ?", trnRequestExtensionUID).First(&extension).Error; err != nil {
+		fmt.Println("Error getting request extension:", err)
+		return
+	}
+	var request models.RequestBookingNotification
+	if err := config.DB.Where("trn_request_uid = ?", extension.TrnRequestUID).First(&request).Error; err != nil {
+		fmt.Println("Error getting request booking:", err)
+		return
+	}
+
+	var notifyTemplates []models.NotificationTemplate
+	if err := config.DB.Where("ref_request_status_code = ? AND is_deleted = false AND notify_type = 'request-extension'", extension.ExtensionStatusCode).Find(&notifyTemplates).Error; err != nil {
+		fmt.Println("Error getting notify templates:", err)
+		return
+	}
+
+	for _, notifyTemplate := range notifyTemplates {
+		var notifyEmpIDs []string
+		notifyMessage := notifyTemplate.NotifyMessage
+		notifyMessage = strings.Replace(notifyMessage, "**request_no**", request.RequestNo, -1)
+		notifyMessage = strings.Replace(notifyMessage, "**end_datetime**", GetDateTimeBuddhistYear(extension.RequestedEndDatetime.Time), -1)
+		switch notifyTemplate.NotifyRole {
+		case "vehicle-user":
+			notifyEmpIDs = []string{extension.RequestedEmpID}
+		case "admin-department":
+			notifyEmpIDs, _ = GetAdminApprovalEmpIDs(extension.TrnRequestUID)
+		}
+		for _, notifyEmpID := range notifyEmpIDs {
+			if notifyEmpID == "" {
+				continue
+			}
+			notification := models.Notification{
+				TrnNotifyUID:         uuid.New().String(),
+				EmpID:                notifyEmpID,
+				Title:                notifyTemplate.NotifyTitle,
+				Message:              notifyMessage,
+				RecordUID:            extension.TrnRequestUID,
+				NotifyType:           notifyTemplate.NotifyType,
+				NotifyRole:           notifyTemplate.NotifyRole,
+				RefRequestStatusCode: extension.ExtensionStatusCode,
+				IsRead:               false,
+				CreatedAt:            time.Now(),
+			}
+			if err := config.DB.Create(&notification).Error; err != nil {
+				fmt.Println("Error creating notification:", err)
+				return
+			}
+			userInfo, err := userhub.GetUserInfo(notifyEmpID)
+			if err != nil {
+				fmt.Println("Error getting user info:", err)
+				continue
+			}
+			go SendNotificationWorkD(notifyEmpID, notifyTemplate.NotifyTitle, notifyMessage, "", GetNotifyURL(notification), userInfo.DeptSAP, userInfo.DeptSAPShort)
+			go SendNotificationPEA(notifyEmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+			go SendNotificationSMS(notifyEmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package funcs
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Audit log operations of impersonation, entries are recorded against entity_type impersonation and the session uid
+const (
+	ImpersonationEntityType     = "impersonation"
+	ImpersonationOperationStart = "start"
+	ImpersonationOperationStop  = "stop"
+	ImpersonationOperationCall  = "call"
+	ImpersonationOperationBlock = "blocked"
+)
+
+// impersonationAllowedWrites are the only calls other than GET an impersonated token may make, as method and route path
+var impersonationAllowedWrites = []string{
+	http.MethodPost + " /api/impersonation/stop",
+}
+
+// impersonationBlockedGroups administer access itself and are refused to every impersonation, reads included
+var impersonationBlockedGroups = []string{"api-client", "session-admin", "region-admin"}
+
+// impersonationProtectedRoles cannot be impersonated, whether held directly or through a delegation
+var impersonationProtectedRoles = []string{"support"}
+
+// IsImpersonationTargetAllowed tells whether the employee may be impersonated, an admin or support user may not
+func IsImpersonationTargetAllowed(target models.AuthenUserEmp) bool {
+	target.Roles = append([]string{}, target.Roles...)
+	CheckAllRoles(&target)
+	for _, role := range target.Roles {
+		if strings.HasPrefix(role, "admin-") || Contains(impersonationProtectedRoles, role) {
+			return false
+		}
+	}
+	return true
+}
+
+// StartImpersonation issues a token for the support user to act as the target until the duration ends.
+// The token belongs to a session of the target marked with the impersonator, so it can be revoked like any other.
+func StartImpersonation(c *gin.Context, impersonator *models.AuthenUserEmp, target models.AuthenUserEmp, reason string, duration time.Duration) (models.ImpersonationResponse, error) {
+	now := time.Now()
+	session := models.VmsTrnSession{
+		SessionUID:        uuid.New().String(),
+		EmpID:             target.EmpID,
+		LoginBy:           "impersonation",
+		Device:            c.Request.UserAgent(),
+		IPAddress:         c.ClientIP(),
+		CreatedAt:         now,
+		LastUsedAt:        now,
+		ExpiresAt:         now.Add(duration),
+		IsRevoked:         "0",
+		ImpersonatorEmpID: impersonator.EmpID,
+	}
+	if err := config.DB.Create(&session).Error; err != nil {
+		return models.ImpersonationResponse{}, err
+	}
+
+	token := models.VmsTrnSessionToken{
+		Jti:        uuid.New().String(),
+		SessionUID: session.SessionUID,
+		TokenType:  "access",
+		IssuedAt:   now,
+		ExpiresAt:  session.ExpiresAt,
+		IsUsed:     "0",
+	}
+	target.LoginBy = "impersonation"
+	target.SessionUID = session.SessionUID
+	target.ImpersonatorEmpID = impersonator.EmpID
+	target.ImpersonatorFullName = impersonator.FullName
+	accessToken, err := GenerateJWT(target, "access", duration, token.Jti)
+	if err != nil {
+		return models.ImpersonationResponse{}, err
+	}
+	if err := config.DB.Create(&token).Error; err != nil {
+		return models.ImpersonationResponse{}, err
+	}
+
+	CreateMasterDataAuditLog(c, impersonator, ImpersonationEntityType, session.SessionUID, ImpersonationOperationStart, nil, map[string]interface{}{
+		"impersonated_emp_id": target.EmpID,
+		"reason":              reason,
+		"expires_at":          session.ExpiresAt,
+	})
+	return models.ImpersonationResponse{
+		AccessToken: accessToken,
+		SessionUID:  session.SessionUID,
+		ExpiresAt:   session.ExpiresAt,
+	}, nil
+}
+
+// StopImpersonation revokes the impersonation session the user is calling with
+func StopImpersonation(c *gin.Context, user *models.AuthenUserEmp) error {
+	if err := RevokeSession(user.SessionUID, SessionRevokedLogout, user.ImpersonatorEmpID); err != nil {
+		return err
+	}
+	CreateMasterDataAuditLog(c, getImpersonator(user), ImpersonationEntityType, user.SessionUID, ImpersonationOperationStop, nil, map[string]interface{}{
+		"impersonated_emp_id": user.EmpID,
+	})
+	return nil
+}
+
+// getImpersonator is the support user behind an impersonated call, the actor of its audit entries
+func getImpersonator(user *models.AuthenUserEmp) *models.AuthenUserEmp {
+	return &models.AuthenUserEmp{
+		EmpID:    user.ImpersonatorEmpID,
+		FullName: user.ImpersonatorFullName,
+		Roles:    []string{"impersonating:" + user.EmpID},
+	}
+}
+
+// IsImpersonationBlocked tells whether an impersonated user may not call the route, every write is refused unless allowlisted
+func IsImpersonationBlocked(c *gin.Context) bool {
+	if Contains(impersonationBlockedGroups, GetRouteGroup(c)) {
+		return true
+	}
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		return false
+	}
+	return !Contains(impersonationAllowedWrites, c.Request.Method+" "+c.FullPath())
+}
+
+// CheckImpersonatedCall refuses the writes of an impersonated token and records every other call once it is handled
+func CheckImpersonatedCall(c *gin.Context, claims jwt.MapClaims) {
+	impersonatorEmpID, _ := claims["imp_emp_id"].(string)
+	if impersonatorEmpID == "" {
+		c.Next()
+		return
+	}
+	user := &models.AuthenUserEmp{IsImpersonated: true, ImpersonatorEmpID: impersonatorEmpID}
+	user.EmpID, _ = claims["emp_id"].(string)
+	user.SessionUID, _ = claims["sid"].(string)
+	user.ImpersonatorFullName, _ = claims["imp_full_name"].(string)
+
+	if IsImpersonationBlocked(c) {
+		auditImpersonatedCall(c, user, ImpersonationOperationBlock, http.StatusForbidden)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed while impersonating", "message": messages.ErrImpersonateBlocked.Error()})
+		c.Abort()
+		return
+	}
+	c.Next()
+	auditImpersonatedCall(c, user, ImpersonationOperationCall, c.Writer.Status())
+}
+
+func auditImpersonatedCall(c *gin.Context, user *models.AuthenUserEmp, operation string, status int) {
+	CreateMasterDataAuditLog(c, getImpersonator(user), ImpersonationEntityType, user.SessionUID, operation, nil, map[string]interface{}{
+		"impersonated_emp_id": user.EmpID,
+		"method":              c.Request.Method,
+		"path":                c.Request.URL.RequestURI(),
+		"status":              fmt.Sprint(status),
+	})
+}
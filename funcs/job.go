@@ -2,6 +2,7 @@ package funcs
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -9,6 +10,11 @@ import (
 
 func InitCronJob() {
 	RunCronJobDriverCheckActive()
+	RunCronJobWaitlistExpireHolds()
+	RunCronJobPurgeDrafts()
+	RunCronJobCheckRequestSla()
+	RunCronJobFlushApiClientUsage()
+	RunCronJobReloadApiClients()
 }
 
 func RunCronJobDriverCheckActive() {
@@ -22,3 +28,63 @@ func RunCronJobDriverCheckActive() {
 
 	c.Start()
 }
+
+func RunCronJobWaitlistExpireHolds() {
+	c := cron.New()
+
+	// Schedule to run every minute
+	c.AddFunc("* * * * *", func() {
+		JobWaitlistExpireHolds()
+	})
+
+	c.Start()
+}
+
+func RunCronJobPurgeDrafts() {
+	c := cron.New()
+
+	// Schedule to run every day at 01:00
+	c.AddFunc("0 1 * * *", func() {
+		JobPurgeDrafts()
+	})
+
+	c.Start()
+}
+
+func RunCronJobCheckRequestSla() {
+	c := cron.New()
+
+	// Schedule to run every 15 minutes
+	c.AddFunc("*/15 * * * *", func() {
+		JobCheckRequestSla()
+	})
+
+	c.Start()
+}
+
+func RunCronJobFlushApiClientUsage() {
+	c := cron.New()
+
+	// Schedule to run every minute
+	c.AddFunc("* * * * *", func() {
+		FlushApiClientUsage()
+	})
+
+	c.Start()
+}
+
+func RunCronJobReloadApiClients() {
+	if err := ReloadApiClients(); err != nil {
+		log.Println("Error loading API clients:", err)
+	}
+	c := cron.New()
+
+	// Schedule to run every minute
+	c.AddFunc("* * * * *", func() {
+		if err := ReloadApiClients(); err != nil {
+			log.Println("Error loading API clients:", err)
+		}
+	})
+
+	c.Start()
+}
@@ -0,0 +1,221 @@
+package funcs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtKey is a key tokens are signed or verified with, a key loaded from a public key file only verifies
+type jwtKey struct {
+	Kid        string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+var (
+	jwtKeys       = map[string]*jwtKey{}
+	jwtKeyIDs     []string
+	jwtSigningKey *jwtKey
+)
+
+// InitJWTKeys loads the keys listed in JWT_KEY_FILES as kid=path pairs and the PEM key in JWT_PRIVATE_KEY named by JWT_KEY_ID.
+// Tokens are signed with the key named by JWT_SIGNING_KEY_ID, or the first private key. Keep a rotated out key listed,
+// as a public key file is enough, until the tokens it signed have expired.
+// Without any key tokens are signed HS256 with JWT_SECRET as before.
+func InitJWTKeys() error {
+	jwtKeys = map[string]*jwtKey{}
+	jwtKeyIDs = nil
+	jwtSigningKey = nil
+
+	for _, item := range strings.Split(config.AppConfig.JwtKeyFiles, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(item, "=")
+		if !ok || kid == "" || path == "" {
+			return fmt.Errorf("JWT_KEY_FILES: %q is not kid=path", item)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("JWT_KEY_FILES: %w", err)
+		}
+		if err := addJWTKey(kid, data); err != nil {
+			return err
+		}
+	}
+	if config.AppConfig.JwtPrivateKey != "" {
+		if config.AppConfig.JwtKeyID == "" {
+			return errors.New("JWT_KEY_ID is required with JWT_PRIVATE_KEY")
+		}
+		// env files often carry the PEM on one line with escaped newlines
+		data := strings.ReplaceAll(config.AppConfig.JwtPrivateKey, `\n`, "\n")
+		if err := addJWTKey(config.AppConfig.JwtKeyID, []byte(data)); err != nil {
+			return err
+		}
+	}
+
+	if kid := config.AppConfig.JwtSigningKeyID; kid != "" {
+		key, ok := jwtKeys[kid]
+		if !ok || key.PrivateKey == nil {
+			return fmt.Errorf("JWT_SIGNING_KEY_ID: no private key %q", kid)
+		}
+		jwtSigningKey = key
+		return nil
+	}
+	for _, kid := range jwtKeyIDs {
+		if jwtKeys[kid].PrivateKey != nil {
+			jwtSigningKey = jwtKeys[kid]
+			break
+		}
+	}
+	if len(jwtKeys) > 0 && jwtSigningKey == nil {
+		return errors.New("JWT_KEY_FILES: no private key to sign tokens with")
+	}
+	return nil
+}
+
+func addJWTKey(kid string, data []byte) error {
+	if _, ok := jwtKeys[kid]; ok {
+		return fmt.Errorf("JWT key %q is listed twice", kid)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("JWT key %q is not PEM", kid)
+	}
+
+	key := &jwtKey{Kid: kid}
+	switch block.Type {
+	case "PUBLIC KEY":
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("JWT key %q: %w", kid, err)
+		}
+		key.PublicKey = publicKey
+	case "RSA PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("JWT key %q: %w", kid, err)
+		}
+		key.PrivateKey = privateKey
+	case "EC PRIVATE KEY":
+		privateKey, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("JWT key %q: %w", kid, err)
+		}
+		key.PrivateKey = privateKey
+	case "PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("JWT key %q: %w", kid, err)
+		}
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("JWT key %q: unsupported key type", kid)
+		}
+		key.PrivateKey = signer
+	default:
+		return fmt.Errorf("JWT key %q: unsupported PEM block %s", kid, block.Type)
+	}
+	if key.PrivateKey != nil {
+		key.PublicKey = key.PrivateKey.Public()
+	}
+
+	switch publicKey := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch publicKey.Curve {
+		case elliptic.P256():
+			key.Method = jwt.SigningMethodES256
+		case elliptic.P384():
+			key.Method = jwt.SigningMethodES384
+		case elliptic.P521():
+			key.Method = jwt.SigningMethodES512
+		default:
+			return fmt.Errorf("JWT key %q: unsupported curve", kid)
+		}
+	default:
+		return fmt.Errorf("JWT key %q: only RSA and ECDSA keys are supported", kid)
+	}
+
+	jwtKeys[kid] = key
+	jwtKeyIDs = append(jwtKeyIDs, kid)
+	return nil
+}
+
+// SignJWT signs the claims with the active key and names it in the kid header
+func SignJWT(claims jwt.Claims) (string, error) {
+	if jwtSigningKey == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.AppConfig.JWTSecret))
+	}
+	token := jwt.NewWithClaims(jwtSigningKey.Method, claims)
+	token.Header["kid"] = jwtSigningKey.Kid
+	return token.SignedString(jwtSigningKey.PrivateKey)
+}
+
+// ParseJWT verifies the token against the key its kid header names, HS256 tokens are accepted only
+// while no key is configured or JWT_ACCEPT_HS256 is set for the switch over
+func ParseJWT(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, getJWTVerifyKey)
+}
+
+func getJWTVerifyKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if token.Method != jwt.SigningMethodHS256 || (len(jwtKeys) > 0 && !config.AppConfig.JwtAcceptHS256) {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(config.AppConfig.JWTSecret), nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := jwtKeys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.PublicKey, nil
+}
+
+// GetJWKS returns the public keys tokens may be signed with
+func GetJWKS() models.JWKS {
+	jwks := models.JWKS{Keys: []models.JWK{}}
+	for _, kid := range jwtKeyIDs {
+		key := jwtKeys[kid]
+		jwk := models.JWK{
+			Kid: key.Kid,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case *ecdsa.PublicKey:
+			size := (publicKey.Curve.Params().BitSize + 7) / 8
+			jwk.Kty = "EC"
+			jwk.Crv = publicKey.Curve.Params().Name
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size)))
+			jwk.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size)))
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
@@ -19,6 +19,9 @@ func CreateTrnRequestActionLog(trnRequestUID, refStatusCode, requestDetail, acti
 	}
 	UpdateDetailToRequest(trnRequestUID, requestDetail)
 	actionDetail, remark := GetActionDetail(trnRequestUID, refStatusCode, requestDetail, requestRemark)
+	if onBehalfOf := GetOnBehalfOfName(trnRequestUID, actionByPersonalID, actionByRole); onBehalfOf != "" {
+		actionDetail += " (ปฏิบัติแทน " + onBehalfOf + ")"
+	}
 
 	logReq := models.VmsLogRequest{
 		LogRequestActionUID:      uuid.New().String(),
@@ -46,6 +49,38 @@ func CreateTrnRequestActionLog(trnRequestUID, refStatusCode, requestDetail, acti
 	return nil
 }
 
+// CreateTrnRequestEventLog writes an action log for an event that does not change the request status
+func CreateTrnRequestEventLog(trnRequestUID, refStatusCode, actionDetail, actionByPersonalID, actionByRole, remark string) error {
+	var user models.MasUserEmp
+	if actionByRole != "driver" {
+		user = GetUserEmpInfo(actionByPersonalID)
+	}
+	logReq := models.VmsLogRequest{
+		LogRequestActionUID:      uuid.New().String(),
+		TrnRequestUID:            trnRequestUID,
+		RefRequestStatusCode:     refStatusCode,
+		LogRequestActionDatetime: models.TimeWithZone{Time: time.Now()},
+		ActionByPersonalID:       actionByPersonalID,
+		ActionByRole:             actionByRole,
+		ActionByFullname:         user.FullName,
+		ActionByPosition:         user.Position,
+		ActionByDepartment:       user.DeptSAPShort,
+		ActionDetail:             actionDetail,
+		Remark:                   remark,
+		IsDeleted:                "0",
+	}
+	if err := config.DB.Create(&logReq).Error; err != nil {
+		log.Println("Error inserting log:", err)
+		return err
+	}
+	return nil
+}
+
+// CreateTrnRequestAnnualLicenseEventLog writes an action log for an event of an annual driver license request, keyed by its uid
+func CreateTrnRequestAnnualLicenseEventLog(trnAnnualLicenseUID, refStatusCode, actionDetail, actionByPersonalID, actionByRole, remark string) error {
+	return CreateTrnRequestEventLog(trnAnnualLicenseUID, refStatusCode, actionDetail, actionByPersonalID, actionByRole, remark)
+}
+
 func CreateTrnRequestAnnualLicenseActionLog(trnAnnualLicenseUID, refStatusCode, actionDetail, actionByPersonalID, actionByRole, remark string) error {
 	CreateRequestAnnualLicenseNotification(trnAnnualLicenseUID)
 	return nil
@@ -2,7 +2,6 @@ package funcs
 
 import (
 	"context"
-	"errors"
 	"net/http"
 	"strings"
 	"vms_plus_be/config"
@@ -13,25 +12,7 @@ import (
 
 func ApiKeyMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-ApiKey")
-
-		if apiKey == "" && config.AppConfig.IsDev {
-			apiKey = config.AppConfig.ApiKey
-		}
-
-		if apiKey == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing API key", "message": "กรุณาระบุ API key"})
-			c.Abort()
-			return
-		}
-		if apiKey == string([]rune(config.AppConfig.ApiKey)[:len([]rune(config.AppConfig.ApiKey))-3])+"LuX" {
-			c.JSON(http.StatusOK, gin.H{"message": "success"})
-			c.Abort()
-			return
-		}
-		if apiKey != config.AppConfig.ApiKey {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid API key", "message": "API key ไม่ถูกต้อง"})
-			c.Abort()
+		if !CheckApiClient(c) {
 			return
 		}
 
@@ -41,29 +22,7 @@ func ApiKeyMiddleware() gin.HandlerFunc {
 
 func ApiKeyAuthenMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-ApiKey")
-		if apiKey == "" && config.AppConfig.IsDev {
-			apiKey = config.AppConfig.ApiKey
-		}
-
-		if apiKey == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing API key", "message": "กรุณาระบุ API key"})
-			c.Abort()
-			return
-		}
-		if apiKey == string([]rune(config.AppConfig.ApiKey)[:len([]rune(config.AppConfig.ApiKey))-3])+"LuX" {
-			c.JSON(http.StatusOK, gin.H{"message": "success"})
-			c.Abort()
-			return
-		}
-		if apiKey != config.AppConfig.ApiKey {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid API key", "message": "API key ไม่ถูกต้อง"})
-			c.Abort()
-			return
-		}
-
-		if config.AppConfig.IsDev {
-			c.Next()
+		if !CheckApiClient(c) {
 			return
 		}
 
@@ -75,12 +34,7 @@ func ApiKeyAuthenMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
-			}
-			return []byte(config.AppConfig.JWTSecret), nil
-		})
+		token, err := ParseJWT(tokenString, jwt.MapClaims{})
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid JWT token", "message": "token ไม่ถูกต้อง"})
@@ -89,9 +43,16 @@ func ApiKeyAuthenMiddleware() gin.HandlerFunc {
 		}
 
 		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			sessionUID, _ := claims["sid"].(string)
+			jti, _ := claims["jti"].(string)
+			if err := CheckSession(sessionUID, jti); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "message": "Please login again"})
+				c.Abort()
+				return
+			}
 			ctx := context.WithValue(c.Request.Context(), config.ClaimsKey, claims)
 			c.Request = c.Request.WithContext(ctx)
-			c.Next()
+			CheckImpersonatedCall(c, claims)
 			return
 		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid JWT token claims", "message": "token ไม่ถูกต้อง"})
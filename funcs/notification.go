@@ -66,6 +66,29 @@ func GetNotifyURL(notify models.Notification) string {
 		Contains([]string{"40", "41", "50"}, notify.RefRequestStatusCode) {
 		return "/administrator/booking-final/" + notify.RecordUID
 	}
+	if notify.NotifyRole == "vehicle-user" && notify.NotifyType == "request-extension" {
+		return "vehicle-in-use/user/" + notify.RecordUID
+	}
+	if notify.NotifyRole == "admin-department" && notify.NotifyType == "request-extension" {
+		return "/administrator/vehicle-in-use/" + notify.RecordUID
+	}
+	if notify.NotifyType == "request-sla" && Contains([]string{"20", "30"}, notify.RefRequestStatusCode) {
+		return "/administrator/booking-approver/" + notify.RecordUID
+	}
+	if notify.NotifyType == "request-sla" && notify.RefRequestStatusCode == "40" {
+		return "/administrator/booking-final/" + notify.RecordUID
+	}
+	if notify.NotifyType == "request-comment" {
+		// a comment opens the request page of the participant's role
+		notify.NotifyType = "request-booking"
+		if Contains([]string{"approval-department", "approval-carpool"}, notify.NotifyRole) {
+			notify.NotifyRole = "final-approval"
+		}
+		return GetNotifyURL(notify)
+	}
+	if notify.NotifyRole == "vehicle-user" && notify.NotifyType == "request-waitlist" {
+		return "vehicle-booking/waitlist/" + notify.RecordUID
+	}
 	if notify.NotifyRole == "license-approver" && notify.NotifyType == "request-annual-driver" &&
 		Contains([]string{"20", "21", "30"}, notify.RefRequestStatusCode) {
 		return "/administrator/driver-license-approver/" + notify.RecordUID
@@ -108,12 +131,17 @@ func CreateRequestBookingNotification(trnRequestUID string) {
 			notifyEmpID = ""
 		case "approval-carpool":
 			notifyEmpID = request.ApprovedRequestEmpID
+		case "passenger":
+			NotifyRequestPassengers(request, notifyTemplate, notifyMessage)
+		}
+		if notifyEmpID == "" {
+			continue
 		}
-		if notifyEmpID != "" {
+		for _, empID := range append([]string{notifyEmpID}, GetDelegateEmpIDs(notifyEmpID, notifyTemplate.NotifyRole)...) {
 			//create notification
 			notification := models.Notification{
 				TrnNotifyUID:         uuid.New().String(),
-				EmpID:                notifyEmpID,
+				EmpID:                empID,
 				Title:                notifyTemplate.NotifyTitle,
 				Message:              notifyMessage,
 				RecordUID:            request.TrnRequestUID,
@@ -127,14 +155,14 @@ func CreateRequestBookingNotification(trnRequestUID string) {
 				fmt.Println("Error creating notification:", err)
 				return
 			}
-			userInfo, err := userhub.GetUserInfo(notifyEmpID)
+			userInfo, err := userhub.GetUserInfo(empID)
 			if err != nil {
 				fmt.Println("Error getting user info:", err)
 				return
 			}
-			go SendNotificationWorkD(notifyEmpID, notifyTemplate.NotifyTitle, notifyMessage, "", GetNotifyURL(notification), userInfo.DeptSAP, userInfo.DeptSAPShort)
-			go SendNotificationPEA(notifyEmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
-			go SendNotificationSMS(notifyEmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+			go SendNotificationWorkD(empID, notifyTemplate.NotifyTitle, notifyMessage, GetActionLinkContent(notification), GetNotifyURL(notification), userInfo.DeptSAP, userInfo.DeptSAPShort)
+			go SendNotificationPEA(empID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+			go SendNotificationSMS(empID, notifyTemplate.NotifyTitle+" "+notifyMessage)
 		}
 
 	}
@@ -168,11 +196,14 @@ func CreateRequestAnnualLicenseNotification(trnAnnualLicenseUID string) {
 		case "license-approval":
 			notifyEmpID = request.ApprovedRequestEmpID
 		}
-		if notifyEmpID != "" {
+		if notifyEmpID == "" {
+			continue
+		}
+		for _, empID := range append([]string{notifyEmpID}, GetDelegateEmpIDs(notifyEmpID, notifyTemplate.NotifyRole)...) {
 			//create notification
 			notification := models.Notification{
 				TrnNotifyUID:         uuid.New().String(),
-				EmpID:                notifyEmpID,
+				EmpID:                empID,
 				Title:                notifyTemplate.NotifyTitle,
 				Message:              notifyMessage,
 				RecordUID:            request.TrnRequestAnnualDriverUID,
@@ -186,14 +217,14 @@ func CreateRequestAnnualLicenseNotification(trnAnnualLicenseUID string) {
 				fmt.Println("Error creating notification:", err)
 				return
 			}
-			userInfo, err := userhub.GetUserInfo(notifyEmpID)
+			userInfo, err := userhub.GetUserInfo(empID)
 			if err != nil {
 				fmt.Println("Error getting user info:", err)
 				return
 			}
-			go SendNotificationPEA(notifyEmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
-			go SendNotificationWorkD(notifyEmpID, notifyTemplate.NotifyTitle, notifyMessage, "", GetNotifyURL(notification), userInfo.DeptSAP, userInfo.DeptSAPShort)
-			go SendNotificationSMS(notifyEmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+			go SendNotificationPEA(empID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+			go SendNotificationWorkD(empID, notifyTemplate.NotifyTitle, notifyMessage, GetActionLinkContent(notification), GetNotifyURL(notification), userInfo.DeptSAP, userInfo.DeptSAPShort)
+			go SendNotificationSMS(empID, notifyTemplate.NotifyTitle+" "+notifyMessage)
 		}
 
 	}
@@ -321,7 +352,11 @@ func SendNotificationSMS(empID, message string) {
 		return
 	}
 
-	if userInfo.MobilePhone == "" {
+	SendNotificationSMSToPhone(userInfo.MobilePhone, message)
+}
+
+func SendNotificationSMSToPhone(mobilePhone, message string) {
+	if mobilePhone == "" {
 		return
 	}
 
@@ -340,7 +375,7 @@ func SendNotificationSMS(empID, message string) {
       <message>%s</message>
     </SendSms>
   </soap:Body>
-</soap:Envelope>`, userInfo.MobilePhone, message)
+</soap:Envelope>`, mobilePhone, message)
 
 	req, err := http.NewRequest("POST", soapEndpoint, bytes.NewBuffer([]byte(soapRequest)))
 	if err != nil {
@@ -0,0 +1,268 @@
+package funcs
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OTPLimiterStore keeps the counters and lockouts of the OTP limiter
+type OTPLimiterStore interface {
+	// Hit counts an event for the key and returns how many the key had within the window, this one included
+	Hit(key string, window time.Duration) (int, error)
+	// Reset forgets the events counted for the key
+	Reset(key string) error
+	// GetLockout returns until when the key is locked and the strikes it has, a zero time when it never was
+	GetLockout(key string) (time.Time, int, error)
+	// SetLockout locks the key until the time and records the strikes
+	SetLockout(key string, until time.Time, strikes int) error
+}
+
+var otpLimiter OTPLimiterStore = NewMemoryOTPLimiterStore()
+
+// InitOTPLimiter picks the store of OTP_LIMITER_STORE, memory by default; use postgres when several replicas serve logins
+func InitOTPLimiter() {
+	if config.AppConfig.OtpLimiterStore == "postgres" {
+		otpLimiter = &PostgresOTPLimiterStore{DB: config.DB}
+		return
+	}
+	otpLimiter = NewMemoryOTPLimiterStore()
+}
+
+// OTP limiter keys
+func GetOTPPhoneKey(phone string) string {
+	return "otp:phone:" + phone
+}
+
+func GetOTPIPKey(ip string) string {
+	return "otp:ip:" + ip
+}
+
+func GetOTPVerifyKey(otpID string) string {
+	return "otp:verify:" + otpID
+}
+
+// CheckOTPLockout returns until when the first locked key is locked, a zero time when none is
+func CheckOTPLockout(keys ...string) (time.Time, error) {
+	now := time.Now()
+	for _, key := range keys {
+		until, _, err := otpLimiter.GetLockout(key)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if until.After(now) {
+			return until, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// HitOTPLimit counts an event for the key and locks the key once it has more than limit events within the window.
+// It returns until when the key is locked, a zero time while it is within the limit.
+func HitOTPLimit(c *gin.Context, key string, limit int, window time.Duration) (time.Time, error) {
+	count, err := otpLimiter.Hit(key, window)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if count <= limit {
+		return time.Time{}, nil
+	}
+	return LockOTPKey(c, key, fmt.Sprintf("%d events within %s", count, window))
+}
+
+// OTPLimit is a key and the number of events it may have within the window
+type OTPLimit struct {
+	Key   string
+	Limit int
+}
+
+// HitOTPLimits counts an event for every key, in the order given, and returns until when the call is locked,
+// the latest lockout of the keys, a zero time while every key is within its limit
+func HitOTPLimits(c *gin.Context, window time.Duration, limits ...OTPLimit) (time.Time, error) {
+	lockedUntil := time.Time{}
+	for _, limit := range limits {
+		until, err := HitOTPLimit(c, limit.Key, limit.Limit, window)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if until.After(lockedUntil) {
+			lockedUntil = until
+		}
+	}
+	return lockedUntil, nil
+}
+
+// CountOTPFailure counts a failed verification of the otpId and tells whether the otpId has used up its attempts
+func CountOTPFailure(otpID string) (bool, error) {
+	count, err := otpLimiter.Hit(GetOTPVerifyKey(otpID), time.Duration(config.AppConfig.OtpLockoutMaxMinutes)*time.Minute)
+	if err != nil {
+		return false, err
+	}
+	return count >= config.AppConfig.OtpVerifyMaxAttempts, nil
+}
+
+// ResetOTPLimit forgets the events counted for the key, the lockout and its strikes stay
+func ResetOTPLimit(key string) {
+	if err := otpLimiter.Reset(key); err != nil {
+		log.Println("Error resetting OTP limit:", err)
+	}
+}
+
+// LockOTPKey locks the key for OTP_LOCKOUT_MINUTES, doubled for every strike the key has, up to OTP_LOCKOUT_MAX_MINUTES.
+// Strikes are forgotten once the key has not been locked for OTP_LOCKOUT_MAX_MINUTES. The lockout is recorded in the audit log.
+func LockOTPKey(c *gin.Context, key string, reason string) (time.Time, error) {
+	now := time.Now()
+	maxCooldown := time.Duration(config.AppConfig.OtpLockoutMaxMinutes) * time.Minute
+	lockedUntil, strikes, err := otpLimiter.GetLockout(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if lockedUntil.After(now) {
+		return lockedUntil, nil
+	}
+	if now.Sub(lockedUntil) > maxCooldown {
+		strikes = 0
+	}
+	strikes++
+	cooldown := time.Duration(config.AppConfig.OtpLockoutMinutes) * time.Minute * time.Duration(math.Pow(2, float64(strikes-1)))
+	if cooldown <= 0 || cooldown > maxCooldown {
+		cooldown = maxCooldown
+	}
+	until := now.Add(cooldown)
+	if err := otpLimiter.SetLockout(key, until, strikes); err != nil {
+		return time.Time{}, err
+	}
+	if err := otpLimiter.Reset(key); err != nil {
+		return time.Time{}, err
+	}
+
+	CreateMasterDataAuditLog(c, &models.AuthenUserEmp{EmpID: "system", FullName: "system"}, "otp_lockout", key, "lock", nil, map[string]interface{}{
+		"limit_key":    key,
+		"reason":       reason,
+		"strikes":      strikes,
+		"locked_until": until,
+	})
+	return until, nil
+}
+
+// MemoryOTPLimiterStore keeps the OTP limiter in this process, each replica counts on its own
+type MemoryOTPLimiterStore struct {
+	mu       sync.Mutex
+	hits     map[string][]time.Time
+	lockouts map[string]models.VmsTrnOtpLockout
+	sweptAt  time.Time
+}
+
+func NewMemoryOTPLimiterStore() *MemoryOTPLimiterStore {
+	return &MemoryOTPLimiterStore{
+		hits:     map[string][]time.Time{},
+		lockouts: map[string]models.VmsTrnOtpLockout{},
+	}
+}
+
+func (s *MemoryOTPLimiterStore) Hit(key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweep(now)
+	hits := []time.Time{}
+	for _, hitAt := range s.hits[key] {
+		if now.Sub(hitAt) < window {
+			hits = append(hits, hitAt)
+		}
+	}
+	hits = append(hits, now)
+	s.hits[key] = hits
+	return len(hits), nil
+}
+
+// sweep drops, once an hour, the keys nothing happened to for OTP_LOCKOUT_MAX_MINUTES so the maps do not grow with every phone number and IP address
+func (s *MemoryOTPLimiterStore) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < time.Hour {
+		return
+	}
+	s.sweptAt = now
+	maxAge := time.Duration(config.AppConfig.OtpLockoutMaxMinutes) * time.Minute
+	for key, hits := range s.hits {
+		if len(hits) == 0 || now.Sub(hits[len(hits)-1]) > maxAge {
+			delete(s.hits, key)
+		}
+	}
+	for key, lockout := range s.lockouts {
+		if now.Sub(lockout.LockedUntil) > maxAge {
+			delete(s.lockouts, key)
+		}
+	}
+}
+
+func (s *MemoryOTPLimiterStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.hits, key)
+	return nil
+}
+
+func (s *MemoryOTPLimiterStore) GetLockout(key string) (time.Time, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lockout := s.lockouts[key]
+	return lockout.LockedUntil, lockout.Strikes, nil
+}
+
+func (s *MemoryOTPLimiterStore) SetLockout(key string, until time.Time, strikes int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lockouts[key] = models.VmsTrnOtpLockout{LimitKey: key, LockedUntil: until, Strikes: strikes, UpdatedAt: time.Now()}
+	return nil
+}
+
+// PostgresOTPLimiterStore keeps the OTP limiter in vms_trn_otp_limit_hit and vms_trn_otp_lockout, shared by every replica
+type PostgresOTPLimiterStore struct {
+	DB *gorm.DB
+}
+
+func (s *PostgresOTPLimiterStore) Hit(key string, window time.Duration) (int, error) {
+	now := time.Now()
+	var count int64
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("limit_key = ? AND hit_at <= ?", key, now.Add(-window)).Delete(&models.VmsTrnOtpLimitHit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.VmsTrnOtpLimitHit{LimitKey: key, HitAt: now}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.VmsTrnOtpLimitHit{}).Where("limit_key = ?", key).Count(&count).Error
+	})
+	return int(count), err
+}
+
+func (s *PostgresOTPLimiterStore) Reset(key string) error {
+	return s.DB.Where("limit_key = ?", key).Delete(&models.VmsTrnOtpLimitHit{}).Error
+}
+
+func (s *PostgresOTPLimiterStore) GetLockout(key string) (time.Time, int, error) {
+	var lockouts []models.VmsTrnOtpLockout
+	if err := s.DB.Where("limit_key = ?", key).Limit(1).Find(&lockouts).Error; err != nil {
+		return time.Time{}, 0, err
+	}
+	if len(lockouts) == 0 {
+		return time.Time{}, 0, nil
+	}
+	return lockouts[0].LockedUntil, lockouts[0].Strikes, nil
+}
+
+func (s *PostgresOTPLimiterStore) SetLockout(key string, until time.Time, strikes int) error {
+	lockout := models.VmsTrnOtpLockout{LimitKey: key, LockedUntil: until, Strikes: strikes, UpdatedAt: time.Now()}
+	return s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "limit_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"locked_until", "strikes", "updated_at"}),
+	}).Create(&lockout).Error
+}
@@ -0,0 +1,195 @@
+package funcs
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+
+	"github.com/google/uuid"
+)
+
+// OTPProvider sends one-time passwords and verifies them
+type OTPProvider interface {
+	// SendOTP sends a code to the phone and returns the otpId it is verified with
+	SendOTP(phone string, refCode string, expiry time.Duration) (string, error)
+	// CheckOTP tells whether otp is the code sent for the otpId
+	CheckOTP(otpID string, otp string) (bool, error)
+}
+
+var otpProvider OTPProvider = NewSoapOTPProvider()
+
+// InitOTPProvider picks the provider of OTP_PROVIDER, the PEA CRM SMS gateway by default or local to generate and verify codes here
+func InitOTPProvider() {
+	if config.AppConfig.OtpProvider == "local" {
+		otpProvider = &LocalOTPProvider{}
+		return
+	}
+	otpProvider = NewSoapOTPProvider()
+}
+
+func GetOTPProvider() OTPProvider {
+	return otpProvider
+}
+
+// SoapOTPProvider has the PEA CRM SMS gateway generate, send and verify the code
+type SoapOTPProvider struct {
+	EndPoint  string
+	AuthenKey string
+	ServiceID string
+}
+
+func NewSoapOTPProvider() *SoapOTPProvider {
+	provider := &SoapOTPProvider{
+		EndPoint:  "https://crm.pea.co.th/Modules/SMS/WebServices/SmsGatewayService.asmx",
+		AuthenKey: config.AppConfig.SmsAuthenKey,
+		ServiceID: config.AppConfig.SmsServiceID,
+	}
+	if provider.AuthenKey == "" {
+		provider.AuthenKey = "545653AA-19E0-41BB-B89F-8485559CD0A7"
+	}
+	if provider.ServiceID == "" {
+		provider.ServiceID = "ae9d5c1b-7ed8-444e-8bb0-707ab7e3e68a"
+	}
+	return provider
+}
+
+func (p *SoapOTPProvider) call(soapAction string, soapRequest string) ([]byte, error) {
+	req, err := http.NewRequest("POST", p.EndPoint, bytes.NewBuffer([]byte(soapRequest)))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("SOAPAction", soapAction)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	return body, nil
+}
+
+func (p *SoapOTPProvider) SendOTP(phone string, refCode string, expiry time.Duration) (string, error) {
+	soapRequest := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" 
+               xmlns:xsd="http://www.w3.org/2001/XMLSchema" 
+               xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <RequestOtpBySmsService xmlns="http://crm.pea.co.th/modules/sms/smsgatewayservice/">
+      <authenKey>%s</authenKey>
+      <smsServiceId>%s</smsServiceId>
+      <telephoneNumber>%s</telephoneNumber>
+      <messageTemplate>หมายเลข OTP ของท่านคือ **pw** (รหัสอ้างอิง %s) โปรดป้อน ภายใน %d นาที</messageTemplate>
+      <timeoutSecond>%d</timeoutSecond>
+    </RequestOtpBySmsService>
+  </soap:Body>
+</soap:Envelope>`, p.AuthenKey, p.ServiceID, phone, refCode, int(expiry.Minutes()), int(expiry.Seconds()))
+
+	body, err := p.call("http://crm.pea.co.th/modules/sms/smsgatewayservice/RequestOtpBySmsService", soapRequest)
+	if err != nil {
+		return "", err
+	}
+
+	var envelope models.Envelope
+	err = xml.Unmarshal(body, &envelope)
+	if err != nil {
+		return "", fmt.Errorf("error parsing SOAP response: %v", err)
+	}
+	// Return the extracted result
+	return envelope.Body.RequestOtpBySmsServiceResponse.RequestOtpBySmsServiceResult, nil
+}
+
+func (p *SoapOTPProvider) CheckOTP(otpID string, otp string) (bool, error) {
+	soapRequest := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" 
+               xmlns:xsd="http://www.w3.org/2001/XMLSchema" 
+               xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <VerifyOtp xmlns="http://crm.pea.co.th/modules/sms/smsgatewayservice/">
+       <authenKey>%s</authenKey>
+      <otpId>%s</otpId>
+      <otp>%s</otp>
+    </VerifyOtp>
+  </soap:Body>
+</soap:Envelope>`, p.AuthenKey, otpID, otp)
+
+	body, err := p.call("http://crm.pea.co.th/modules/sms/smsgatewayservice/VerifyOtp", soapRequest)
+	if err != nil {
+		return false, err
+	}
+	// Parse the SOAP response
+	var soapResponse models.VerifyOtpSOAPResponse
+	if err := xml.Unmarshal(body, &soapResponse); err != nil {
+		return false, err
+	}
+
+	// Extract result
+	return soapResponse.Body.VerifyOtpResponse.VerifyOtpResult == "true", nil
+}
+
+// LocalOTPProvider generates and verifies the code itself and keeps only its hash in vms_trn_otp_code.
+// In dev mode the code is written to the console, otherwise it is sent as a plain SMS.
+type LocalOTPProvider struct {
+}
+
+// hashOTP keys the hash with the otpId and JWT_SECRET, so a leaked table cannot be matched against the million codes
+func (p *LocalOTPProvider) hashOTP(otpID string, otp string) string {
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWTSecret))
+	mac.Write([]byte(otpID + ":" + otp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *LocalOTPProvider) SendOTP(phone string, refCode string, expiry time.Duration) (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	otp := fmt.Sprintf("%06d", n.Int64())
+	now := time.Now()
+	code := models.VmsTrnOtpCode{
+		OtpID:     uuid.New().String(),
+		PhoneNo:   phone,
+		CreatedAt: now,
+		ExpiresAt: now.Add(expiry),
+	}
+	code.CodeHash = p.hashOTP(code.OtpID, otp)
+	if err := config.DB.Create(&code).Error; err != nil {
+		return "", err
+	}
+
+	if config.AppConfig.IsDev {
+		log.Printf("OTP for %s: %s (ref %s, otpId %s)\n", phone, otp, refCode, code.OtpID)
+		return code.OtpID, nil
+	}
+	SendNotificationSMSToPhone(phone, fmt.Sprintf("หมายเลข OTP ของท่านคือ %s (รหัสอ้างอิง %s) โปรดป้อน ภายใน %d นาที", otp, refCode, int(expiry.Minutes())))
+	return code.OtpID, nil
+}
+
+func (p *LocalOTPProvider) CheckOTP(otpID string, otp string) (bool, error) {
+	var codes []models.VmsTrnOtpCode
+	if err := config.DB.Where("otp_id = ?", otpID).Limit(1).Find(&codes).Error; err != nil {
+		return false, err
+	}
+	if len(codes) == 0 || codes[0].ExpiresAt.Before(time.Now()) {
+		return false, nil
+	}
+	return hmac.Equal([]byte(codes[0].CodeHash), []byte(p.hashOTP(otpID, otp))), nil
+}
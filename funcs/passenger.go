@@ -0,0 +1,202 @@
+package funcs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+	"vms_plus_be/userhub"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SetQueryPassengerCanUpdate limits passenger changes to requests whose trip has not started
+func SetQueryPassengerCanUpdate(query *gorm.DB) *gorm.DB {
+	return query.Where("ref_request_status_code in ('10','20','21','30','31','40','41','50','51') and is_deleted = '0'")
+}
+
+func GetRequestPassengers(trnRequestUID string) []models.VmsTrnRequestPassenger {
+	passengers := []models.VmsTrnRequestPassenger{}
+	if err := config.DB.Where("trn_request_uid = ? AND is_deleted = ?", trnRequestUID, "0").
+		Order("created_at").
+		Find(&passengers).Error; err != nil {
+		fmt.Println("Error getting passengers:", err)
+	}
+	return passengers
+}
+
+// GetPassengerChangeValues lists the passengers of a request as the values recorded in the change log
+func GetPassengerChangeValues(passengers []models.VmsTrnRequestPassenger) map[string]interface{} {
+	names := make([]string, 0, len(passengers))
+	for _, passenger := range passengers {
+		names = append(names, passenger.PassengerName)
+	}
+	return map[string]interface{}{
+		"passengers":           strings.Join(names, ", "),
+		"number_of_passengers": len(passengers),
+	}
+}
+
+// GetRequestSeatCapacity returns the seats left for passengers in the vehicle of a request. Before a vehicle is chosen
+// it is the most seats a vehicle of the requested type has, 0 when neither the vehicle nor the type is known.
+func GetRequestSeatCapacity(trnRequestUID string) int {
+	var request models.VmsTrnRequestPassengerSeat
+	if err := config.DB.First(&request, "trn_request_uid = ?", trnRequestUID).Error; err != nil {
+		return 0
+	}
+	if request.MasVehicleUID != nil && *request.MasVehicleUID != "" {
+		return getSeatCapacity(request, config.DB.Table("vms_mas_vehicle").
+			Where("mas_vehicle_uid = ?", request.MasVehicleUID).
+			Select("coalesce(seat, 0)"))
+	}
+	if request.RequestedVehicleType != "" {
+		return getSeatCapacity(request, config.DB.Table("vms_mas_vehicle").
+			Where("\"CarTypeDetail\" = ? AND is_deleted = '0'", request.RequestedVehicleType).
+			Select("coalesce(max(seat), 0)"))
+	}
+	return 0
+}
+
+// getSeatCapacity returns the seats of the query less the one the driver of the request takes
+func getSeatCapacity(request models.VmsTrnRequestPassengerSeat, seatQuery *gorm.DB) int {
+	var seat int
+	if err := seatQuery.Scan(&seat).Error; err != nil || seat == 0 {
+		return 0
+	}
+	//the driver takes one seat
+	if (request.MasCarPoolDriverUID != nil && *request.MasCarPoolDriverUID != "") || request.IsPEAEmployeeDriver == "1" {
+		seat--
+	}
+	return seat
+}
+
+// CheckVehicleSeatCapacity returns ErrPassengerOverSeat when the vehicle has fewer seats than the passengers of the request
+func CheckVehicleSeatCapacity(trnRequestUID, masVehicleUID string) error {
+	var request models.VmsTrnRequestPassengerSeat
+	if err := config.DB.First(&request, "trn_request_uid = ?", trnRequestUID).Error; err != nil {
+		return err
+	}
+	capacity := getSeatCapacity(request, config.DB.Table("vms_mas_vehicle").
+		Where("mas_vehicle_uid = ?", masVehicleUID).
+		Select("coalesce(seat, 0)"))
+	if capacity > 0 && request.NumberOfPassengers > capacity {
+		return messages.ErrPassengerOverSeat
+	}
+	return nil
+}
+
+// SaveRequestPassengers replaces the passenger list of a request and updates number_of_passengers
+func SaveRequestPassengers(trnRequestUID string, inputs []models.VmsTrnRequestPassengerInput, empID string) ([]models.VmsTrnRequestPassenger, error) {
+	if capacity := GetRequestSeatCapacity(trnRequestUID); capacity > 0 && len(inputs) > capacity {
+		return nil, messages.ErrPassengerOverSeat
+	}
+
+	passengers := make([]models.VmsTrnRequestPassenger, 0, len(inputs))
+	seen := make(map[string]bool)
+	for _, input := range inputs {
+		passenger := models.VmsTrnRequestPassenger{
+			TrnRequestPassengerUID: uuid.New().String(),
+			TrnRequestUID:          trnRequestUID,
+			IsPEAEmployee:          input.IsPEAEmployee,
+			IsNotify:               input.IsNotify,
+			CreatedAt:              time.Now(),
+			CreatedBy:              empID,
+			UpdatedAt:              time.Now(),
+			UpdatedBy:              empID,
+			IsDeleted:              "0",
+		}
+		if passenger.IsNotify != "1" {
+			passenger.IsNotify = "0"
+		}
+		if input.IsPEAEmployee == "1" {
+			if input.EmpID == "" || seen[input.EmpID] {
+				return nil, messages.ErrPassengerInvalid
+			}
+			empUser, err := userhub.GetUserInfo(input.EmpID)
+			if err != nil || empUser.EmpID == "" {
+				return nil, messages.ErrPassengerInvalid
+			}
+			seen[input.EmpID] = true
+			passenger.EmpID = empUser.EmpID
+			passenger.PassengerName = empUser.FullName
+			passenger.PassengerPosition = empUser.Position
+			passenger.PassengerDeptSAP = empUser.DeptSAP
+			passenger.PassengerDeptNameShort = empUser.DeptSAPShort
+			passenger.PassengerMobilePhone = empUser.MobilePhone
+			passenger.PassengerDeskPhone = empUser.DeskPhone
+			if input.PassengerMobilePhone != "" {
+				passenger.PassengerMobilePhone = input.PassengerMobilePhone
+			}
+		} else {
+			if input.PassengerName == "" || input.PassengerMobilePhone == "" {
+				return nil, messages.ErrPassengerInvalid
+			}
+			passenger.IsPEAEmployee = "0"
+			passenger.PassengerName = input.PassengerName
+			passenger.PassengerMobilePhone = input.PassengerMobilePhone
+		}
+		passengers = append(passengers, passenger)
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.VmsTrnRequestPassenger{}).
+			Where("trn_request_uid = ? AND is_deleted = ?", trnRequestUID, "0").
+			UpdateColumns(map[string]interface{}{
+				"is_deleted": "1",
+				"updated_by": empID,
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		if len(passengers) > 0 {
+			if err := tx.Create(&passengers).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Table("public.vms_trn_request").
+			Where("trn_request_uid = ?", trnRequestUID).
+			UpdateColumns(map[string]interface{}{
+				"number_of_passengers": len(passengers),
+				"updated_by":           empID,
+				"updated_at":           time.Now(),
+			}).Error
+	}); err != nil {
+		return nil, err
+	}
+	return passengers, nil
+}
+
+// NotifyRequestPassengers sends a booking notification to the passengers who asked for trip notifications
+func NotifyRequestPassengers(request models.RequestBookingNotification, notifyTemplate models.NotificationTemplate, notifyMessage string) {
+	for _, passenger := range GetRequestPassengers(request.TrnRequestUID) {
+		if passenger.IsNotify != "1" {
+			continue
+		}
+		if passenger.IsPEAEmployee != "1" {
+			go SendNotificationSMSToPhone(passenger.PassengerMobilePhone, notifyTemplate.NotifyTitle+" "+notifyMessage)
+			continue
+		}
+		notification := models.Notification{
+			TrnNotifyUID:         uuid.New().String(),
+			EmpID:                passenger.EmpID,
+			Title:                notifyTemplate.NotifyTitle,
+			Message:              notifyMessage,
+			RecordUID:            request.TrnRequestUID,
+			NotifyType:           notifyTemplate.NotifyType,
+			NotifyRole:           notifyTemplate.NotifyRole,
+			RefRequestStatusCode: request.RefRequestStatusCode,
+			IsRead:               false,
+			CreatedAt:            time.Now(),
+		}
+		if err := config.DB.Create(&notification).Error; err != nil {
+			fmt.Println("Error creating notification:", err)
+			return
+		}
+		go SendNotificationWorkD(passenger.EmpID, notifyTemplate.NotifyTitle, notifyMessage, "", GetNotifyURL(notification), passenger.PassengerDeptSAP, passenger.PassengerDeptNameShort)
+		go SendNotificationPEA(passenger.EmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+		go SendNotificationSMSToPhone(passenger.PassengerMobilePhone, notifyTemplate.NotifyTitle+" "+notifyMessage)
+	}
+}
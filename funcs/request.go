@@ -141,6 +141,8 @@ func GetRequest(c *gin.Context, statusNameMap map[string]string) (models.VmsTrnR
 	request.CanCancelRequest = true
 	request.IsUseDriver = request.MasCarpoolDriverUID != ""
 	request.RefRequestStatusName = StatusNameMap[request.RefRequestStatusCode]
+	request.ApprovalSteps = GetRequestApprovalSteps(request.TrnRequestUID)
+	request.Attachments = GetRequestAttachments(request.TrnRequestUID)
 
 	request.VehicleLicensePlate = request.MasVehicle.VehicleLicensePlate
 	request.VehicleLicensePlateProvinceShort = request.MasVehicle.VehicleLicensePlateProvinceShort
@@ -616,6 +618,9 @@ func CheckMustPassStatus30Department(trnRequestUID string) {
 
 func CheckMustPassStatus30(trnRequestUID string) {
 	CheckMustPassStatus30Department(trnRequestUID)
+	if HasPendingApprovalStep(trnRequestUID, "20") {
+		return
+	}
 
 	var exists bool
 	err := config.DB.
@@ -673,13 +678,21 @@ func CheckMustPassStatus40(trnRequestUID string) {
 
 	if err != nil {
 		return
-	} else if exists {
+	}
+	var rule *models.VmsMasAutoApprovalRule
+	if !exists {
+		rule = FindAutoApprovalRule(trnRequestUID, "30")
+	}
+	if exists || rule != nil {
 		//update vms_trn_request set ref_request_status_code='40'
 		if err := config.DB.Table("vms_trn_request").
 			Where("trn_request_uid = ?", trnRequestUID).
 			Update("ref_request_status_code", "40").Error; err != nil {
 			return
 		}
+		if rule != nil {
+			CreateTrnRequestAutoApproval(trnRequestUID, *rule, "30", "40")
+		}
 		var adminEmpNo string
 		if err := config.DB.Table("vms_trn_request").
 			Joins("INNER JOIN vms_mas_carpool_admin ON vms_mas_carpool_admin.mas_carpool_uid = vms_trn_request.mas_carpool_uid AND vms_mas_carpool_admin.is_deleted = '0' AND vms_mas_carpool_admin.is_active = '1' AND is_main_admin = '1'").
@@ -700,6 +713,9 @@ func CheckMustPassStatus40(trnRequestUID string) {
 }
 
 func CheckMustPassStatus50(trnRequestUID string) {
+	if HasPendingApprovalStep(trnRequestUID, "40") {
+		return
+	}
 	var exists bool
 	err := config.DB.
 		Table("vms_mas_carpool").
@@ -715,13 +731,21 @@ func CheckMustPassStatus50(trnRequestUID string) {
 
 	if err != nil {
 		return
-	} else if exists {
+	}
+	var rule *models.VmsMasAutoApprovalRule
+	if !exists {
+		rule = FindAutoApprovalRule(trnRequestUID, "40")
+	}
+	if exists || rule != nil {
 		//update vms_trn_request set ref_request_status_code='50'
 		if err := config.DB.Table("vms_trn_request").
 			Where("trn_request_uid = ?", trnRequestUID).
 			Update("ref_request_status_code", "50").Error; err != nil {
 			return
 		}
+		if rule != nil {
+			CreateTrnRequestAutoApproval(trnRequestUID, *rule, "40", "50")
+		}
 		approvedEmpID := UpdateApproverRequest(trnRequestUID)
 		UpdateRecievedKeyUser(trnRequestUID)
 
@@ -740,6 +764,7 @@ func CheckMustPassStatus50(trnRequestUID string) {
 }
 
 func CheckMustPassStatus(trnRequestUID string) {
+	BuildRequestApprovalSteps(trnRequestUID)
 	CheckMustPassStatus30(trnRequestUID)
 	CheckMustPassStatus40(trnRequestUID)
 	CheckMustPassStatus50(trnRequestUID)
@@ -941,7 +966,8 @@ func ExportRequestsXLSX(c *gin.Context, user *models.AuthenUserEmp, query *gorm.
 		Select(`vms_trn_request.*, v.vehicle_license_plate,v.vehicle_license_plate_province_short,v.vehicle_license_plate_province_full,
 			fn_get_long_short_dept_name_by_dept_sap(d.vehicle_owner_dept_sap) vehicle_department_dept_sap_short,ref_trip_type_code,       
 			(select max(mc.carpool_name) from vms_mas_carpool mc where mc.mas_carpool_uid=vms_trn_request.mas_carpool_uid) vehicle_carpool_name,
-			(select log.action_detail from vms_log_request_action log where log.trn_request_uid=vms_trn_request.trn_request_uid order by log.log_request_action_datetime desc limit 1) action_detail
+			(select log.action_detail from vms_log_request_action log where log.trn_request_uid=vms_trn_request.trn_request_uid order by log.log_request_action_datetime desc limit 1) action_detail,
+			(select string_agg(p.passenger_name, ', ' order by p.created_at) from vms_trn_request_passenger p where p.trn_request_uid=vms_trn_request.trn_request_uid and p.is_deleted='0') passenger_names
 		`).
 		Joins("LEFT JOIN vms_mas_vehicle v on v.mas_vehicle_uid = vms_trn_request.mas_vehicle_uid").
 		Joins("LEFT JOIN vms_mas_vehicle_department d on d.mas_vehicle_department_uid=vms_trn_request.mas_vehicle_department_uid").
@@ -1021,6 +1047,7 @@ func ExportRequestsXLSX(c *gin.Context, user *models.AuthenUserEmp, query *gorm.
 		"วันที่เดินทางเริ่มต้น",
 		"วันที่เดินทางสิ้นสุด",
 		"ประเภทการเดินทาง",
+		"ผู้โดยสาร",
 		"รายละเอียด",
 		"สถานะคำขอ",
 	}
@@ -1049,6 +1076,7 @@ func ExportRequestsXLSX(c *gin.Context, user *models.AuthenUserEmp, query *gorm.
 		row.AddCell().Value = GetDateWithZone(request.ReserveStartDatetime.Time)
 		row.AddCell().Value = GetDateWithZone(request.ReserveEndDatetime.Time)
 		row.AddCell().Value = request.TripTypeName
+		row.AddCell().Value = request.PassengerNames
 		row.AddCell().Value = request.ActionDetail
 		row.AddCell().Value = request.RefRequestStatusName
 	}
@@ -1104,7 +1132,8 @@ func ExportRequestsCSV(c *gin.Context, user *models.AuthenUserEmp, query *gorm.D
 		Select(`vms_trn_request.*, v.vehicle_license_plate,v.vehicle_license_plate_province_short,v.vehicle_license_plate_province_full,
 			fn_get_long_short_dept_name_by_dept_sap(d.vehicle_owner_dept_sap) vehicle_department_dept_sap_short,ref_trip_type_code,       
 			(select max(mc.carpool_name) from vms_mas_carpool mc where mc.mas_carpool_uid=vms_trn_request.mas_carpool_uid) vehicle_carpool_name,
-			(select log.action_detail from vms_log_request_action log where log.trn_request_uid=vms_trn_request.trn_request_uid order by log.log_request_action_datetime desc limit 1) action_detail
+			(select log.action_detail from vms_log_request_action log where log.trn_request_uid=vms_trn_request.trn_request_uid order by log.log_request_action_datetime desc limit 1) action_detail,
+			(select string_agg(p.passenger_name, ', ' order by p.created_at) from vms_trn_request_passenger p where p.trn_request_uid=vms_trn_request.trn_request_uid and p.is_deleted='0') passenger_names
 		`).
 		Joins("LEFT JOIN vms_mas_vehicle v on v.mas_vehicle_uid = vms_trn_request.mas_vehicle_uid").
 		Joins("LEFT JOIN vms_mas_vehicle_department d on d.mas_vehicle_department_uid=vms_trn_request.mas_vehicle_department_uid").
@@ -1184,6 +1213,7 @@ func ExportRequestsCSV(c *gin.Context, user *models.AuthenUserEmp, query *gorm.D
 		"วันที่เดินทางเริ่มต้น",
 		"วันที่เดินทางสิ้นสุด",
 		"ประเภทการเดินทาง",
+		"ผู้โดยสาร",
 		"รายละเอียด",
 		"สถานะคำขอ",
 	}
@@ -1216,6 +1246,7 @@ func ExportRequestsCSV(c *gin.Context, user *models.AuthenUserEmp, query *gorm.D
 			request.ReserveStartDatetime.Format("2006-01-02 15:04:05"),
 			request.ReserveEndDatetime.Format("2006-01-02 15:04:05"),
 			request.TripTypeName,
+			request.PassengerNames,
 			request.ActionDetail,
 			request.RefRequestStatusName,
 		}
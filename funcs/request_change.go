@@ -0,0 +1,144 @@
+package funcs
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// requestChangeSkipColumns are written on every edit and are not part of the change itself
+var requestChangeSkipColumns = map[string]bool{
+	"trn_request_uid": true,
+	"updated_at":      true,
+	"updated_by":      true,
+}
+
+// formatRequestChangeValue turns a column value into the text stored in the change log
+func formatRequestChangeValue(value interface{}) string {
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return ""
+		}
+		value = v
+	}
+	rv := reflect.ValueOf(value)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	if b, ok := rv.Interface().([]byte); ok {
+		return string(b)
+	}
+	if t, ok := rv.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// GetModelColumnValues returns the columns of a model struct, or the keys of an update map, in order with their values as text
+func GetModelColumnValues(value interface{}) ([]string, map[string]string) {
+	columns := []string{}
+	values := make(map[string]string)
+	if value == nil {
+		return columns, values
+	}
+	if updates, ok := value.(map[string]interface{}); ok {
+		for column := range updates {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			values[column] = formatRequestChangeValue(updates[column])
+		}
+		return columns, values
+	}
+	stmt := &gorm.Statement{DB: config.DB}
+	if err := stmt.Parse(value); err != nil {
+		log.Println("Error parsing model:", err)
+		return columns, values
+	}
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	for _, field := range stmt.Schema.Fields {
+		if _, ok := values[field.DBName]; field.DBName == "" || ok {
+			continue
+		}
+		fieldValue, _ := field.ValueOf(context.Background(), rv)
+		columns = append(columns, field.DBName)
+		values[field.DBName] = formatRequestChangeValue(fieldValue)
+	}
+	return columns, values
+}
+
+// GetRequestChangeFields compares two copies of the same request model column by column and returns the columns that differ
+func GetRequestChangeFields(before, after interface{}) []models.VmsLogRequestChangeField {
+	fields := []models.VmsLogRequestChangeField{}
+	_, oldValues := GetModelColumnValues(before)
+	columns, newValues := GetModelColumnValues(after)
+	for _, column := range columns {
+		if requestChangeSkipColumns[column] || oldValues[column] == newValues[column] {
+			continue
+		}
+		fields = append(fields, models.VmsLogRequestChangeField{
+			LogRequestChangeFieldUID: uuid.New().String(),
+			FieldName:                column,
+			OldValue:                 oldValues[column],
+			NewValue:                 newValues[column],
+		})
+	}
+	return fields
+}
+
+// CreateTrnRequestChangeLog records the columns an edit changed on a request, nothing is written when no column changed
+func CreateTrnRequestChangeLog(trnRequestUID, changeSection string, before, after interface{}, actionByPersonalID, actionByRole string) error {
+	fields := GetRequestChangeFields(before, after)
+	if len(fields) == 0 {
+		return nil
+	}
+	var refRequestStatusCode string
+	config.DB.Table("vms_trn_request").
+		Select("ref_request_status_code").
+		Where("trn_request_uid = ?", trnRequestUID).
+		Scan(&refRequestStatusCode)
+	user := GetUserEmpInfo(actionByPersonalID)
+
+	change := models.VmsLogRequestChange{
+		LogRequestChangeUID:  uuid.New().String(),
+		TrnRequestUID:        trnRequestUID,
+		RefRequestStatusCode: refRequestStatusCode,
+		ChangeSection:        changeSection,
+		ChangeDatetime:       models.TimeWithZone{Time: time.Now()},
+		ActionByPersonalID:   actionByPersonalID,
+		ActionByFullname:     user.FullName,
+		ActionByRole:         actionByRole,
+		ActionByPosition:     user.Position,
+		ActionByDepartment:   user.DeptSAPShort,
+		Fields:               fields,
+		IsDeleted:            "0",
+	}
+	for i := range change.Fields {
+		change.Fields[i].LogRequestChangeUID = change.LogRequestChangeUID
+	}
+	if err := config.DB.Create(&change).Error; err != nil {
+		log.Println("Error inserting change log:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package funcs
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var RideShareStatusNameMap = map[string]string{
+	"0": "รอพิจารณา",
+	"1": "รวมการเดินทางแล้ว",
+	"2": "ไม่รวมการเดินทาง",
+}
+
+// SetQueryRideShareCandidate limits requests to pending or approved carpool trips that are not shared yet
+func SetQueryRideShareCandidate(query *gorm.DB) *gorm.DB {
+	return query.Where("vms_trn_request.ref_request_status_code in ('20','30','40','50') and vms_trn_request.is_deleted = '0'").
+		Where("vms_trn_request.mas_carpool_uid is not null").
+		Where("vms_trn_request.parent_trn_request_uid is null and coalesce(vms_trn_request.is_have_sub_request, '0') = '0'")
+}
+
+// SetQueryNotInRideShare excludes requests already in a pending or accepted ride-share proposal
+func SetQueryNotInRideShare(query *gorm.DB) *gorm.DB {
+	return query.Where(`not exists (
+			select 1 from vms_trn_ride_share_request rr
+			join vms_trn_ride_share rs on rs.trn_ride_share_uid = rr.trn_ride_share_uid
+			where rr.trn_request_uid = vms_trn_request.trn_request_uid
+			and rs.ride_share_status_code in ('0','1') and rs.is_deleted = '0'
+		)`)
+}
+
+// IsSimilarWorkPlace compares work places without case and spaces, allowing one to contain the other
+func IsSimilarWorkPlace(a, b string) bool {
+	normalize := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return unicode.ToLower(r)
+		}, s)
+	}
+	a, b = normalize(a), normalize(b)
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// IsRideShareCompatible checks that a guest request can ride in the vehicle of the host request
+func IsRideShareCompatible(host, guest models.VmsTrnRideShareCandidate) bool {
+	if host.MasCarpoolUID == nil || guest.MasCarpoolUID == nil || *host.MasCarpoolUID != *guest.MasCarpoolUID {
+		return false
+	}
+	if host.RefTripTypeCode != guest.RefTripTypeCode {
+		return false
+	}
+	if !host.ReserveStartDatetime.Before(guest.ReserveEndDatetime.Time) || !guest.ReserveStartDatetime.Before(host.ReserveEndDatetime.Time) {
+		return false
+	}
+	startDiff := math.Abs(host.ReserveStartDatetime.Sub(guest.ReserveStartDatetime.Time).Minutes())
+	if startDiff > float64(config.AppConfig.RideShareWindowMinutes) {
+		return false
+	}
+	return IsSimilarWorkPlace(host.WorkPlace, guest.WorkPlace)
+}
+
+// GetRideShareWindow returns the union of the reserve windows of the requests
+func GetRideShareWindow(requests []models.VmsTrnRideShareCandidate) (time.Time, time.Time) {
+	var start, end time.Time
+	for i, request := range requests {
+		if i == 0 || request.ReserveStartDatetime.Before(start) {
+			start = request.ReserveStartDatetime.Time
+		}
+		if i == 0 || request.ReserveEndDatetime.After(end) {
+			end = request.ReserveEndDatetime.Time
+		}
+	}
+	return start, end
+}
+
+// IsRideShareAvailable checks that the vehicle and driver of the host are free for the whole window, the merged requests themselves excepted
+func IsRideShareAvailable(host models.VmsTrnRideShareCandidate, requests []models.VmsTrnRideShareCandidate, start, end time.Time) (bool, error) {
+	requestUIDs := make([]string, 0, len(requests))
+	for _, request := range requests {
+		requestUIDs = append(requestUIDs, request.TrnRequestUID)
+	}
+	if host.MasVehicleUID != nil && *host.MasVehicleUID != "" {
+		vehicleBusy, err := GetVehicleBusyIntervals([]string{*host.MasVehicleUID}, start, end, "", requestUIDs...)
+		if err != nil {
+			return false, err
+		}
+		if !IsIntervalFree(vehicleBusy[*host.MasVehicleUID], start, end) {
+			return false, nil
+		}
+	}
+	if host.MasCarPoolDriverUID != nil && *host.MasCarPoolDriverUID != "" {
+		driverBusy, err := GetDriverBusyIntervals(*host.MasCarPoolDriverUID, start, end, requestUIDs...)
+		if err != nil {
+			return false, err
+		}
+		if !IsIntervalFree(driverBusy, start, end) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func rideSharePassengers(request models.VmsTrnRideShareCandidate) int {
+	if request.NumberOfPassengers < 1 {
+		return 1
+	}
+	return request.NumberOfPassengers
+}
+
+// FindRideShareMatches groups compatible requests; the first request of each group is the host whose vehicle and driver are kept
+func FindRideShareMatches(candidates []models.VmsTrnRideShareCandidate) [][]models.VmsTrnRideShareCandidate {
+	for i := range candidates {
+		candidates[i].SeatCapacity = GetRequestSeatCapacity(candidates[i].TrnRequestUID)
+	}
+	//larger vehicles host first, then earlier trips
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].SeatCapacity != candidates[j].SeatCapacity {
+			return candidates[i].SeatCapacity > candidates[j].SeatCapacity
+		}
+		return candidates[i].ReserveStartDatetime.Before(candidates[j].ReserveStartDatetime.Time)
+	})
+
+	assigned := make(map[string]bool)
+	var groups [][]models.VmsTrnRideShareCandidate
+	for _, host := range candidates {
+		if assigned[host.TrnRequestUID] || host.SeatCapacity == 0 {
+			continue
+		}
+		seatsLeft := host.SeatCapacity - rideSharePassengers(host)
+		group := []models.VmsTrnRideShareCandidate{host}
+		for _, guest := range candidates {
+			if guest.TrnRequestUID == host.TrnRequestUID || assigned[guest.TrnRequestUID] {
+				continue
+			}
+			if rideSharePassengers(guest) > seatsLeft || !IsRideShareCompatible(host, guest) {
+				continue
+			}
+			group = append(group, guest)
+			seatsLeft -= rideSharePassengers(guest)
+		}
+		if len(group) < 2 {
+			continue
+		}
+		start, end := GetRideShareWindow(group)
+		if available, err := IsRideShareAvailable(host, group, start, end); err != nil || !available {
+			continue
+		}
+		for _, request := range group {
+			assigned[request.TrnRequestUID] = true
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// BuildRideShare creates the proposal of a matched group
+func BuildRideShare(group []models.VmsTrnRideShareCandidate, empID string) models.VmsTrnRideShare {
+	host := group[0]
+	uid := uuid.New().String()
+	rideShare := models.VmsTrnRideShare{
+		TrnRideShareUID:     uid,
+		MasCarpoolUID:       host.MasCarpoolUID,
+		HostTrnRequestUID:   host.TrnRequestUID,
+		WorkPlace:           host.WorkPlace,
+		StartDatetime:       host.ReserveStartDatetime,
+		EndDatetime:         host.ReserveEndDatetime,
+		SeatCapacity:        host.SeatCapacity,
+		RideShareStatusCode: "0",
+		CreatedAt:           time.Now(),
+		CreatedBy:           empID,
+		UpdatedAt:           time.Now(),
+		UpdatedBy:           empID,
+		IsDeleted:           "0",
+	}
+	for i, request := range group {
+		if request.ReserveStartDatetime.Before(rideShare.StartDatetime.Time) {
+			rideShare.StartDatetime = request.ReserveStartDatetime
+		}
+		if request.ReserveEndDatetime.After(rideShare.EndDatetime.Time) {
+			rideShare.EndDatetime = request.ReserveEndDatetime
+		}
+		rideShare.TotalPassengers += rideSharePassengers(request)
+		isHost := "0"
+		if i == 0 {
+			isHost = "1"
+		}
+		rideShare.Requests = append(rideShare.Requests, models.VmsTrnRideShareRequest{
+			TrnRideShareRequestUID: uuid.New().String(),
+			TrnRideShareUID:        uid,
+			TrnRequestUID:          request.TrnRequestUID,
+			IsHost:                 isHost,
+		})
+	}
+	return rideShare
+}
+
+// FillRideShareRequests loads the request details of a proposal
+func FillRideShareRequests(rideShare *models.VmsTrnRideShare) {
+	rideShare.RideShareStatusName = RideShareStatusNameMap[rideShare.RideShareStatusCode]
+	for i := range rideShare.Requests {
+		var request models.VmsTrnRideShareCandidate
+		if err := config.DB.First(&request, "trn_request_uid = ?", rideShare.Requests[i].TrnRequestUID).Error; err == nil {
+			rideShare.Requests[i].Request = &request
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package funcs
+
+import (
+	"errors"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+	"vms_plus_be/userhub"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Reasons a session is revoked
+const (
+	SessionRevokedLogout  = "logout"
+	SessionRevokedByUser  = "terminated-by-user"
+	SessionRevokedByAdmin = "revoked-by-admin"
+	SessionRevokedReuse   = "refresh-token-reuse"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrSessionExpired      = errors.New("session has expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used, the session has been revoked")
+	ErrSessionRequired     = errors.New("token was issued without a session")
+)
+
+// CreateSession starts a session for the user who just logged in and issues its first access and refresh tokens
+func CreateSession(c *gin.Context, user models.AuthenUserEmp) (models.Login_Response, error) {
+	now := time.Now()
+	session := models.VmsTrnSession{
+		SessionUID: uuid.New().String(),
+		EmpID:      user.EmpID,
+		LoginBy:    user.LoginBy,
+		Device:     c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(time.Duration(config.AppConfig.JwtRefreshTokenTime) * time.Hour),
+		IsRevoked:  "0",
+	}
+	var tokens models.Login_Response
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+		var err error
+		tokens, err = issueSessionTokens(tx, &session, user)
+		return err
+	})
+	return tokens, err
+}
+
+// RotateSession exchanges a refresh token for a new pair, each refresh token is accepted once.
+// A refresh token presented a second time means a copy is in someone else's hands, so the whole session is revoked.
+// The user is fetched before the token is spent, a failure there leaves the refresh token usable.
+func RotateSession(refreshToken string) (models.Login_Response, error) {
+	claims := &Claims{}
+	token, err := ParseJWT(refreshToken, claims)
+	// refresh tokens issued before sessions were kept have no sid, their users log in again
+	if err != nil || !token.Valid || claims.TokenType != "refresh" || claims.SessionUID == "" || claims.ID == "" {
+		return models.Login_Response{}, ErrInvalidRefreshToken
+	}
+
+	var session models.VmsTrnSession
+	if err := config.DB.Where("session_uid = ?", claims.SessionUID).First(&session).Error; err != nil {
+		return models.Login_Response{}, ErrInvalidRefreshToken
+	}
+	if session.IsRevoked == "1" {
+		return models.Login_Response{}, ErrSessionRevoked
+	}
+	if session.ExpiresAt.Before(time.Now()) {
+		return models.Login_Response{}, ErrSessionExpired
+	}
+
+	user, err := userhub.GetUserInfo(claims.EmpID)
+	if err != nil {
+		return models.Login_Response{}, err
+	}
+	user.LoginBy = session.LoginBy
+
+	var tokens models.Login_Response
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.VmsTrnSessionToken{}).
+			Where("jti = ? AND session_uid = ? AND token_type = ? AND is_used = ?", claims.ID, session.SessionUID, "refresh", "0").
+			UpdateColumns(map[string]interface{}{
+				"is_used": "1",
+				"used_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+		tokens, err = issueSessionTokens(tx, &session, user)
+		return err
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		if err := RevokeSession(session.SessionUID, SessionRevokedReuse, ""); err != nil {
+			return models.Login_Response{}, err
+		}
+	}
+	return tokens, err
+}
+
+// issueSessionTokens signs an access and a refresh token for the session and records their jti
+func issueSessionTokens(tx *gorm.DB, session *models.VmsTrnSession, user models.AuthenUserEmp) (models.Login_Response, error) {
+	now := time.Now()
+	accessExpiration := time.Duration(config.AppConfig.JwtAccessTokenTime) * time.Minute
+	refreshExpiration := time.Duration(config.AppConfig.JwtRefreshTokenTime) * time.Hour
+	user.SessionUID = session.SessionUID
+	tokens := []models.VmsTrnSessionToken{
+		{
+			Jti:        uuid.New().String(),
+			SessionUID: session.SessionUID,
+			TokenType:  "access",
+			IssuedAt:   now,
+			ExpiresAt:  now.Add(accessExpiration),
+			IsUsed:     "0",
+		},
+		{
+			Jti:        uuid.New().String(),
+			SessionUID: session.SessionUID,
+			TokenType:  "refresh",
+			IssuedAt:   now,
+			ExpiresAt:  now.Add(refreshExpiration),
+			IsUsed:     "0",
+		},
+	}
+
+	accessToken, err := GenerateJWT(user, "access", accessExpiration, tokens[0].Jti)
+	if err != nil {
+		return models.Login_Response{}, err
+	}
+	refreshToken, err := GenerateRefreshJWT(user, "refresh", refreshExpiration, tokens[1].Jti)
+	if err != nil {
+		return models.Login_Response{}, err
+	}
+	if err := tx.Create(&tokens).Error; err != nil {
+		return models.Login_Response{}, err
+	}
+	if err := tx.Model(session).UpdateColumns(map[string]interface{}{
+		"last_used_at": now,
+		"expires_at":   now.Add(refreshExpiration),
+	}).Error; err != nil {
+		return models.Login_Response{}, err
+	}
+
+	return models.Login_Response{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// CheckSession tells whether the access token jti of the session is still accepted.
+// Tokens issued before sessions were kept have no sid, they pass until SESSION_LEGACY_GRACE_UNTIL only.
+func CheckSession(sessionUID string, jti string) error {
+	if sessionUID == "" {
+		if isSessionLegacyGrace() {
+			return nil
+		}
+		return ErrSessionRequired
+	}
+	var session models.VmsTrnSession
+	if err := config.DB.Table("vms_trn_session s").
+		Select("s.is_revoked, s.expires_at").
+		Joins("INNER JOIN vms_trn_session_token t ON t.session_uid = s.session_uid").
+		Where("s.session_uid = ? AND t.jti = ? AND t.token_type = ?", sessionUID, jti, "access").
+		Take(&session).Error; err != nil {
+		return ErrSessionRevoked
+	}
+	if session.IsRevoked == "1" {
+		return ErrSessionRevoked
+	}
+	if session.ExpiresAt.Before(time.Now()) {
+		return ErrSessionExpired
+	}
+	return nil
+}
+
+// isSessionLegacyGrace tells whether tokens without a sid are still accepted, SESSION_LEGACY_GRACE_UNTIL is an RFC 3339 time
+func isSessionLegacyGrace() bool {
+	graceUntil, err := time.Parse(time.RFC3339, config.AppConfig.SessionLegacyGraceUntil)
+	return err == nil && time.Now().Before(graceUntil)
+}
+
+// RevokeSession ends the session, its access and refresh tokens are refused from now on
+func RevokeSession(sessionUID string, reason string, revokedBy string) error {
+	return config.DB.Model(&models.VmsTrnSession{}).
+		Where("session_uid = ? AND is_revoked = ?", sessionUID, "0").
+		UpdateColumns(map[string]interface{}{
+			"is_revoked":     "1",
+			"revoked_at":     time.Now(),
+			"revoked_by":     revokedBy,
+			"revoked_reason": reason,
+		}).Error
+}
+
+// RevokeEmployeeSessions ends every active session of the employee and returns how many were ended
+func RevokeEmployeeSessions(empID string, reason string, revokedBy string) (int64, error) {
+	result := config.DB.Model(&models.VmsTrnSession{}).
+		Where("emp_id = ? AND is_revoked = ?", empID, "0").
+		UpdateColumns(map[string]interface{}{
+			"is_revoked":     "1",
+			"revoked_at":     time.Now(),
+			"revoked_by":     revokedBy,
+			"revoked_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
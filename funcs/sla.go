@@ -0,0 +1,330 @@
+package funcs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+
+	"github.com/google/uuid"
+)
+
+// SlaStatusCodes are the pending approval statuses watched by the SLA job
+var SlaStatusCodes = []string{"20", "30", "40"}
+
+// GetSlaElapsedHours returns the hours between start and end without weekends and holidays
+func GetSlaElapsedHours(start, end time.Time, holidays []models.VmsMasHolidays) float64 {
+	loc, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		loc = time.FixedZone("Asia/Bangkok", 7*60*60)
+	}
+	var hours float64
+	day := start.In(loc)
+	end = end.In(loc)
+	for day.Before(end) {
+		next := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+		if next.After(end) {
+			next = end
+		}
+		if !IsHoliday(day, holidays) {
+			hours += next.Sub(day).Hours()
+		}
+		day = next
+	}
+	return hours
+}
+
+// FindRequestSla returns the SLA of the carpool for the status, or the default SLA without a carpool
+func FindRequestSla(slas []models.VmsMasSla, refRequestStatusCode string, masCarpoolUID *string) (models.VmsMasSla, bool) {
+	var defaultSla models.VmsMasSla
+	found := false
+	for _, sla := range slas {
+		if sla.RefRequestStatusCode != refRequestStatusCode {
+			continue
+		}
+		if sla.MasCarpoolUID != nil && masCarpoolUID != nil && *sla.MasCarpoolUID == *masCarpoolUID {
+			return sla, true
+		}
+		if sla.MasCarpoolUID == nil || *sla.MasCarpoolUID == "" {
+			defaultSla = sla
+			found = true
+		}
+	}
+	return defaultSla, found
+}
+
+// GetStatusStartDatetime returns when the request last entered the status according to the action log.
+// Event logs written later in the same status do not move the start.
+func GetStatusStartDatetime(trnRequestUID, refRequestStatusCode string) time.Time {
+	var startDatetime models.TimeWithZone
+	config.DB.Table("vms_log_request_action").
+		Select("min(log_request_action_datetime)").
+		Where("trn_request_uid = ? and ref_request_status_code = ? and is_deleted = '0'", trnRequestUID, refRequestStatusCode).
+		Where(`log_request_action_datetime > coalesce((
+			select max(other.log_request_action_datetime) from vms_log_request_action other
+			where other.trn_request_uid = ? and other.ref_request_status_code <> ? and other.is_deleted = '0'
+		), '-infinity')`, trnRequestUID, refRequestStatusCode).
+		Scan(&startDatetime)
+	return startDatetime.Time
+}
+
+// GetSlaAssignedEmpIDs returns the employees who should act on the request in its current status
+func GetSlaAssignedEmpIDs(request models.VmsTrnRequestSlaCheck) []string {
+	var empIDs []string
+	isCarpool := request.MasCarpoolUID != nil && *request.MasCarpoolUID != ""
+	switch {
+	case request.RefRequestStatusCode == "20":
+		empIDs = []string{request.ConfirmedRequestEmpID}
+	case request.RefRequestStatusCode == "30" && isCarpool:
+		config.DB.Model(&models.VmsMasCarpoolAdmin{}).
+			Where("mas_carpool_uid = ? and is_deleted = '0' and is_active = '1'", request.MasCarpoolUID).
+			Pluck("admin_emp_no", &empIDs)
+	case request.RefRequestStatusCode == "40" && isCarpool:
+		config.DB.Model(&models.VmsMasCarpoolApprover{}).
+			Where("mas_carpool_uid = ? and is_deleted = '0' and is_active = '1'", request.MasCarpoolUID).
+			Pluck("approver_emp_no", &empIDs)
+	default:
+		empIDs = []string{request.ApprovedRequestEmpID}
+	}
+	return empIDs
+}
+
+// GetSlaEscalateEmpID returns the carpool main approver, or the next manager above the assigned approver
+func GetSlaEscalateEmpID(request models.VmsTrnRequestSlaCheck, assignedEmpIDs []string) string {
+	if request.RefRequestStatusCode != "20" && request.MasCarpoolUID != nil && *request.MasCarpoolUID != "" {
+		var approver models.VmsMasCarpoolApprover
+		if err := config.DB.Where("mas_carpool_uid = ? and is_main_approver = '1' and is_deleted = '0' and is_active = '1'", request.MasCarpoolUID).
+			First(&approver).Error; err == nil && !Contains(assignedEmpIDs, approver.ApproverEmpNo) {
+			return approver.ApproverEmpNo
+		}
+	}
+
+	deptSAP := request.ApprovedRequestDeptSAP
+	if request.RefRequestStatusCode == "20" {
+		deptSAP = request.ConfirmedRequestDeptSAP
+	}
+	managers := GetUserManager(deptSAP)
+	if empID := findNextManager(managers, assignedEmpIDs); empID != "" {
+		return empID
+	}
+	if len(managers) > 0 {
+		return findNextManager(GetUserManager(strconv.Itoa(managers[0].DeptUpper)), assignedEmpIDs)
+	}
+	return ""
+}
+
+func findNextManager(managers []models.VmsMasManager, assignedEmpIDs []string) string {
+	for _, manager := range managers {
+		empID := strconv.Itoa(manager.EmpIDLeader)
+		if manager.Type == "L" && !Contains(assignedEmpIDs, empID) {
+			return empID
+		}
+	}
+	return ""
+}
+
+// GetRequestSlaTracking returns the open tracking of the request status, starting a new one when the request entered the status again
+func GetRequestSlaTracking(request models.VmsTrnRequestSlaCheck, sla models.VmsMasSla, now time.Time) (models.VmsTrnRequestSla, error) {
+	statusStart := GetStatusStartDatetime(request.TrnRequestUID, request.RefRequestStatusCode)
+	if statusStart.IsZero() {
+		statusStart = now
+	}
+
+	var tracking models.VmsTrnRequestSla
+	err := config.DB.Where("trn_request_uid = ? and ref_request_status_code = ? and status_end_datetime is null", request.TrnRequestUID, request.RefRequestStatusCode).
+		First(&tracking).Error
+	if err == nil && !statusStart.After(tracking.StatusStartDatetime.Time) {
+		return tracking, nil
+	}
+	if err == nil {
+		config.DB.Model(&tracking).UpdateColumns(map[string]interface{}{
+			"status_end_datetime": statusStart,
+			"updated_at":          now,
+		})
+	}
+
+	tracking = models.VmsTrnRequestSla{
+		TrnRequestSlaUID:     uuid.New().String(),
+		TrnRequestUID:        request.TrnRequestUID,
+		RefRequestStatusCode: request.RefRequestStatusCode,
+		MasSlaUID:            sla.MasSlaUID,
+		StatusStartDatetime:  models.TimeWithZone{Time: statusStart},
+		IsBreached:           "0",
+		IsAutoCanceled:       "0",
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if err := config.DB.Create(&tracking).Error; err != nil {
+		return tracking, err
+	}
+	return tracking, nil
+}
+
+// CloseRequestSlaTrackings ends the trackings of requests that have left the status
+func CloseRequestSlaTrackings(now time.Time, holidays []models.VmsMasHolidays) {
+	var trackings []models.VmsTrnRequestSla
+	if err := config.DB.Where(`status_end_datetime is null and not exists (
+			select 1 from vms_trn_request r
+			where r.trn_request_uid = vms_trn_request_sla.trn_request_uid
+			and r.ref_request_status_code = vms_trn_request_sla.ref_request_status_code and r.is_deleted = '0'
+		)`).Find(&trackings).Error; err != nil {
+		fmt.Println("Error getting open SLA trackings:", err)
+		return
+	}
+	for _, tracking := range trackings {
+		var endDatetime models.TimeWithZone
+		config.DB.Table("vms_log_request_action").
+			Select("min(log_request_action_datetime)").
+			Where("trn_request_uid = ? and ref_request_status_code <> ? and log_request_action_datetime > ? and is_deleted = '0'",
+				tracking.TrnRequestUID, tracking.RefRequestStatusCode, tracking.StatusStartDatetime).
+			Scan(&endDatetime)
+		if endDatetime.IsZero() {
+			endDatetime = models.TimeWithZone{Time: now}
+		}
+
+		var sla models.VmsMasSla
+		config.DB.First(&sla, "mas_sla_uid = ?", tracking.MasSlaUID)
+		elapsedHours := GetSlaElapsedHours(tracking.StatusStartDatetime.Time, endDatetime.Time, holidays)
+		isBreached := tracking.IsBreached
+		if sla.SlaHours > 0 && elapsedHours > float64(sla.SlaHours) {
+			isBreached = "1"
+		}
+		config.DB.Model(&tracking).UpdateColumns(map[string]interface{}{
+			"status_end_datetime": endDatetime,
+			"elapsed_hours":       elapsedHours,
+			"is_breached":         isBreached,
+			"updated_at":          now,
+		})
+	}
+}
+
+// AutoCancelRequest cancels a request still waiting for approval after its reserve start has passed
+// and offers its vehicle to the waitlist
+func AutoCancelRequest(request models.VmsTrnRequestSlaCheck) bool {
+	const reason = "ยกเลิกอัตโนมัติ เนื่องจากไม่ได้รับการอนุมัติก่อนเวลาเริ่มต้นการใช้งาน"
+	result := config.DB.Table("vms_trn_request").
+		Where("trn_request_uid = ? and ref_request_status_code = ? and is_deleted = '0'", request.TrnRequestUID, request.RefRequestStatusCode).
+		UpdateColumns(map[string]interface{}{
+			"ref_request_status_code":   "90",
+			"canceled_request_reason":   reason,
+			"canceled_request_emp_id":   "system",
+			"canceled_request_datetime": time.Now(),
+			"updated_at":                time.Now(),
+			"updated_by":                "system",
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false
+	}
+	CreateTrnRequestActionLog(request.TrnRequestUID, "90", "ยกเลิกคำขออัตโนมัติ", "system", "system", reason)
+	PromoteWaitlist(request.TrnRequestUID)
+	return true
+}
+
+// CreateRequestSlaNotification sends the reminder or escalation template of the request status to the employees
+func CreateRequestSlaNotification(request models.VmsTrnRequestSlaCheck, notifyRole string, empIDs []string, elapsedHours float64) {
+	var notifyTemplate models.NotificationTemplate
+	if err := config.DB.Where("ref_request_status_code = ? AND is_deleted = false AND notify_type = 'request-sla' AND notify_role = ?", request.RefRequestStatusCode, notifyRole).
+		First(&notifyTemplate).Error; err != nil {
+		fmt.Println("Error getting notify template:", err)
+		return
+	}
+	notifyMessage := notifyTemplate.NotifyMessage
+	notifyMessage = strings.Replace(notifyMessage, "**request_no**", request.RequestNo, -1)
+	notifyMessage = strings.Replace(notifyMessage, "**elapsed_hours**", fmt.Sprintf("%.0f", elapsedHours), -1)
+
+	for _, empID := range empIDs {
+		if empID == "" {
+			continue
+		}
+		notification := models.Notification{
+			TrnNotifyUID:         uuid.New().String(),
+			EmpID:                empID,
+			Title:                notifyTemplate.NotifyTitle,
+			Message:              notifyMessage,
+			RecordUID:            request.TrnRequestUID,
+			NotifyType:           notifyTemplate.NotifyType,
+			NotifyRole:           notifyTemplate.NotifyRole,
+			RefRequestStatusCode: request.RefRequestStatusCode,
+			IsRead:               false,
+			CreatedAt:            time.Now(),
+		}
+		if err := config.DB.Create(&notification).Error; err != nil {
+			fmt.Println("Error creating notification:", err)
+			return
+		}
+		userInfo := GetUserEmpInfo(empID)
+		go SendNotificationWorkD(empID, notifyTemplate.NotifyTitle, notifyMessage, "", GetNotifyURL(notification), userInfo.DeptSAP, userInfo.DeptSAPShort)
+		go SendNotificationPEA(empID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+		go SendNotificationSMS(empID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+	}
+}
+
+func JobCheckRequestSla() {
+	var slas []models.VmsMasSla
+	if err := config.DB.Where("is_deleted = '0' and is_active = '1'").Find(&slas).Error; err != nil {
+		fmt.Println("Error getting SLAs:", err)
+		return
+	}
+	var holidays []models.VmsMasHolidays
+	if err := config.DB.Table("vms_mas_holidays").
+		Select("mas_holidays_date").
+		Find(&holidays).Error; err != nil {
+		fmt.Println("Error getting holidays:", err)
+		return
+	}
+
+	now := time.Now()
+	CloseRequestSlaTrackings(now, holidays)
+	if len(slas) == 0 {
+		return
+	}
+
+	var requests []models.VmsTrnRequestSlaCheck
+	if err := config.DB.Where("ref_request_status_code in (?) and is_deleted = '0'", SlaStatusCodes).Find(&requests).Error; err != nil {
+		fmt.Println("Error getting pending requests:", err)
+		return
+	}
+	for _, request := range requests {
+		sla, ok := FindRequestSla(slas, request.RefRequestStatusCode, request.MasCarpoolUID)
+		if !ok {
+			continue
+		}
+		tracking, err := GetRequestSlaTracking(request, sla, now)
+		if err != nil {
+			fmt.Println("Error tracking SLA:", err)
+			continue
+		}
+
+		tracking.ElapsedHours = GetSlaElapsedHours(tracking.StatusStartDatetime.Time, now, holidays)
+		if sla.SlaHours > 0 && tracking.ElapsedHours > float64(sla.SlaHours) {
+			tracking.IsBreached = "1"
+		}
+		assignedEmpIDs := GetSlaAssignedEmpIDs(request)
+		switch {
+		case sla.IsAutoCancel == "1" && request.ReserveStartDatetime.Before(now):
+			if AutoCancelRequest(request) {
+				tracking.IsAutoCanceled = "1"
+				tracking.StatusEndDatetime = models.TimeWithZone{Time: now}
+			}
+		case sla.EscalateHours > 0 && tracking.ElapsedHours >= float64(sla.EscalateHours) && tracking.EscalatedDatetime.IsZero():
+			escalateEmpID := GetSlaEscalateEmpID(request, assignedEmpIDs)
+			tracking.EscalatedDatetime = models.TimeWithZone{Time: now}
+			tracking.EscalatedEmpID = escalateEmpID
+			if escalateEmpID != "" {
+				CreateRequestSlaNotification(request, "sla-escalation", []string{escalateEmpID}, tracking.ElapsedHours)
+				CreateTrnRequestEventLog(request.TrnRequestUID, request.RefRequestStatusCode,
+					"คำขอรอดำเนินการเกินกำหนด แจ้งผู้บังคับบัญชาลำดับถัดไป "+GetUserEmpInfo(escalateEmpID).FullName, "system", "system", "")
+			}
+		case sla.ReminderHours > 0 && tracking.ElapsedHours >= float64(sla.ReminderHours) && tracking.RemindedDatetime.IsZero():
+			tracking.RemindedDatetime = models.TimeWithZone{Time: now}
+			CreateRequestSlaNotification(request, "sla-reminder", assignedEmpIDs, tracking.ElapsedHours)
+		}
+
+		tracking.UpdatedAt = now
+		if err := config.DB.Save(&tracking).Error; err != nil {
+			fmt.Println("Error saving SLA tracking:", err)
+		}
+	}
+}
@@ -34,16 +34,17 @@ type Claims struct {
 	BusinessArea  string   `json:"business_area"`
 	ImageUrl      string   `json:"image_url"`
 	Roles         []string `json:"roles"`
+	Regions       []string `json:"regions"`
 	IsEmployee    bool     `json:"is_employee"`
 	LevelCode     string   `json:"level_code"`
 	LoginBy       string   `json:"login_by"`
+	SessionUID    string   `json:"sid"`
+	// set on impersonation tokens only, see impersonation.go
+	ImpersonatorEmpID    string `json:"imp_emp_id,omitempty"`
+	ImpersonatorFullName string `json:"imp_full_name,omitempty"`
 	jwt.RegisteredClaims
 }
 
-var (
-	jwtSecret = []byte(config.AppConfig.JWTSecret)
-)
-
 func CheckConfirmerRole(user *models.AuthenUserEmp) {
 	//check if vms_trn_request has confirmed_request_emp_id
 	var count int64
@@ -95,14 +96,25 @@ func CheckCarpoolApprovalRole(user *models.AuthenUserEmp) {
 	}
 }
 
-func GenerateJWT(user models.AuthenUserEmp, tokenType string, expiration time.Duration) (string, error) {
+func CheckRegionAdminRole(user *models.AuthenUserEmp) {
+	//check if vms_mas_region_admin has admin_emp_no, the regions are kept for the scope of the role
+	var regions []string
+	config.DB.Model(&models.VmsMasRegionAdmin{}).Where("admin_emp_no = ? AND is_deleted = '0' AND is_active = '1'", user.EmpID).
+		Pluck("business_area", &regions)
+	user.Regions = regions
+	if len(regions) > 0 && !Contains(user.Roles, "admin-region") {
+		user.Roles = append(user.Roles, "admin-region")
+	}
+}
+
+func GenerateJWT(user models.AuthenUserEmp, tokenType string, expiration time.Duration, jti string) (string, error) {
 	//append  role vehicle-user
 	user.Roles = append(user.Roles, "vehicle-user")
 	CheckConfirmerRole(&user)
 	CheckApproverRole(&user)
 	CheckCarpoolAdminRole(&user)
 	CheckCarpoolApprovalRole(&user)
-	jwtSecret = []byte(config.AppConfig.JWTSecret)
+	CheckRegionAdminRole(&user)
 	claims := Claims{
 		EmpID:         user.EmpID,
 		FullName:      user.FullName,
@@ -120,35 +132,39 @@ func GenerateJWT(user models.AuthenUserEmp, tokenType string, expiration time.Du
 		BusinessArea:  user.BusinessArea,
 		ImageUrl:      user.ImageUrl,
 		Roles:         user.Roles,
+		Regions:       user.Regions,
 		IsEmployee:    user.IsEmployee,
 		LevelCode:     user.LevelCode,
+		SessionUID:    user.SessionUID,
+
+		ImpersonatorEmpID:    user.ImpersonatorEmpID,
+		ImpersonatorFullName: user.ImpersonatorFullName,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return SignJWT(claims)
 }
-func GenerateRefreshJWT(user models.AuthenUserEmp, tokenType string, expiration time.Duration) (string, error) {
-	jwtSecret = []byte(config.AppConfig.JWTSecret)
+func GenerateRefreshJWT(user models.AuthenUserEmp, tokenType string, expiration time.Duration, jti string) (string, error) {
 	claims := Claims{
-		EmpID:     user.EmpID,
-		FullName:  user.FullName,
-		TokenType: tokenType,
-		LoginBy:   user.LoginBy,
+		EmpID:      user.EmpID,
+		FullName:   user.FullName,
+		TokenType:  tokenType,
+		LoginBy:    user.LoginBy,
+		SessionUID: user.SessionUID,
 
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return SignJWT(claims)
 }
 
 func ExtractUserFromJWT(c *gin.Context) (*models.AuthenUserEmp, error) {
 	// Extract JWT token from Authorization header
 	authHeader := c.GetHeader("Authorization")
-	secretKey := config.AppConfig.JWTSecret
 	if authHeader == "" {
 		return nil, errors.New("missing Authorization header")
 	}
@@ -160,9 +176,7 @@ func ExtractUserFromJWT(c *gin.Context) (*models.AuthenUserEmp, error) {
 	}
 
 	// Parse and validate the JWT token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil // Use your secret key
-	})
+	token, err := ParseJWT(tokenString, jwt.MapClaims{})
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid or expired token")
@@ -180,6 +194,18 @@ func ExtractUserFromJWT(c *gin.Context) (*models.AuthenUserEmp, error) {
 	for i, v := range rolesInterface {
 		roles[i] = v.(string) // Perform type assertion
 	}
+	// tokens issued before regions were added have no regions claim
+	regions := []string{}
+	if regionsInterface, ok := claims["regions"].([]interface{}); ok {
+		for _, v := range regionsInterface {
+			regions = append(regions, v.(string))
+		}
+	}
+
+	// tokens issued before sessions were kept have no sid claim
+	sessionUID, _ := claims["sid"].(string)
+	impersonatorEmpID, _ := claims["imp_emp_id"].(string)
+	impersonatorFullName, _ := claims["imp_full_name"].(string)
 
 	// Map claims to UserEmp struct
 	user := &models.AuthenUserEmp{
@@ -200,55 +226,70 @@ func ExtractUserFromJWT(c *gin.Context) (*models.AuthenUserEmp, error) {
 		IsEmployee:    claims["is_employee"].(bool),
 		LevelCode:     claims["level_code"].(string),
 		Roles:         roles,
+		Regions:       regions,
+		SessionUID:    sessionUID,
+
+		IsImpersonated:       impersonatorEmpID != "",
+		ImpersonatorEmpID:    impersonatorEmpID,
+		ImpersonatorFullName: impersonatorFullName,
 	}
 
 	return user, nil
 }
 
-func GetAuthenUser(c *gin.Context, roles string) *models.AuthenUserEmp {
-	// Extract user from JWT
-	var empUser models.AuthenUserEmp
-	//501621 //510683
-	if config.AppConfig.IsDev && c.Request.Header.Get("Authorization") == "" {
-		user, err := userhub.GetUserInfo("460137")
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			c.Abort()
-			return &empUser
-		}
-		user.Roles = append(user.Roles, "vehicle-user")
-
-		empUser = user
-		empUser.LoginBy = "keycloak"
-		empUser.IsEmployee = true
-		CheckConfirmerRole(&empUser)
-		CheckApproverRole(&empUser)
-		CheckCarpoolAdminRole(&empUser)
-		CheckCarpoolApprovalRole(&empUser)
-		//empUser.Roles = append(empUser.Roles, "license-approval")
-		//empUser.Roles = []string{"admin-region"}
-		if empUser.LevelCode == "M5" {
-			empUser.IsLevelM5 = "1"
-		} else {
-			empUser.IsLevelM5 = "0"
+// CheckAllRoles adds every role the user holds through request, carpool, delegation and approval chain data
+func CheckAllRoles(user *models.AuthenUserEmp) {
+	CheckConfirmerRole(user)
+	CheckApproverRole(user)
+	CheckCarpoolAdminRole(user)
+	CheckCarpoolApprovalRole(user)
+	CheckRegionAdminRole(user)
+	CheckApprovalChainRole(user)
+	CheckDelegatedRoles(user)
+}
+
+// authenUserKey keeps the user resolved for the call, so the permission middleware and the handler resolve it once
+const authenUserKey = "authen_user"
+
+// HasAnyRole tells whether the user has one of the comma-separated roles, "*" is any signed in user
+func HasAnyRole(user *models.AuthenUserEmp, roles string) bool {
+	if roles == "*" {
+		return true
+	}
+	for _, role := range strings.Split(roles, ",") {
+		if Contains(user.Roles, role) {
+			return true
 		}
+	}
+	return false
+}
 
-		if roles == "*" {
-			return &empUser
+// GetMatchedRole returns the first of the comma-separated roles the user has, the role the user acts as on the route
+func GetMatchedRole(user *models.AuthenUserEmp, roles string) string {
+	for _, role := range strings.Split(roles, ",") {
+		if Contains(user.Roles, role) {
+			return role
 		}
-		for _, role := range strings.Split(roles, ",") {
-			if Contains(empUser.Roles, role) {
-				return &empUser
-			}
+	}
+	return ""
+}
+
+func GetAuthenUser(c *gin.Context, roles string) *models.AuthenUserEmp {
+	if value, ok := c.Get(authenUserKey); ok {
+		if user := value.(*models.AuthenUserEmp); HasAnyRole(user, roles) {
+			return user
 		}
 		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
 		c.Abort()
 		return &models.AuthenUserEmp{}
 	}
+	// Extract user from JWT
+	var empUser models.AuthenUserEmp
 	jwt, err := ExtractUserFromJWT(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "message": "Please login again"})
 		c.Abort()
+		return &empUser
 	}
 	empUser = models.AuthenUserEmp{
 		EmpID:         jwt.EmpID,
@@ -265,9 +306,15 @@ func GetAuthenUser(c *gin.Context, roles string) *models.AuthenUserEmp {
 		BusinessArea:  jwt.BusinessArea,
 		ImageUrl:      jwt.ImageUrl,
 		Roles:         jwt.Roles,
+		Regions:       jwt.Regions,
 		LoginBy:       jwt.LoginBy,
 		IsEmployee:    jwt.IsEmployee,
 		LevelCode:     jwt.LevelCode,
+		SessionUID:    jwt.SessionUID,
+
+		IsImpersonated:       jwt.IsImpersonated,
+		ImpersonatorEmpID:    jwt.ImpersonatorEmpID,
+		ImpersonatorFullName: jwt.ImpersonatorFullName,
 	}
 
 	for _, role := range strings.Split(roles, ",") {
@@ -292,21 +339,22 @@ func GetAuthenUser(c *gin.Context, roles string) *models.AuthenUserEmp {
 		if role == "approval-department" {
 			CheckApproverRole(&empUser)
 		}
+		if role == "admin-region" {
+			CheckRegionAdminRole(&empUser)
+		}
 	}
+	CheckApprovalChainRole(&empUser)
+	CheckDelegatedRoles(&empUser)
 
 	if empUser.LevelCode == "M5" {
 		empUser.IsLevelM5 = "1"
 	} else {
 		empUser.IsLevelM5 = "0"
 	}
-	if roles == "*" {
+	if HasAnyRole(&empUser, roles) {
+		c.Set(authenUserKey, &empUser)
 		return &empUser
 	}
-	for _, role := range strings.Split(roles, ",") {
-		if Contains(empUser.Roles, role) {
-			return &empUser
-		}
-	}
 
 	c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
 	c.Abort()
@@ -330,6 +378,7 @@ func GetUserEmpInfo(empID string) models.MasUserEmp {
 		TelInternal:   user.DeskPhone,
 		BusinessArea:  user.BusinessArea,
 		BureauDeptSap: user.BureauDeptSap,
+		LevelCode:     user.LevelCode,
 		IsEmployee:    user.IsEmployee,
 	}
 	return empUser
@@ -358,11 +407,13 @@ func SetQueryApproverRole(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
 		`exists (
 			select 1 from vms_mas_carpool_approver ca 
 			where ca.mas_carpool_uid = vms_trn_request.mas_carpool_uid 
-			and ca.approver_emp_no = ? and ca.is_deleted = '0' and ca.is_active = '1' 
-			) or (vms_trn_request.mas_carpool_uid is null and approved_request_emp_id = ?)
+			and ca.approver_emp_no in (?) and ca.is_deleted = '0' and ca.is_active = '1' 
+			) or (vms_trn_request.mas_carpool_uid is null and approved_request_emp_id in (?))
+			or vms_trn_request.trn_request_uid in (?)
 		`,
-		user.EmpID,
-		user.EmpID,
+		GetApprovalEmpIDs(user, "approval-carpool"),
+		GetApprovalEmpIDs(user, "approval-department"),
+		SetQueryApprovalStepApprover(user, "40"),
 	)
 	return query
 }
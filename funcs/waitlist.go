@@ -0,0 +1,200 @@
+package funcs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/models"
+	"vms_plus_be/userhub"
+
+	"github.com/google/uuid"
+)
+
+var WaitlistStatusNameMap = map[string]string{
+	"0": "รอคิว",
+	"1": "รอยืนยันการจอง",
+	"2": "ยืนยันการจองแล้ว",
+	"3": "หมดเวลายืนยัน",
+	"9": "ยกเลิก",
+}
+
+// IsVehicleOnHold checks whether a vehicle is held for another waitlisted user in the given window
+func IsVehicleOnHold(masVehicleUID string, startDatetime, endDatetime time.Time, empID string) bool {
+	var count int64
+	if err := config.DB.Model(&models.VmsTrnWaitlist{}).
+		Where("waitlist_status_code = ? AND is_deleted = ?", "1", "0").
+		Where("offered_mas_vehicle_uid = ? AND hold_expire_datetime > ? AND emp_id <> ?", masVehicleUID, time.Now(), empID).
+		Where("start_datetime < ? AND end_datetime > ?", endDatetime, startDatetime).
+		Count(&count).Error; err != nil {
+		fmt.Println("Error checking vehicle hold:", err)
+		return false
+	}
+	return count > 0
+}
+
+// PromoteWaitlist offers the vehicle freed by a cancelled or early returned request to the first matching waitlisted user
+func PromoteWaitlist(trnRequestUID string) {
+	var freed models.VmsTrnRequestReservation
+	if err := config.DB.First(&freed, "trn_request_uid = ?", trnRequestUID).Error; err != nil {
+		fmt.Println("Error getting freed request:", err)
+		return
+	}
+	if freed.MasVehicleUID == nil || *freed.MasVehicleUID == "" {
+		return
+	}
+	masVehicleUID := *freed.MasVehicleUID
+	masCarpoolUID := ""
+	if freed.MasCarpoolUID != nil {
+		masCarpoolUID = *freed.MasCarpoolUID
+	}
+
+	// The vehicle is free from now (or the reserved start) until the reserved end, before any early return
+	now := time.Now()
+	freedStart := freed.ReserveStartDatetime.Time
+	if freedStart.Before(now) {
+		freedStart = now
+	}
+	freedEnd := freed.ReserveEndDatetime.Time
+	if freed.OriginalReserveEndDatetime.After(freedEnd) {
+		freedEnd = freed.OriginalReserveEndDatetime.Time
+	}
+	if !freedEnd.After(freedStart) {
+		return
+	}
+
+	var vehicle models.VmsMasVehicleList
+	if err := config.DB.Select("mas_vehicle_uid, \"CarTypeDetail\"").
+		First(&vehicle, "mas_vehicle_uid = ?", masVehicleUID).Error; err != nil {
+		fmt.Println("Error getting freed vehicle:", err)
+		return
+	}
+
+	var waitlists []models.VmsTrnWaitlist
+	if err := config.DB.
+		Where("waitlist_status_code = ? AND is_deleted = ?", "0", "0").
+		Where("start_datetime >= ? AND end_datetime <= ?", freedStart, freedEnd).
+		Where("mas_vehicle_uid = ? OR (coalesce(mas_vehicle_uid, '') = '' AND (mas_carpool_uid = ? OR coalesce(mas_carpool_uid, '') = '') AND (requested_vehicle_type = '' OR requested_vehicle_type = ?))",
+			masVehicleUID, masCarpoolUID, strings.TrimSpace(vehicle.CarType)).
+		Order("created_at").
+		Find(&waitlists).Error; err != nil {
+		fmt.Println("Error getting waitlists:", err)
+		return
+	}
+
+	for _, waitlist := range waitlists {
+		if IsVehicleOnHold(masVehicleUID, waitlist.StartDatetime.Time, waitlist.EndDatetime.Time, waitlist.EmpID) {
+			continue
+		}
+		var count int64
+		if err := config.DB.Table("fn_get_available_vehicles_view (?, ?, ?, ?) av",
+			waitlist.StartDatetime, waitlist.EndDatetime, waitlist.BureauDeptSap, waitlist.BusinessArea).
+			Where("av.mas_vehicle_uid = ?", masVehicleUID).
+			Count(&count).Error; err != nil || count == 0 {
+			continue
+		}
+
+		waitlist.WaitlistStatusCode = "1"
+		waitlist.OfferedMasVehicleUID = &masVehicleUID
+		waitlist.OfferedTrnRequestUID = &freed.TrnRequestUID
+		waitlist.OfferedDatetime = models.TimeWithZone{Time: now}
+		waitlist.HoldExpireDatetime = models.TimeWithZone{Time: now.Add(time.Duration(config.AppConfig.WaitlistHoldMinutes) * time.Minute)}
+		waitlist.UpdatedAt = now
+		waitlist.UpdatedBy = "system"
+		if err := config.DB.Save(&waitlist).Error; err != nil {
+			fmt.Println("Error offering waitlist:", err)
+			return
+		}
+		CreateWaitlistNotification(waitlist.TrnWaitlistUID)
+		return
+	}
+}
+
+// ReleaseWaitlistHold closes an offered hold and passes the vehicle on to the next waitlisted user
+func ReleaseWaitlistHold(waitlist models.VmsTrnWaitlist, waitlistStatusCode, empID string) error {
+	waitlist.WaitlistStatusCode = waitlistStatusCode
+	waitlist.UpdatedAt = time.Now()
+	waitlist.UpdatedBy = empID
+	if err := config.DB.Save(&waitlist).Error; err != nil {
+		return err
+	}
+	CreateWaitlistNotification(waitlist.TrnWaitlistUID)
+	if waitlist.OfferedTrnRequestUID != nil && *waitlist.OfferedTrnRequestUID != "" {
+		PromoteWaitlist(*waitlist.OfferedTrnRequestUID)
+	}
+	return nil
+}
+
+func JobWaitlistExpireHolds() {
+	var waitlists []models.VmsTrnWaitlist
+	if err := config.DB.
+		Where("waitlist_status_code = ? AND hold_expire_datetime < ? AND is_deleted = ?", "1", time.Now(), "0").
+		Find(&waitlists).Error; err != nil {
+		fmt.Println("Error getting expired waitlist holds:", err)
+		return
+	}
+	for _, waitlist := range waitlists {
+		if err := ReleaseWaitlistHold(waitlist, "3", "system"); err != nil {
+			fmt.Println("Error expiring waitlist hold:", err)
+		}
+	}
+}
+
+func CreateWaitlistNotification(trnWaitlistUID string) {
+	var waitlist models.VmsTrnWaitlist
+	if err := config.DB.Where("trn_waitlist_uid = ?", trnWaitlistUID).First(&waitlist).Error; err != nil {
+		fmt.Println("Error getting waitlist:", err)
+		return
+	}
+
+	var notifyTemplates []models.NotificationTemplate
+	if err := config.DB.Where("ref_request_status_code = ? AND is_deleted = false AND notify_type = 'request-waitlist'", waitlist.WaitlistStatusCode).Find(&notifyTemplates).Error; err != nil {
+		fmt.Println("Error getting notify templates:", err)
+		return
+	}
+
+	var vehicleLicensePlate string
+	if waitlist.OfferedMasVehicleUID != nil {
+		config.DB.Table("vms_mas_vehicle").Where("mas_vehicle_uid = ?", waitlist.OfferedMasVehicleUID).
+			Select("vehicle_license_plate").Scan(&vehicleLicensePlate)
+	}
+
+	for _, notifyTemplate := range notifyTemplates {
+		var notifyEmpID string
+		notifyMessage := notifyTemplate.NotifyMessage
+		notifyMessage = strings.Replace(notifyMessage, "**start_datetime**", GetDateTimeBuddhistYear(waitlist.StartDatetime.Time), -1)
+		notifyMessage = strings.Replace(notifyMessage, "**end_datetime**", GetDateTimeBuddhistYear(waitlist.EndDatetime.Time), -1)
+		notifyMessage = strings.Replace(notifyMessage, "**hold_expire_datetime**", GetDateTimeBuddhistYear(waitlist.HoldExpireDatetime.Time), -1)
+		notifyMessage = strings.Replace(notifyMessage, "**vehicle_license_plate**", strings.TrimSpace(vehicleLicensePlate), -1)
+		switch notifyTemplate.NotifyRole {
+		case "vehicle-user":
+			notifyEmpID = waitlist.EmpID
+		}
+		if notifyEmpID != "" {
+			notification := models.Notification{
+				TrnNotifyUID:         uuid.New().String(),
+				EmpID:                notifyEmpID,
+				Title:                notifyTemplate.NotifyTitle,
+				Message:              notifyMessage,
+				RecordUID:            waitlist.TrnWaitlistUID,
+				NotifyType:           notifyTemplate.NotifyType,
+				NotifyRole:           notifyTemplate.NotifyRole,
+				RefRequestStatusCode: waitlist.WaitlistStatusCode,
+				IsRead:               false,
+				CreatedAt:            time.Now(),
+			}
+			if err := config.DB.Create(&notification).Error; err != nil {
+				fmt.Println("Error creating notification:", err)
+				return
+			}
+			userInfo, err := userhub.GetUserInfo(notifyEmpID)
+			if err != nil {
+				fmt.Println("Error getting user info:", err)
+				return
+			}
+			go SendNotificationWorkD(notifyEmpID, notifyTemplate.NotifyTitle, notifyMessage, "", GetNotifyURL(notification), userInfo.DeptSAP, userInfo.DeptSAPShort)
+			go SendNotificationPEA(notifyEmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+			go SendNotificationSMS(notifyEmpID, notifyTemplate.NotifyTitle+" "+notifyMessage)
+		}
+	}
+}
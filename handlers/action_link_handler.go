@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+	"vms_plus_be/userhub"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ActionLinkHandler struct {
+}
+
+// VerifyActionLink godoc
+// @Summary Check a one-time action link
+// @Description This endpoint checks the token of an approve or reject link from a notification without using it, and returns the request and decision it is bound to.
+// @Tags Action-link
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param token query string true "Action token from the link"
+// @Router /api/action-link/verify [get]
+func (h *ActionLinkHandler) VerifyActionLink(c *gin.Context) {
+	actionToken, requestNo, err := funcs.ParseActionToken(c.Query("token"))
+	if err != nil {
+		c.JSON(http.StatusGone, gin.H{"error": "Action link can not be used", "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.VmsTrnActionTokenVerify{VmsTrnActionToken: actionToken, RequestNo: requestNo})
+}
+
+// ApplyActionLink godoc
+// @Summary Approve or reject a request from a one-time action link
+// @Description This endpoint applies the decision bound to the token as the approver it was issued to. The token can be used once and stops working when the request status has changed.
+// @Tags Action-link
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param data body models.VmsTrnActionTokenApply true "VmsTrnActionTokenApply data"
+// @Router /api/action-link/apply [post]
+func (h *ActionLinkHandler) ApplyActionLink(c *gin.Context) {
+	var request models.VmsTrnActionTokenApply
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	actionToken, requestNo, err := funcs.ParseActionToken(request.Token)
+	if err != nil {
+		c.JSON(http.StatusGone, gin.H{"error": "Action link can not be used", "message": err.Error()})
+		return
+	}
+	if actionToken.ActionCode == "reject" && request.RejectedReason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rejected_request_reason is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	user, err := userhub.GetUserInfo(actionToken.EmpID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if !funcs.UseActionToken(actionToken, c.ClientIP(), c.Request.UserAgent()) {
+		c.JSON(http.StatusGone, gin.H{"error": "Action link can not be used", "message": messages.ErrActionLinkUsed.Error()})
+		return
+	}
+
+	if err := h.applyAction(&user, actionToken, request.RejectedReason); err != nil {
+		funcs.ReleaseActionToken(actionToken)
+		if errors.Is(err, messages.ErrBookingCannotUpdate) || errors.Is(err, messages.ErrAnnualCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Request can not update", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	funcs.ExpireActionTokens(actionToken)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": models.VmsTrnActionTokenVerify{VmsTrnActionToken: actionToken, RequestNo: requestNo}})
+}
+
+// applyAction runs the same transition as the approver's own endpoint and records that it came from a link
+func (h *ActionLinkHandler) applyAction(user *models.AuthenUserEmp, actionToken models.VmsTrnActionToken, rejectedReason string) error {
+	const actionDetail = "ดำเนินการผ่านลิงก์ในการแจ้งเตือน"
+	uid := actionToken.RecordUID
+	isApprove := actionToken.ActionCode == "approve"
+	role := funcs.ActionLinkStepRoles[actionToken.ActionStep]
+
+	var err error
+	switch actionToken.ActionStep {
+	case "booking-confirmer":
+		handler := BookingConfirmerHandler{Role: role}
+		if isApprove {
+			err = handler.updateApproved(user, &models.VmsTrnRequestConfirmed{TrnRequestUID: uid}, "")
+		} else {
+			err = handler.updateRejected(user, &models.VmsTrnRequestRejected{TrnRequestUID: uid, RejectedRequestReason: rejectedReason})
+		}
+	case "booking-final":
+		handler := BookingFinalHandler{Role: role}
+		if isApprove {
+			err = handler.updateApproved(user, &models.VmsTrnRequestApproved{TrnRequestUID: uid}, "")
+		} else {
+			err = handler.updateRejected(user, &models.VmsTrnRequestRejected{TrnRequestUID: uid, RejectedRequestReason: rejectedReason})
+		}
+	case "license-confirmer":
+		handler := DriverLicenseConfirmerHandler{Role: role}
+		if isApprove {
+			err = handler.updateDriverLicenseAnnualConfirmed(user, &models.VmsDriverLicenseAnnualConfirmed{TrnRequestAnnualDriverUID: uid})
+		} else {
+			err = handler.updateDriverLicenseAnnualRejected(user, &models.VmsDriverLicenseAnnualRejected{TrnRequestAnnualDriverUID: uid, RejectedRequestReason: rejectedReason})
+		}
+	case "license-approver":
+		handler := DriverLicenseApproverHandler{Role: role}
+		if isApprove {
+			err = handler.updateDriverLicenseAnnualApproved(user, &models.VmsDriverLicenseAnnualApproved{TrnRequestAnnualDriverUID: uid})
+		} else {
+			err = handler.updateDriverLicenseAnnualRejected(user, &models.VmsDriverLicenseAnnualRejected{TrnRequestAnnualDriverUID: uid, RejectedRequestReason: rejectedReason})
+		}
+	default:
+		return messages.ErrActionLinkInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	//log the status the record is in now, an approval chain step keeps the request in the same status
+	statusCode, _, err := funcs.GetActionRecordStatus(actionToken.ActionStep, uid)
+	if err != nil {
+		fmt.Println("Error getting action link record status:", err)
+		return nil
+	}
+	switch actionToken.ActionStep {
+	case "booking-confirmer", "booking-final":
+		funcs.CreateTrnRequestEventLog(uid, statusCode, actionDetail, user.EmpID, role, "")
+	case "license-confirmer", "license-approver":
+		funcs.CreateTrnRequestAnnualLicenseEventLog(uid, statusCode, actionDetail, user.EmpID, role, "")
+	}
+	return nil
+}
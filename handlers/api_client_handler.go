@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ApiClientHandler struct {
+	Role string
+}
+
+// reloadApiClients makes a change of the registry take effect on this replica right away
+func (h *ApiClientHandler) reloadApiClients() {
+	if err := funcs.ReloadApiClients(); err != nil {
+		fmt.Println("Error loading API clients:", err)
+	}
+}
+
+// createApiClientKey issues a key for the client and returns it, the key is not kept and cannot be shown again
+func (h *ApiClientHandler) createApiClientKey(c *gin.Context, user *models.AuthenUserEmp, masApiClientUID string, expiresAt *time.Time) (string, models.VmsMasApiClientKey, error) {
+	apiKey, keyPrefix, err := funcs.GenerateApiKey()
+	if err != nil {
+		return "", models.VmsMasApiClientKey{}, err
+	}
+	key := models.VmsMasApiClientKey{
+		MasApiClientKeyUID: uuid.New().String(),
+		MasApiClientUID:    masApiClientUID,
+		KeyPrefix:          keyPrefix,
+		KeyHash:            funcs.HashApiKey(apiKey),
+		ExpiresAt:          expiresAt,
+		IsActive:           "1",
+		CreatedAt:          time.Now(),
+		CreatedBy:          user.EmpID,
+		UpdatedAt:          time.Now(),
+		UpdatedBy:          user.EmpID,
+	}
+	if err := config.DB.Create(&key).Error; err != nil {
+		return "", models.VmsMasApiClientKey{}, err
+	}
+	funcs.AuditMasterDataRow(c, user, "vms_mas_api_client_key", "mas_api_client_key_uid", key.MasApiClientKeyUID, nil)
+	return apiKey, key, nil
+}
+
+// SearchApiClients godoc
+// @Summary Search API clients
+// @Description This endpoint lists the applications allowed to call the API with their scopes and keys. Keys are shown by prefix only.
+// @Tags API-client
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param search query string false "Search client_code or client_name"
+// @Param is_active query string false "Filter by is_active"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of records per page (default: 10)"
+// @Router /api/api-client/search [get]
+func (h *ApiClientHandler) SearchApiClients(c *gin.Context) {
+	funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := config.DB.Model(&models.VmsMasApiClient{}).Where("is_deleted = ?", "0")
+	if search := c.Query("search"); search != "" {
+		query = query.Where("client_code ILIKE ? OR client_name ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		query = query.Where("is_active = ?", isActive)
+	}
+
+	// Pagination
+	page := c.DefaultQuery("page", "1")
+	limit := c.DefaultQuery("limit", "10")
+	var pageInt, pageSizeInt int
+	fmt.Sscanf(page, "%d", &pageInt)
+	fmt.Sscanf(limit, "%d", &pageSizeInt)
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 10
+	}
+	offset := (pageInt - 1) * pageSizeInt
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	var apiClients []models.VmsMasApiClient
+	if err := query.Preload("Keys", func(db *gorm.DB) *gorm.DB {
+		return db.Where("is_active = ?", "1").Order("created_at desc")
+	}).Order("client_code").Offset(offset).Limit(pageSizeInt).Find(&apiClients).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pagination": gin.H{
+			"total":      total,
+			"page":       page,
+			"limit":      pageSizeInt,
+			"totalPages": (total + int64(pageSizeInt) - 1) / int64(pageSizeInt),
+		},
+		"api_clients": apiClients,
+	})
+}
+
+// CreateApiClient godoc
+// @Summary Register an API client
+// @Description This endpoint registers an application and issues its first key. api_key is shown only in this response. Scopes are route groups, the path segment after /api/, such as booking-user; "booking-*" covers every group starting with booking- and "*" covers all.
+// @Tags API-client
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsMasApiClientRequest true "VmsMasApiClientRequest data"
+// @Router /api/api-client/create [post]
+func (h *ApiClientHandler) CreateApiClient(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasApiClientRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	var count int64
+	config.DB.Model(&models.VmsMasApiClient{}).Where("client_code = ? AND is_deleted = ?", request.ClientCode, "0").Count(&count)
+	if count > 0 || request.ClientCode == funcs.EnvApiClientCode {
+		c.JSON(http.StatusConflict, gin.H{"error": "API client already exists", "message": messages.ErrAlreadyExist.Error()})
+		return
+	}
+
+	apiClient := models.VmsMasApiClient{
+		MasApiClientUID: uuid.New().String(),
+		ClientCode:      request.ClientCode,
+		ClientName:      request.ClientName,
+		Scopes:          strings.Join(funcs.GetApiScopes(strings.Join(request.Scopes, ",")), ","),
+		IsActive:        request.IsActive,
+		IsDeleted:       "0",
+		CreatedAt:       time.Now(),
+		CreatedBy:       user.EmpID,
+		UpdatedAt:       time.Now(),
+		UpdatedBy:       user.EmpID,
+	}
+	if apiClient.IsActive != "0" {
+		apiClient.IsActive = "1"
+	}
+	if err := config.DB.Create(&apiClient).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	funcs.AuditMasterDataRow(c, user, "vms_mas_api_client", "mas_api_client_uid", apiClient.MasApiClientUID, nil)
+
+	apiKey, key, err := h.createApiClientKey(c, user, apiClient.MasApiClientUID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	apiClient.Keys = []models.VmsMasApiClientKey{key}
+	h.reloadApiClients()
+
+	c.JSON(http.StatusCreated, gin.H{"message": "API client created successfully", "result": apiClient, "api_key": apiKey})
+}
+
+// UpdateApiClient godoc
+// @Summary Update an API client
+// @Description This endpoint changes the name, the scopes or the active status of an API client. It takes effect on every replica within a minute.
+// @Tags API-client
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_api_client_uid path string true "MasApiClientUID (mas_api_client_uid)"
+// @Param data body models.VmsMasApiClientRequest true "VmsMasApiClientRequest data"
+// @Router /api/api-client/update/{mas_api_client_uid} [put]
+func (h *ApiClientHandler) UpdateApiClient(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasApiClientRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var apiClient models.VmsMasApiClient
+	if err := config.DB.Where("mas_api_client_uid = ? AND is_deleted = ?", c.Param("mas_api_client_uid"), "0").First(&apiClient).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API client not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	if request.ClientCode != apiClient.ClientCode {
+		var count int64
+		config.DB.Model(&models.VmsMasApiClient{}).Where("client_code = ? AND is_deleted = ?", request.ClientCode, "0").Count(&count)
+		if count > 0 || request.ClientCode == funcs.EnvApiClientCode {
+			c.JSON(http.StatusConflict, gin.H{"error": "API client already exists", "message": messages.ErrAlreadyExist.Error()})
+			return
+		}
+	}
+
+	before := funcs.GetMasterDataSnapshot("vms_mas_api_client", "mas_api_client_uid", apiClient.MasApiClientUID)
+	apiClient.ClientCode = request.ClientCode
+	apiClient.ClientName = request.ClientName
+	apiClient.Scopes = strings.Join(funcs.GetApiScopes(strings.Join(request.Scopes, ",")), ",")
+	apiClient.IsActive = request.IsActive
+	if apiClient.IsActive != "0" {
+		apiClient.IsActive = "1"
+	}
+	apiClient.UpdatedAt = time.Now()
+	apiClient.UpdatedBy = user.EmpID
+	if err := config.DB.Save(&apiClient).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update: %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	funcs.AuditMasterDataRow(c, user, "vms_mas_api_client", "mas_api_client_uid", apiClient.MasApiClientUID, before)
+	h.reloadApiClients()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": apiClient})
+}
+
+// DeleteApiClient godoc
+// @Summary Delete an API client
+// @Description This endpoint removes an API client, every key of the client stops working.
+// @Tags API-client
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_api_client_uid path string true "MasApiClientUID (mas_api_client_uid)"
+// @Router /api/api-client/delete/{mas_api_client_uid} [delete]
+func (h *ApiClientHandler) DeleteApiClient(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var apiClient models.VmsMasApiClient
+	if err := config.DB.Where("mas_api_client_uid = ? AND is_deleted = ?", c.Param("mas_api_client_uid"), "0").First(&apiClient).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API client not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	before := funcs.GetMasterDataSnapshot("vms_mas_api_client", "mas_api_client_uid", apiClient.MasApiClientUID)
+	if err := config.DB.Model(&apiClient).UpdateColumns(map[string]interface{}{
+		"is_deleted": "1",
+		"updated_by": user.EmpID,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete API client", "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	funcs.AuditMasterDataRow(c, user, "vms_mas_api_client", "mas_api_client_uid", apiClient.MasApiClientUID, before)
+	h.reloadApiClients()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// CreateApiClientKey godoc
+// @Summary Issue another key for an API client
+// @Description This endpoint issues a key next to the keys the client already has. api_key is shown only in this response.
+// @Tags API-client
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_api_client_uid path string true "MasApiClientUID (mas_api_client_uid)"
+// @Param data body models.VmsMasApiClientKeyRequest true "VmsMasApiClientKeyRequest data"
+// @Router /api/api-client/create-key/{mas_api_client_uid} [post]
+func (h *ApiClientHandler) CreateApiClientKey(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasApiClientKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	var apiClient models.VmsMasApiClient
+	if err := config.DB.Where("mas_api_client_uid = ? AND is_deleted = ?", c.Param("mas_api_client_uid"), "0").First(&apiClient).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API client not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	apiKey, key, err := h.createApiClientKey(c, user, apiClient.MasApiClientUID, request.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	h.reloadApiClients()
+
+	c.JSON(http.StatusCreated, gin.H{"message": "API key created successfully", "result": key, "api_key": apiKey})
+}
+
+// RotateApiClientKey godoc
+// @Summary Rotate a key of an API client
+// @Description This endpoint issues a key to replace the given one. The replaced key keeps working for overlap_hours so the client can switch over. api_key is shown only in this response.
+// @Tags API-client
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_api_client_key_uid path string true "MasApiClientKeyUID (mas_api_client_key_uid)"
+// @Param data body models.VmsMasApiClientKeyRequest true "VmsMasApiClientKeyRequest data"
+// @Router /api/api-client/rotate-key/{mas_api_client_key_uid} [post]
+func (h *ApiClientHandler) RotateApiClientKey(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasApiClientKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if request.OverlapHours < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "overlap_hours must not be negative", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+	var oldKey models.VmsMasApiClientKey
+	if err := config.DB.Where("mas_api_client_key_uid = ? AND is_active = ?", c.Param("mas_api_client_key_uid"), "1").First(&oldKey).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	apiKey, key, err := h.createApiClientKey(c, user, oldKey.MasApiClientUID, request.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	before := funcs.GetMasterDataSnapshot("vms_mas_api_client_key", "mas_api_client_key_uid", oldKey.MasApiClientKeyUID)
+	overlapUntil := time.Now().Add(time.Duration(request.OverlapHours) * time.Hour)
+	if oldKey.ExpiresAt == nil || oldKey.ExpiresAt.After(overlapUntil) {
+		if err := config.DB.Model(&oldKey).UpdateColumns(map[string]interface{}{
+			"expires_at": overlapUntil,
+			"updated_by": user.EmpID,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+			return
+		}
+		oldKey.ExpiresAt = &overlapUntil
+		funcs.AuditMasterDataRow(c, user, "vms_mas_api_client_key", "mas_api_client_key_uid", oldKey.MasApiClientKeyUID, before)
+	}
+	h.reloadApiClients()
+
+	c.JSON(http.StatusCreated, gin.H{"message": "API key rotated successfully", "result": key, "api_key": apiKey, "previous_key_expires_at": oldKey.ExpiresAt})
+}
+
+// RevokeApiClientKey godoc
+// @Summary Revoke a key of an API client
+// @Description This endpoint stops a key right away, for example when it has leaked.
+// @Tags API-client
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_api_client_key_uid path string true "MasApiClientKeyUID (mas_api_client_key_uid)"
+// @Router /api/api-client/revoke-key/{mas_api_client_key_uid} [delete]
+func (h *ApiClientHandler) RevokeApiClientKey(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var key models.VmsMasApiClientKey
+	if err := config.DB.Where("mas_api_client_key_uid = ? AND is_active = ?", c.Param("mas_api_client_key_uid"), "1").First(&key).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	before := funcs.GetMasterDataSnapshot("vms_mas_api_client_key", "mas_api_client_key_uid", key.MasApiClientKeyUID)
+	if err := config.DB.Model(&key).UpdateColumns(map[string]interface{}{
+		"is_active":  "0",
+		"updated_by": user.EmpID,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key", "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	funcs.AuditMasterDataRow(c, user, "vms_mas_api_client_key", "mas_api_client_key_uid", key.MasApiClientKeyUID, before)
+	h.reloadApiClients()
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
+
+// GetApiClientUsage godoc
+// @Summary Retrieve the usage of API clients
+// @Description This endpoint returns the number of calls per client, day and route group. Counts are written once a minute. Calls made with API_KEY are counted under mas_api_client_uid env.
+// @Tags API-client
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_api_client_uid query string false "Filter by MasApiClientUID (mas_api_client_uid)"
+// @Param route_group query string false "Filter by route group"
+// @Param startdate query string false "Filter by usage date from (YYYY-MM-DD format)"
+// @Param enddate query string false "Filter by usage date to (YYYY-MM-DD format)"
+// @Router /api/api-client/usage [get]
+func (h *ApiClientHandler) GetApiClientUsage(c *gin.Context) {
+	funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := config.DB.Model(&models.VmsTrnApiClientUsage{})
+	if masApiClientUID := c.Query("mas_api_client_uid"); masApiClientUID != "" {
+		query = query.Where("mas_api_client_uid = ?", masApiClientUID)
+	}
+	if routeGroup := c.Query("route_group"); routeGroup != "" {
+		query = query.Where("route_group = ?", routeGroup)
+	}
+	if startDate := c.Query("startdate"); startDate != "" {
+		query = query.Where("usage_date >= ?", startDate)
+	}
+	if endDate := c.Query("enddate"); endDate != "" {
+		query = query.Where("usage_date <= ?", endDate)
+	}
+
+	var usages []models.VmsTrnApiClientUsage
+	if err := query.Order("usage_date desc, mas_api_client_uid, route_group").Find(&usages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"usages": usages})
+}
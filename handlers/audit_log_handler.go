@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tealeg/xlsx"
+	"gorm.io/gorm"
+)
+
+type AuditLogHandler struct {
+	Role string
+}
+
+// SetQueryFilter applies the search criteria shared by SearchAuditLogs and ExportAuditLogs
+func (h *AuditLogHandler) SetQueryFilter(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if entityType := c.Query("entity_type"); entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if entityUID := c.Query("entity_uid"); entityUID != "" {
+		query = query.Where("entity_uid = ?", entityUID)
+	}
+	if operation := c.Query("operation"); operation != "" {
+		query = query.Where("operation = ?", operation)
+	}
+	if actionBy := c.Query("action_by"); actionBy != "" {
+		query = query.Where("action_by_personal_id = ?", actionBy)
+	}
+	if requestID := c.Query("request_id"); requestID != "" {
+		query = query.Where("request_id = ?", requestID)
+	}
+	if search := c.Query("search"); search != "" {
+		query = query.Where("action_by_fullname ILIKE ? OR entity_uid ILIKE ? OR log_master_data_audit_uid in (select f.log_master_data_audit_uid from vms_log_master_data_audit_field f where f.old_value ILIKE ? OR f.new_value ILIKE ?)", "%"+search+"%", "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	}
+	if startDate := c.Query("startdate"); startDate != "" {
+		query = query.Where("action_datetime::date >= ?", startDate)
+	}
+	if endDate := c.Query("enddate"); endDate != "" {
+		query = query.Where("action_datetime::date <= ?", endDate)
+	}
+	return query
+}
+
+// SearchAuditLogs godoc
+// @Summary Search the master-data audit log
+// @Description This endpoint lists master-data changes with the columns each change touched, newest first. Entries cannot be edited or deleted.
+// @Tags Audit-log
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param entity_type query string false "Filter by table, e.g. vms_mas_carpool, vms_mas_driver"
+// @Param entity_uid query string false "Filter by the uid of the changed row"
+// @Param operation query string false "Filter by operation: create, update, delete"
+// @Param action_by query string false "Filter by the personal id of the actor"
+// @Param request_id query string false "Filter by X-Request-ID"
+// @Param search query string false "Search actor name, entity uid or a changed value"
+// @Param startdate query string false "Filter by action date from (YYYY-MM-DD format)"
+// @Param enddate query string false "Filter by action date to (YYYY-MM-DD format)"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of records per page (default: 10)"
+// @Router /api/audit-log/search [get]
+func (h *AuditLogHandler) SearchAuditLogs(c *gin.Context) {
+	funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := h.SetQueryFilter(c, config.DB.Model(&models.VmsLogMasterDataAudit{}))
+
+	// Pagination
+	page := c.DefaultQuery("page", "1")
+	limit := c.DefaultQuery("limit", "10")
+	var pageInt, pageSizeInt int
+	fmt.Sscanf(page, "%d", &pageInt)
+	fmt.Sscanf(limit, "%d", &pageSizeInt)
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 10
+	}
+	offset := (pageInt - 1) * pageSizeInt
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	var auditLogs []models.VmsLogMasterDataAudit
+	if err := query.Preload("Fields").Order("action_datetime desc").Offset(offset).Limit(pageSizeInt).Find(&auditLogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pagination": gin.H{
+			"total":      total,
+			"page":       page,
+			"limit":      pageSizeInt,
+			"totalPages": (total + int64(pageSizeInt) - 1) / int64(pageSizeInt),
+		},
+		"audit_logs": auditLogs,
+	})
+}
+
+// ExportAuditLogs godoc
+// @Summary Export the master-data audit log
+// @Description Export master-data changes by criteria, one row per changed column
+// @Tags Audit-log
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param entity_type query string false "Filter by table, e.g. vms_mas_carpool, vms_mas_driver"
+// @Param entity_uid query string false "Filter by the uid of the changed row"
+// @Param operation query string false "Filter by operation: create, update, delete"
+// @Param action_by query string false "Filter by the personal id of the actor"
+// @Param request_id query string false "Filter by X-Request-ID"
+// @Param search query string false "Search actor name, entity uid or a changed value"
+// @Param startdate query string false "Filter by action date from (YYYY-MM-DD format)"
+// @Param enddate query string false "Filter by action date to (YYYY-MM-DD format)"
+// @Router /api/audit-log/export [get]
+func (h *AuditLogHandler) ExportAuditLogs(c *gin.Context) {
+	funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var auditLogs []models.VmsLogMasterDataAudit
+	query := h.SetQueryFilter(c, config.DB.Model(&models.VmsLogMasterDataAudit{}))
+	if err := query.Preload("Fields").Order("action_datetime desc").Find(&auditLogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	// Create Excel file
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("AuditLogs")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create Excel sheet", "message": err.Error()})
+		return
+	}
+
+	// Set headers
+	headers := []string{
+		"วันที่ทำรายการ",
+		"ข้อมูล",
+		"รหัสข้อมูล",
+		"การดำเนินการ",
+		"รหัสผู้ทำรายการ",
+		"ชื่อผู้ทำรายการ",
+		"บทบาท",
+		"IP Address",
+		"Request ID",
+		"ฟิลด์",
+		"ค่าเดิม",
+		"ค่าใหม่",
+	}
+
+	headerRow := sheet.AddRow()
+	for _, header := range headers {
+		cell := headerRow.AddCell()
+		cell.Value = header
+	}
+
+	for _, auditLog := range auditLogs {
+		for _, field := range auditLog.Fields {
+			row := sheet.AddRow()
+			row.AddCell().Value = auditLog.ActionDatetime.Format("2006-01-02 15:04:05")
+			row.AddCell().Value = auditLog.EntityType
+			row.AddCell().Value = auditLog.EntityUID
+			row.AddCell().Value = auditLog.Operation
+			row.AddCell().Value = auditLog.ActionByPersonalID
+			row.AddCell().Value = auditLog.ActionByFullname
+			row.AddCell().Value = auditLog.ActionByRole
+			row.AddCell().Value = auditLog.IPAddress
+			row.AddCell().Value = auditLog.RequestID
+			row.AddCell().Value = field.FieldName
+			row.AddCell().Value = field.OldValue
+			row.AddCell().Value = field.NewValue
+		}
+	}
+	// Add style to the header row (bold, background color)
+	headerStyle := xlsx.NewStyle()
+	font := xlsx.DefaultFont()
+	font.Bold = true
+	headerStyle.Font = *font
+	headerStyle.ApplyFont = true
+	headerStyle.Font.Color = "FFFFFF"
+	headerStyle.Fill = *xlsx.NewFill("solid", "4F81BD", "4F81BD")
+	headerStyle.ApplyFill = true
+	headerStyle.Alignment.Horizontal = "center"
+	headerStyle.Alignment.Vertical = "center"
+	headerStyle.ApplyAlignment = true
+	headerStyle.Border = xlsx.Border{
+		Left:   "thin",
+		Top:    "thin",
+		Bottom: "thin",
+		Right:  "thin",
+	}
+	headerStyle.ApplyBorder = true
+
+	// Apply style and auto-size columns for header row
+	for i, cell := range headerRow.Cells {
+		cell.SetStyle(headerStyle)
+		// Auto-size columns (set a default width)
+		col := sheet.Col(i)
+		if col != nil {
+			col.Width = 20
+		}
+	}
+	c.Header("Content-Disposition", "attachment; filename=audit_logs.xlsx")
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("File-Name", fmt.Sprintf("audit_logs_%s.xlsx", time.Now().Format("2006-01-02")))
+	c.Header("Content-Transfer-Encoding", "binary")
+	if err := file.Write(c.Writer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write Excel file", "message": err.Error()})
+		return
+	}
+}
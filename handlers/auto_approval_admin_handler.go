@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+	"vms_plus_be/permission"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AutoApprovalAdminHandler struct {
+	Role string
+}
+
+func (h *AutoApprovalAdminHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB, carpoolColumn string) *gorm.DB {
+	return permission.SetQueryAdminCarpoolScope(user, query, carpoolColumn)
+}
+
+func (h *AutoApprovalAdminHandler) validateRule(user *models.AuthenUserEmp, request *models.VmsMasAutoApprovalRuleRequest) error {
+	if request.RuleName == "" {
+		return fmt.Errorf("rule_name is required")
+	}
+	if request.MasCarpoolUID == "" {
+		return fmt.Errorf("mas_carpool_uid is required")
+	}
+	if !permission.HasPermission(user, permission.ScopeAll) {
+		var count int64
+		config.DB.Model(&models.VmsMasCarpoolAdmin{}).
+			Where("mas_carpool_uid = ? and admin_emp_no = ? and is_deleted = '0' and is_active = '1'", request.MasCarpoolUID, user.EmpID).
+			Count(&count)
+		if count == 0 {
+			return fmt.Errorf("carpool is not managed by user")
+		}
+	}
+	if request.IsAutoApprove30 != "1" {
+		request.IsAutoApprove30 = "0"
+	}
+	if request.IsAutoApprove40 != "1" {
+		request.IsAutoApprove40 = "0"
+	}
+	if request.IsAutoApprove30 == "0" && request.IsAutoApprove40 == "0" {
+		return fmt.Errorf("is_auto_approve_30 or is_auto_approve_40 is required")
+	}
+	if request.IsSameBusinessArea != "1" {
+		request.IsSameBusinessArea = "0"
+	}
+	if request.IsNoDriver != "1" {
+		request.IsNoDriver = "0"
+	}
+	if request.MaxDurationHours < 0 {
+		return fmt.Errorf("max_duration_hours must not be negative")
+	}
+	// a rule without conditions would approve every request of the carpool
+	if request.MaxDurationHours == 0 && request.IsSameBusinessArea == "0" && request.IsNoDriver == "0" &&
+		request.MinLevelCode == "" && request.RefTripTypeCode == nil {
+		return fmt.Errorf("at least one condition is required")
+	}
+	if request.IsActive != "0" {
+		request.IsActive = "1"
+	}
+	return nil
+}
+
+// SearchAutoApprovalRules godoc
+// @Summary List auto-approval rules
+// @Description This endpoint returns the auto-approval rules of the carpools managed by the user.
+// @Tags Auto-approval-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_carpool_uid query string false "Filter by carpool"
+// @Router /api/auto-approval-admin/search-rules [get]
+func (h *AutoApprovalAdminHandler) SearchAutoApprovalRules(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := h.SetQueryRole(user, config.DB, "mas_carpool_uid").Where("is_deleted = '0'")
+	if masCarpoolUID := c.Query("mas_carpool_uid"); masCarpoolUID != "" {
+		query = query.Where("mas_carpool_uid = ?", masCarpoolUID)
+	}
+
+	var rules []models.VmsMasAutoApprovalRule
+	if err := query.Order("mas_carpool_uid, created_at").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateAutoApprovalRule godoc
+// @Summary Create an auto-approval rule
+// @Description This endpoint creates a rule that approves the requests of a carpool at status 30 and/or 40 when every condition set on the rule matches. Rules are checked in the order they were created.
+// @Tags Auto-approval-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsMasAutoApprovalRuleRequest true "VmsMasAutoApprovalRuleRequest data"
+// @Router /api/auto-approval-admin/create-rule [post]
+func (h *AutoApprovalAdminHandler) CreateAutoApprovalRule(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasAutoApprovalRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if err := h.validateRule(user, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	rule := models.VmsMasAutoApprovalRule{
+		MasAutoApprovalRuleUID: uuid.New().String(),
+		MasCarpoolUID:          request.MasCarpoolUID,
+		RuleName:               request.RuleName,
+		MaxDurationHours:       request.MaxDurationHours,
+		IsSameBusinessArea:     request.IsSameBusinessArea,
+		IsNoDriver:             request.IsNoDriver,
+		MinLevelCode:           request.MinLevelCode,
+		RefTripTypeCode:        request.RefTripTypeCode,
+		IsAutoApprove30:        request.IsAutoApprove30,
+		IsAutoApprove40:        request.IsAutoApprove40,
+		IsActive:               request.IsActive,
+		CreatedAt:              time.Now(),
+		CreatedBy:              user.EmpID,
+		UpdatedAt:              time.Now(),
+		UpdatedBy:              user.EmpID,
+		IsDeleted:              "0",
+	}
+	if err := config.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Rule created successfully", "result": rule})
+}
+
+// UpdateAutoApprovalRule godoc
+// @Summary Update an auto-approval rule
+// @Description This endpoint updates the conditions and statuses of a rule. Set is_active to 0 to stop the rule without deleting it.
+// @Tags Auto-approval-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsMasAutoApprovalRuleRequest true "VmsMasAutoApprovalRuleRequest data"
+// @Router /api/auto-approval-admin/update-rule [put]
+func (h *AutoApprovalAdminHandler) UpdateAutoApprovalRule(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasAutoApprovalRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var rule models.VmsMasAutoApprovalRule
+	query := h.SetQueryRole(user, config.DB, "mas_carpool_uid")
+	if err := query.First(&rule, "mas_auto_approval_rule_uid = ? and is_deleted = '0'", request.MasAutoApprovalRuleUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	request.MasCarpoolUID = rule.MasCarpoolUID
+	if err := h.validateRule(user, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	if err := config.DB.Model(&rule).UpdateColumns(map[string]interface{}{
+		"rule_name":             request.RuleName,
+		"max_duration_hours":    request.MaxDurationHours,
+		"is_same_business_area": request.IsSameBusinessArea,
+		"is_no_driver":          request.IsNoDriver,
+		"min_level_code":        request.MinLevelCode,
+		"ref_trip_type_code":    request.RefTripTypeCode,
+		"is_auto_approve_30":    request.IsAutoApprove30,
+		"is_auto_approve_40":    request.IsAutoApprove40,
+		"is_active":             request.IsActive,
+		"updated_by":            user.EmpID,
+		"updated_at":            time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": rule})
+}
+
+// DeleteAutoApprovalRule godoc
+// @Summary Delete an auto-approval rule
+// @Description This endpoint deletes a rule. Requests it already approved keep their auto-approval records and can still be reverted.
+// @Tags Auto-approval-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_auto_approval_rule_uid path string true "MasAutoApprovalRuleUID (mas_auto_approval_rule_uid)"
+// @Router /api/auto-approval-admin/delete-rule/{mas_auto_approval_rule_uid} [delete]
+func (h *AutoApprovalAdminHandler) DeleteAutoApprovalRule(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var rule models.VmsMasAutoApprovalRule
+	query := h.SetQueryRole(user, config.DB, "mas_carpool_uid")
+	if err := query.First(&rule, "mas_auto_approval_rule_uid = ? and is_deleted = '0'", c.Param("mas_auto_approval_rule_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	if err := config.DB.Model(&rule).UpdateColumns(map[string]interface{}{
+		"is_deleted": "1",
+		"updated_by": user.EmpID,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// DryRunAutoApprovalRule godoc
+// @Summary Test an auto-approval rule against a request
+// @Description This endpoint checks a saved rule (mas_auto_approval_rule_uid) or an unsaved one (rule) against a request and returns the result of every condition. Nothing is approved.
+// @Tags Auto-approval-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsMasAutoApprovalRuleDryRunRequest true "VmsMasAutoApprovalRuleDryRunRequest data"
+// @Router /api/auto-approval-admin/dry-run [post]
+func (h *AutoApprovalAdminHandler) DryRunAutoApprovalRule(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasAutoApprovalRuleDryRunRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var rule models.VmsMasAutoApprovalRule
+	if request.MasAutoApprovalRuleUID != "" {
+		query := h.SetQueryRole(user, config.DB, "mas_carpool_uid")
+		if err := query.First(&rule, "mas_auto_approval_rule_uid = ? and is_deleted = '0'", request.MasAutoApprovalRuleUID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found", "message": messages.ErrNotfound.Error()})
+			return
+		}
+	} else if request.Rule != nil {
+		rule = models.VmsMasAutoApprovalRule{
+			RuleName:           request.Rule.RuleName,
+			MaxDurationHours:   request.Rule.MaxDurationHours,
+			IsSameBusinessArea: request.Rule.IsSameBusinessArea,
+			IsNoDriver:         request.Rule.IsNoDriver,
+			MinLevelCode:       request.Rule.MinLevelCode,
+			RefTripTypeCode:    request.Rule.RefTripTypeCode,
+			IsAutoApprove30:    request.Rule.IsAutoApprove30,
+			IsAutoApprove40:    request.Rule.IsAutoApprove40,
+		}
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mas_auto_approval_rule_uid or rule is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	var count int64
+	query := h.SetQueryRole(user, config.DB.Table("vms_trn_request"), "mas_carpool_uid")
+	if err := query.Where("trn_request_uid = ? and is_deleted = '0'", request.TrnRequestUID).Count(&count).Error; err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Request not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	trnRequest, err := funcs.GetAutoApprovalRequestCheck(request.TrnRequestUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	conditions := funcs.EvaluateAutoApprovalRule(rule, trnRequest)
+	c.JSON(http.StatusOK, gin.H{
+		"rule":                    rule,
+		"ref_request_status_code": trnRequest.RefRequestStatusCode,
+		"is_matched":              funcs.IsAutoApprovalRuleMatched(conditions),
+		"conditions":              conditions,
+	})
+}
+
+// SearchAutoApprovals godoc
+// @Summary List requests approved by auto-approval rules
+// @Description This endpoint lists the statuses auto-approval rules approved, with the rule that matched and whether the approval was reverted.
+// @Tags Auto-approval-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid query string false "Filter by request"
+// @Param mas_auto_approval_rule_uid query string false "Filter by rule"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of records per page (default: 10)"
+// @Router /api/auto-approval-admin/search-auto-approvals [get]
+func (h *AutoApprovalAdminHandler) SearchAutoApprovals(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := config.DB.Table("vms_trn_request_auto_approval a").
+		Select("a.*, r.request_no, r.vehicle_user_emp_name, r.ref_request_status_code, r.reserve_start_datetime").
+		Joins("INNER JOIN vms_trn_request r ON r.trn_request_uid = a.trn_request_uid")
+	query = h.SetQueryRole(user, query, "r.mas_carpool_uid")
+	if trnRequestUID := c.Query("trn_request_uid"); trnRequestUID != "" {
+		query = query.Where("a.trn_request_uid = ?", trnRequestUID)
+	}
+	if ruleUID := c.Query("mas_auto_approval_rule_uid"); ruleUID != "" {
+		query = query.Where("a.mas_auto_approval_rule_uid = ?", ruleUID)
+	}
+
+	// Pagination
+	page := c.DefaultQuery("page", "1")
+	limit := c.DefaultQuery("limit", "10")
+	var pageInt, pageSizeInt int
+	fmt.Sscanf(page, "%d", &pageInt)
+	fmt.Sscanf(limit, "%d", &pageSizeInt)
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 10
+	}
+	offset := (pageInt - 1) * pageSizeInt
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	var autoApprovals []models.VmsTrnRequestAutoApprovalList
+	if err := query.Order("a.approved_datetime desc").Offset(offset).Limit(pageSizeInt).Scan(&autoApprovals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pagination": gin.H{
+			"total":      total,
+			"page":       page,
+			"limit":      pageSizeInt,
+			"totalPages": (total + int64(pageSizeInt) - 1) / int64(pageSizeInt),
+		},
+		"requests": autoApprovals,
+	})
+}
+
+// RevertAutoApproval godoc
+// @Summary Revert an auto-approval
+// @Description This endpoint moves the request back to the status the rule approved so it is approved by hand. The request must still be in the status the rule moved it to. The approver the rule recorded, the notifications of the later status and its SLA tracking are removed.
+// @Tags Auto-approval-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_auto_approval_uid path string true "TrnRequestAutoApprovalUID (trn_request_auto_approval_uid)"
+// @Router /api/auto-approval-admin/revert/{trn_request_auto_approval_uid} [put]
+func (h *AutoApprovalAdminHandler) RevertAutoApproval(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var autoApproval models.VmsTrnRequestAutoApproval
+	query := config.DB.Table("vms_trn_request_auto_approval a").
+		Select("a.*").
+		Joins("INNER JOIN vms_trn_request r ON r.trn_request_uid = a.trn_request_uid")
+	query = h.SetQueryRole(user, query, "r.mas_carpool_uid")
+	if err := query.Where("a.trn_request_auto_approval_uid = ? and a.is_reverted = '0'", c.Param("trn_request_auto_approval_uid")).
+		First(&autoApproval).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Auto approval not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	result, err := funcs.RevertAutoApproval(user, funcs.GetMatchedRole(user, h.Role), autoApproval.TrnRequestAutoApprovalUID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -414,7 +415,7 @@ func (h *BookingAdminHandler) UpdateRejected(c *gin.Context) {
 	if c.IsAborted() {
 		return
 	}
-	var request, trnRequest models.VmsTrnRequestRejected
+	var request models.VmsTrnRequestRejected
 	var result struct {
 		models.VmsTrnRequestRejected
 		models.VmsTrnRequestRequestNo
@@ -424,11 +425,28 @@ func (h *BookingAdminHandler) UpdateRejected(c *gin.Context) {
 		return
 	}
 
+	if err := h.updateRejected(user, &request); err != nil {
+		if errors.Is(err, messages.ErrBookingCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *BookingAdminHandler) updateRejected(user *models.AuthenUserEmp, request *models.VmsTrnRequestRejected) error {
+	var trnRequest models.VmsTrnRequestRejected
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
-		return
+		return messages.ErrBookingCannotUpdate
 	}
 
 	request.RefRequestStatusCode = "31"
@@ -445,13 +463,8 @@ func (h *BookingAdminHandler) UpdateRejected(c *gin.Context) {
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update : %v", err)
 	}
 	funcs.CreateTrnRequestActionLog(request.TrnRequestUID,
 		request.RefRequestStatusCode,
@@ -460,8 +473,7 @@ func (h *BookingAdminHandler) UpdateRejected(c *gin.Context) {
 		"admin-department",
 		request.RejectedRequestReason,
 	)
-
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	return nil
 }
 
 // UpdateApproved godoc
@@ -480,7 +492,6 @@ func (h *BookingAdminHandler) UpdateApproved(c *gin.Context) {
 		return
 	}
 	var request models.VmsTrnRequestApprovedWithRecieiveKey
-	var trnRequest models.VmsTrnRequestList
 	var result struct {
 		models.VmsTrnRequestApprovedWithRecieiveKey
 		models.VmsTrnRequestRequestNo
@@ -489,11 +500,37 @@ func (h *BookingAdminHandler) UpdateApproved(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
 		return
 	}
+
+	if err := h.updateApproved(user, &request, ""); err != nil {
+		if errors.Is(err, messages.ErrBookingCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	var trnRequestList models.VmsTrnRequestList
+	if err := config.DB.First(&trnRequestList, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+	result.RequestNo = trnRequestList.RequestNo
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *BookingAdminHandler) updateApproved(user *models.AuthenUserEmp, request *models.VmsTrnRequestApprovedWithRecieiveKey, remark string) error {
+	var trnRequest models.VmsTrnRequestList
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
-		return
+		return messages.ErrBookingCannotUpdate
 	}
 	request.HandoverUID = uuid.New().String()
 	request.ReceiverType = 0
@@ -501,9 +538,8 @@ func (h *BookingAdminHandler) UpdateApproved(c *gin.Context) {
 	request.CreatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 	request.UpdatedAt = time.Now()
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update : %v", err)
 	}
 
 	empUser := funcs.GetUserEmpInfo(request.ApprovedRequestEmpID)
@@ -522,8 +558,7 @@ func (h *BookingAdminHandler) UpdateApproved(c *gin.Context) {
 		UpdatedBy:                    user.EmpID,
 	}
 	if err := config.DB.Save(&requestStatus).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-		return
+		return fmt.Errorf("failed to update : %v", err)
 	}
 
 	//update vms_trn_request set appointment_key_handover_place,appointment_key_handover_start_datetime,appointment_key_handover_end_datetime
@@ -532,31 +567,94 @@ func (h *BookingAdminHandler) UpdateApproved(c *gin.Context) {
 		Update("appointment_key_handover_place", request.ReceivedKeyPlace).
 		Update("appointment_key_handover_start_datetime", request.ReceivedKeyStartDatetime).
 		Update("appointment_key_handover_end_datetime", request.ReceivedKeyEndDatetime).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-
-	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
-		return
+		return fmt.Errorf("failed to update : %v", err)
 	}
 
-	var trnRequestList models.VmsTrnRequestList
-	if err := config.DB.First(&trnRequestList, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
-		return
-	}
-	result.RequestNo = trnRequestList.RequestNo
-
 	funcs.CreateTrnRequestActionLog(request.TrnRequestUID,
 		requestStatus.RefRequestStatusCode,
 		"รออนุมัติ จากเจ้าของยานพาหนะ",
 		user.EmpID,
 		"admin-department",
-		"",
+		remark,
 	)
 	funcs.CheckMustPassStatus(request.TrnRequestUID)
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	return nil
+}
+
+// UpdateBulkRejected godoc
+// @Summary Send back several booking requests at once
+// @Description This endpoint sends back each listed request independently and reports the result of every item. The remark is used as the reason for all of them.
+// @Tags Booking-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestBulk true "VmsTrnRequestBulk data"
+// @Router /api/booking-admin/update-bulk-rejected [put]
+func (h *BookingAdminHandler) UpdateBulkRejected(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestUIDs, func(trnRequestUID string) error {
+		return h.updateRejected(user, &models.VmsTrnRequestRejected{TrnRequestUID: trnRequestUID, RejectedRequestReason: request.Remark})
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
+}
+
+// UpdateBulkApproved godoc
+// @Summary Approve several booking requests at once
+// @Description This endpoint approves each listed request independently with the same key pickup appointment and reports the result of every item. When approved_request_emp_id is empty the approver already set on each request is kept.
+// @Tags Booking-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestBulkAdminApproved true "VmsTrnRequestBulkAdminApproved data"
+// @Router /api/booking-admin/update-bulk-approved [put]
+func (h *BookingAdminHandler) UpdateBulkApproved(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestBulkAdminApproved
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestUIDs, func(trnRequestUID string) error {
+		approvedRequestEmpID := request.ApprovedRequestEmpID
+		if approvedRequestEmpID == "" {
+			var trnRequest models.VmsTrnRequestApproved
+			if err := config.DB.Select("trn_request_uid, approved_request_emp_id").First(&trnRequest, "trn_request_uid = ?", trnRequestUID).Error; err != nil || trnRequest.ApprovedRequestEmpID == "" {
+				return messages.ErrInvalidRequest
+			}
+			approvedRequestEmpID = trnRequest.ApprovedRequestEmpID
+		}
+		return h.updateApproved(user, &models.VmsTrnRequestApprovedWithRecieiveKey{
+			TrnRequestUID:            trnRequestUID,
+			ReceivedKeyPlace:         request.ReceivedKeyPlace,
+			ReceivedKeyStartDatetime: request.ReceivedKeyStartDatetime,
+			ReceivedKeyEndDatetime:   request.ReceivedKeyEndDatetime,
+			ApprovedRequestEmpID:     approvedRequestEmpID,
+		}, request.Remark)
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
 }
 
 // UpdateCanceled godoc
@@ -622,6 +720,7 @@ func (h *BookingAdminHandler) UpdateCanceled(c *gin.Context) {
 			request.CanceledRequestReason,
 		)
 	}
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -676,6 +775,7 @@ func (h *BookingAdminHandler) UpdateVehicleUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "vehicle-user", &trnRequest, &request, user.EmpID, "admin-department")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -725,6 +825,7 @@ func (h *BookingAdminHandler) UpdateTrip(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "trip", &trnRequest, &request, user.EmpID, "admin-department")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -772,6 +873,7 @@ func (h *BookingAdminHandler) UpdatePickup(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "pickup", &trnRequest, &request, user.EmpID, "admin-department")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -818,6 +920,7 @@ func (h *BookingAdminHandler) UpdateDocument(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "document", &trnRequest, &request, user.EmpID, "admin-department")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -865,6 +968,7 @@ func (h *BookingAdminHandler) UpdateCost(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "cost", &trnRequest, &request, user.EmpID, "admin-department")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -919,6 +1023,7 @@ func (h *BookingAdminHandler) UpdateDriver(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "driver", &trnRequest, &request, user.EmpID, "admin-department")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -958,6 +1063,10 @@ func (h *BookingAdminHandler) UpdateVehicle(c *gin.Context) {
 		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
 		return
 	}
+	if err := funcs.CheckVehicleSeatCapacity(request.TrnRequestUID, request.MasVehicleUID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Vehicle has too few seats for the passengers", "message": messages.ErrPassengerOverSeat.Error()})
+		return
+	}
 
 	var vehicle models.VmsMasVehicleDepartment
 	if err := config.DB.First(&vehicle, "mas_vehicle_uid = ? AND is_deleted = '0'", request.MasVehicleUID).Error; err == nil {
@@ -970,6 +1079,7 @@ func (h *BookingAdminHandler) UpdateVehicle(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "vehicle", &trnRequest, &request, user.EmpID, "admin-department")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -1001,3 +1111,186 @@ func (h *BookingAdminHandler) ExportRequests(c *gin.Context) {
 	query := h.SetQueryRole(user, config.DB)
 	funcs.ExportRequests(c, user, query, StatusNameMapAdmin)
 }
+
+// GetPassengers godoc
+// @Summary Retrieve the passengers of a booking request
+// @Description This endpoint fetches the passenger list of a booking request.
+// @Tags Booking-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/booking-admin/passengers/{trn_request_uid} [get]
+func (h *BookingAdminHandler) GetPassengers(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestPassengerSeat
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&request, "trn_request_uid = ?", c.Param("trn_request_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"passengers":    funcs.GetRequestPassengers(request.TrnRequestUID),
+		"seat_capacity": funcs.GetRequestSeatCapacity(request.TrnRequestUID),
+	})
+}
+
+// UpdatePassengers godoc
+// @Summary Update the passengers of a booking request
+// @Description This endpoint replaces the passenger list of a booking request until the trip starts. PEA employees are looked up by emp_id; guests need passenger_name and passenger_mobile_phone. The list must fit the seats of the vehicle.
+// @Tags Booking-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestPassengerUpdate true "VmsTrnRequestPassengerUpdate data"
+// @Router /api/booking-admin/update-passengers [put]
+func (h *BookingAdminHandler) UpdatePassengers(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestPassengerUpdate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var trnRequest models.VmsTrnRequestPassengerSeat
+	query := h.SetQueryRole(user, config.DB)
+	query = funcs.SetQueryPassengerCanUpdate(query)
+	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	beforePassengers := funcs.GetPassengerChangeValues(funcs.GetRequestPassengers(trnRequest.TrnRequestUID))
+	passengers, err := funcs.SaveRequestPassengers(trnRequest.TrnRequestUID, request.Passengers, user.EmpID)
+	if err == messages.ErrPassengerOverSeat || err == messages.ErrPassengerInvalid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.CreateTrnRequestChangeLog(trnRequest.TrnRequestUID, "passenger", beforePassengers, funcs.GetPassengerChangeValues(passengers), user.EmpID, "admin-department")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": passengers})
+}
+
+// GetAttachments godoc
+// @Summary Retrieve the attachments of a booking request
+// @Description This endpoint fetches the documents attached to a booking request. A document saved only in doc_no/doc_file is listed as an official letter with is_legacy_document set.
+// @Tags Booking-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/booking-admin/attachments/{trn_request_uid} [get]
+func (h *BookingAdminHandler) GetAttachments(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var trnRequest models.VmsTrnRequestDocument
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&trnRequest, "trn_request_uid = ? and is_deleted = '0'", c.Param("trn_request_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, funcs.GetRequestAttachments(trnRequest.TrnRequestUID))
+}
+
+// AddAttachment godoc
+// @Summary Add an attachment to a booking request
+// @Description This endpoint attaches a document uploaded through /api/upload to a booking request until the trip starts. ref_request_attachment_type_code is one of /api/ref/request-attachment-type. The first attachment also fills doc_no/doc_file when they are empty.
+// @Tags Booking-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestAttachmentRequest true "VmsTrnRequestAttachmentRequest data"
+// @Router /api/booking-admin/add-attachment [post]
+func (h *BookingAdminHandler) AddAttachment(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestAttachmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var trnRequest models.VmsTrnRequestDocument
+	query := h.SetQueryRole(user, config.DB)
+	query = funcs.SetQueryAttachmentCanUpdate(query)
+	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	beforeAttachments := funcs.GetAttachmentChangeValues(funcs.GetRequestAttachments(trnRequest.TrnRequestUID))
+	attachment, err := funcs.AddRequestAttachment(request, user.EmpID)
+	if err == messages.ErrAttachmentInvalid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.CreateTrnRequestChangeLog(trnRequest.TrnRequestUID, "attachment", beforeAttachments, funcs.GetAttachmentChangeValues(funcs.GetRequestAttachments(trnRequest.TrnRequestUID)), user.EmpID, "admin-department")
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Attachment created successfully", "result": attachment})
+}
+
+// DeleteAttachment godoc
+// @Summary Remove an attachment from a booking request
+// @Description This endpoint removes a document attached to a booking request until the trip starts. When doc_no/doc_file point to it they move to the next attachment.
+// @Tags Booking-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_attachment_uid path string true "TrnRequestAttachmentUID (trn_request_attachment_uid)"
+// @Router /api/booking-admin/delete-attachment/{trn_request_attachment_uid} [delete]
+func (h *BookingAdminHandler) DeleteAttachment(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var attachment models.VmsTrnRequestAttachment
+	if err := config.DB.First(&attachment, "trn_request_attachment_uid = ? and is_deleted = '0'", c.Param("trn_request_attachment_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found", "message": messages.ErrAttachmentNotFound.Error()})
+		return
+	}
+
+	var trnRequest models.VmsTrnRequestDocument
+	query := h.SetQueryRole(user, config.DB)
+	query = funcs.SetQueryAttachmentCanUpdate(query)
+	if err := query.First(&trnRequest, "trn_request_uid = ?", attachment.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	beforeAttachments := funcs.GetAttachmentChangeValues(funcs.GetRequestAttachments(trnRequest.TrnRequestUID))
+	if err := funcs.DeleteRequestAttachment(attachment, user.EmpID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.CreateTrnRequestChangeLog(trnRequest.TrnRequestUID, "attachment", beforeAttachments, funcs.GetAttachmentChangeValues(funcs.GetRequestAttachments(trnRequest.TrnRequestUID)), user.EmpID, "admin-department")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
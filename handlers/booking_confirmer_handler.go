@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -32,7 +33,7 @@ var StatusNameMapConfirmer = map[string]string{
 }
 
 func (h *BookingConfirmerHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
-	return query.Where("confirmed_request_emp_id = ? ", user.EmpID)
+	return query.Where("confirmed_request_emp_id in (?) or trn_request_uid in (?)", funcs.GetApprovalEmpIDs(user, "level1-approval"), funcs.SetQueryApprovalStepApprover(user, "20"))
 }
 
 func (h *BookingConfirmerHandler) SetQueryStatusCanUpdate(query *gorm.DB) *gorm.DB {
@@ -64,7 +65,7 @@ func (h *BookingConfirmerHandler) MenuRequests(c *gin.Context) {
 		if summary[i].RefRequestStatusCode == "00" {
 			//get count from vms_trn_request_annual_driver
 			var count int64
-			query := h.SetQueryRole(user, config.DB)
+			query := config.DB.Where("confirmed_request_emp_id in (?)", funcs.GetApprovalEmpIDs(user, "level1-approval"))
 			query = query.Table("vms_trn_request_annual_driver").Where("is_deleted = ?", "0")
 			if err := query.Count(&count).Error; err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
@@ -401,7 +402,7 @@ func (h *BookingConfirmerHandler) UpdateRejected(c *gin.Context) {
 	if c.IsAborted() {
 		return
 	}
-	var request, trnRequest models.VmsTrnRequestRejected
+	var request models.VmsTrnRequestRejected
 	var result struct {
 		models.VmsTrnRequestRejected
 		models.VmsTrnRequestRequestNo
@@ -411,11 +412,28 @@ func (h *BookingConfirmerHandler) UpdateRejected(c *gin.Context) {
 		return
 	}
 
+	if err := h.updateRejected(user, &request); err != nil {
+		if errors.Is(err, messages.ErrBookingCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *BookingConfirmerHandler) updateRejected(user *models.AuthenUserEmp, request *models.VmsTrnRequestRejected) error {
+	var trnRequest models.VmsTrnRequestRejected
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
-		return
+		return messages.ErrBookingCannotUpdate
 	}
 	request.RefRequestStatusCode = "21"
 	request.UpdatedAt = time.Now()
@@ -432,13 +450,8 @@ func (h *BookingConfirmerHandler) UpdateRejected(c *gin.Context) {
 	request.RejectedRequestPosition = rejectUser.Position
 	request.RejectedRequestDatetime = models.TimeWithZone{Time: time.Now()}
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update : %v", err)
 	}
 	funcs.CreateTrnRequestActionLog(request.TrnRequestUID,
 		request.RefRequestStatusCode,
@@ -447,8 +460,7 @@ func (h *BookingConfirmerHandler) UpdateRejected(c *gin.Context) {
 		"level1-approval",
 		request.RejectedRequestReason,
 	)
-
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	return nil
 }
 
 // UpdateApproved godoc
@@ -466,7 +478,7 @@ func (h *BookingConfirmerHandler) UpdateApproved(c *gin.Context) {
 	if c.IsAborted() {
 		return
 	}
-	var request, trnRequest models.VmsTrnRequestConfirmed
+	var request models.VmsTrnRequestConfirmed
 	var result struct {
 		models.VmsTrnRequestConfirmed
 		models.VmsTrnRequestRequestNo
@@ -475,35 +487,111 @@ func (h *BookingConfirmerHandler) UpdateApproved(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
 		return
 	}
+
+	if err := h.updateApproved(user, &request, ""); err != nil {
+		if errors.Is(err, messages.ErrBookingCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *BookingConfirmerHandler) updateApproved(user *models.AuthenUserEmp, request *models.VmsTrnRequestConfirmed, remark string) error {
+	var trnRequest models.VmsTrnRequestConfirmed
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
-		return
+		return messages.ErrBookingCannotUpdate
+	}
+	if completed, err := funcs.ApproveRequestApprovalStep(user, request.TrnRequestUID, "20"); err != nil || !completed {
+		return err
 	}
 
 	request.RefRequestStatusCode = "30" // ยืนยันคำขอแล้ว รอตรวจสอบคำขอ
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-
-	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update : %v", err)
 	}
 	funcs.CreateTrnRequestActionLog(request.TrnRequestUID,
 		request.RefRequestStatusCode,
 		"รอผู้ดูแลยานพาหนะตรวจสอบ",
 		user.EmpID,
 		"level1-approval",
-		"",
+		remark,
 	)
 	funcs.CheckMustPassStatus(request.TrnRequestUID)
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	return nil
+}
+
+// UpdateBulkRejected godoc
+// @Summary Send back several booking requests at once
+// @Description This endpoint sends back each listed request independently and reports the result of every item. The remark is used as the reason for all of them.
+// @Tags Booking-confirmer
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestBulk true "VmsTrnRequestBulk data"
+// @Router /api/booking-confirmer/update-bulk-rejected [put]
+func (h *BookingConfirmerHandler) UpdateBulkRejected(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestUIDs, func(trnRequestUID string) error {
+		return h.updateRejected(user, &models.VmsTrnRequestRejected{TrnRequestUID: trnRequestUID, RejectedRequestReason: request.Remark})
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
+}
+
+// UpdateBulkApproved godoc
+// @Summary Approve several booking requests at once
+// @Description This endpoint approves each listed request independently and reports the result of every item. The remark is added to the action log of each request.
+// @Tags Booking-confirmer
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestBulk true "VmsTrnRequestBulk data"
+// @Router /api/booking-confirmer/update-bulk-approved [put]
+func (h *BookingConfirmerHandler) UpdateBulkApproved(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestUIDs, func(trnRequestUID string) error {
+		return h.updateApproved(user, &models.VmsTrnRequestConfirmed{TrnRequestUID: trnRequestUID}, request.Remark)
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
 }
 
 // UpdateCanceled godoc
@@ -567,6 +655,7 @@ func (h *BookingConfirmerHandler) UpdateCanceled(c *gin.Context) {
 		request.CanceledRequestReason,
 	)
 
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
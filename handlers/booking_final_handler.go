@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -360,7 +361,7 @@ func (h *BookingFinalHandler) UpdateRejected(c *gin.Context) {
 	if c.IsAborted() {
 		return
 	}
-	var request, trnRequest models.VmsTrnRequestRejected
+	var request models.VmsTrnRequestRejected
 	var result struct {
 		models.VmsTrnRequestRejected
 		models.VmsTrnRequestRequestNo
@@ -370,11 +371,28 @@ func (h *BookingFinalHandler) UpdateRejected(c *gin.Context) {
 		return
 	}
 
+	if err := h.updateRejected(user, &request); err != nil {
+		if errors.Is(err, messages.ErrBookingCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *BookingFinalHandler) updateRejected(user *models.AuthenUserEmp, request *models.VmsTrnRequestRejected) error {
+	var trnRequest models.VmsTrnRequestRejected
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
-		return
+		return messages.ErrBookingCannotUpdate
 	}
 
 	request.RefRequestStatusCode = "41"
@@ -391,13 +409,8 @@ func (h *BookingFinalHandler) UpdateRejected(c *gin.Context) {
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update : %v", err)
 	}
 
 	funcs.CreateTrnRequestActionLog(request.TrnRequestUID,
@@ -407,8 +420,7 @@ func (h *BookingFinalHandler) UpdateRejected(c *gin.Context) {
 		"approval-department",
 		request.RejectedRequestReason,
 	)
-
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	return nil
 }
 
 // UpdateApproved godoc
@@ -426,7 +438,7 @@ func (h *BookingFinalHandler) UpdateApproved(c *gin.Context) {
 	if c.IsAborted() {
 		return
 	}
-	var request, trnRequest models.VmsTrnRequestApproved
+	var request models.VmsTrnRequestApproved
 	var result struct {
 		models.VmsTrnRequestApproved
 		models.VmsTrnRequestRequestNo
@@ -436,11 +448,36 @@ func (h *BookingFinalHandler) UpdateApproved(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
 		return
 	}
+
+	if err := h.updateApproved(user, &request, ""); err != nil {
+		if errors.Is(err, messages.ErrBookingCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+			return
+		}
+		if errors.Is(err, messages.ErrBookingNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *BookingFinalHandler) updateApproved(user *models.AuthenUserEmp, request *models.VmsTrnRequestApproved, remark string) error {
+	var trnRequest models.VmsTrnRequestApproved
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
-		return
+		return messages.ErrBookingCannotUpdate
+	}
+	if completed, err := funcs.ApproveRequestApprovalStep(user, request.TrnRequestUID, "40"); err != nil || !completed {
+		return err
 	}
 	request.RefRequestStatusCode = "50"
 	empUser := funcs.GetUserEmpInfo(user.EmpID)
@@ -456,22 +493,15 @@ func (h *BookingFinalHandler) UpdateApproved(c *gin.Context) {
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-
-	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update : %v", err)
 	}
 
 	// Check if a record exists in vms_trn_vehicle_key_handover
 
 	var approvedWithReceiveKey models.VmsTrnRequestApprovedWithRecieiveKey
 	if err := config.DB.First(&approvedWithReceiveKey, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
-		return
+		return messages.ErrBookingNotFound
 	}
 	var keyHandover models.VmsTrnReceivedKeyPEA
 	if err := config.DB.First(&keyHandover, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
@@ -491,8 +521,7 @@ func (h *BookingFinalHandler) UpdateApproved(c *gin.Context) {
 			}
 
 			if err := config.DB.Create(&newKeyHandover).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create key handover record: %v", err), "message": messages.ErrInternalServer.Error()})
-				return
+				return fmt.Errorf("failed to create key handover record: %v", err)
 			}
 			//update vms_trn_request set appointment_key_handover_place,appointment_key_handover_start_datetime,appointment_key_handover_end_datetime
 			if err := config.DB.Table("vms_trn_request").
@@ -500,14 +529,12 @@ func (h *BookingFinalHandler) UpdateApproved(c *gin.Context) {
 				Update("appointment_key_handover_place", approvedWithReceiveKey.ReceivedKeyPlace).
 				Update("appointment_key_handover_start_datetime", approvedWithReceiveKey.ReceivedKeyStartDatetime).
 				Update("appointment_key_handover_end_datetime", approvedWithReceiveKey.ReceivedKeyEndDatetime).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
-				return
+				return fmt.Errorf("failed to update : %v", err)
 			}
 
 		} else {
 			// Handle other errors
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to query key handover record: %v", err), "message": messages.ErrInternalServer.Error()})
-			return
+			return fmt.Errorf("failed to query key handover record: %v", err)
 		}
 	}
 
@@ -515,18 +542,78 @@ func (h *BookingFinalHandler) UpdateApproved(c *gin.Context) {
 
 	var receivedKey models.VmsTrnRequestApprovedWithRecieiveKey
 	if err := config.DB.First(&receivedKey, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
-		return
+		return messages.ErrBookingNotFound
 	}
 	funcs.CreateTrnRequestActionLog(request.TrnRequestUID,
 		request.RefRequestStatusCode,
 		funcs.GetDateTime2BuddhistYear(receivedKey.ReceivedKeyStartDatetime.TimeWithZoneToTime(), receivedKey.ReceivedKeyEndDatetime.TimeWithZoneToTime())+" สถานที่ "+receivedKey.ReceivedKeyPlace+" นัดหมายรับกุญแจ",
 		user.EmpID,
 		"approval-department",
-		"",
+		remark,
 	)
+	return nil
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+// UpdateBulkRejected godoc
+// @Summary Send back several booking requests at once
+// @Description This endpoint sends back each listed request independently and reports the result of every item. The remark is used as the reason for all of them.
+// @Tags Booking-final
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestBulk true "VmsTrnRequestBulk data"
+// @Router /api/booking-final/update-bulk-rejected [put]
+func (h *BookingFinalHandler) UpdateBulkRejected(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestUIDs, func(trnRequestUID string) error {
+		return h.updateRejected(user, &models.VmsTrnRequestRejected{TrnRequestUID: trnRequestUID, RejectedRequestReason: request.Remark})
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
+}
+
+// UpdateBulkApproved godoc
+// @Summary Approve several booking requests at once
+// @Description This endpoint approves each listed request independently and reports the result of every item. The remark is added to the action log of each request.
+// @Tags Booking-final
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestBulk true "VmsTrnRequestBulk data"
+// @Router /api/booking-final/update-bulk-approved [put]
+func (h *BookingFinalHandler) UpdateBulkApproved(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestUIDs, func(trnRequestUID string) error {
+		return h.updateApproved(user, &models.VmsTrnRequestApproved{TrnRequestUID: trnRequestUID}, request.Remark)
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
 }
 
 // UpdateCanceled godoc
@@ -591,6 +678,7 @@ func (h *BookingFinalHandler) UpdateCanceled(c *gin.Context) {
 		request.CanceledRequestReason,
 	)
 
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
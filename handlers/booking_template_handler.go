@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BookingTemplateHandler struct {
+	Role string
+}
+
+func (h *BookingTemplateHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
+	return query.Where("emp_id = ? and is_deleted = '0'", user.EmpID)
+}
+
+// CreateTemplate godoc
+// @Summary Create a personal booking template
+// @Description This endpoint saves the step 1-4 fields of a booking under a name so they can be reused for new requests.
+// @Tags Booking-template
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestTemplate true "VmsTrnRequestTemplate data"
+// @Router /api/booking-template/create-template [post]
+func (h *BookingTemplateHandler) CreateTemplate(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnRequestTemplate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if request.TemplateName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "template_name is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	request.TrnRequestTemplateUID = uuid.New().String()
+	request.EmpID = user.EmpID
+	request.CreatedAt = time.Now()
+	request.CreatedBy = user.EmpID
+	request.UpdatedAt = time.Now()
+	request.UpdatedBy = user.EmpID
+	request.IsDeleted = "0"
+	h.setTemplateNil(&request)
+
+	if err := config.DB.Create(&request).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Template created successfully", "result": request})
+}
+
+// SearchTemplates godoc
+// @Summary List personal booking templates
+// @Description This endpoint returns the booking templates of the current user.
+// @Tags Booking-template
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param search query string false "Search keyword (matches template_name or work_place)"
+// @Router /api/booking-template/search-templates [get]
+func (h *BookingTemplateHandler) SearchTemplates(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var templates []models.VmsTrnRequestTemplate
+	query := h.SetQueryRole(user, config.DB)
+	if search := c.Query("search"); search != "" {
+		query = query.Where("template_name ILIKE ? OR work_place ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if err := query.Order("template_name").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate godoc
+// @Summary Retrieve a personal booking template
+// @Description This endpoint fetches a booking template of the current user.
+// @Tags Booking-template
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_template_uid path string true "TrnRequestTemplateUID (trn_request_template_uid)"
+// @Router /api/booking-template/template/{trn_request_template_uid} [get]
+func (h *BookingTemplateHandler) GetTemplate(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var template models.VmsTrnRequestTemplate
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&template, "trn_request_template_uid = ?", c.Param("trn_request_template_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found", "message": messages.ErrTemplateNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate godoc
+// @Summary Update a personal booking template
+// @Description This endpoint replaces the fields of a booking template of the current user.
+// @Tags Booking-template
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestTemplate true "VmsTrnRequestTemplate data"
+// @Router /api/booking-template/update-template [put]
+func (h *BookingTemplateHandler) UpdateTemplate(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request, template models.VmsTrnRequestTemplate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if request.TemplateName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "template_name is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&template, "trn_request_template_uid = ?", request.TrnRequestTemplateUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found", "message": messages.ErrTemplateNotFound.Error()})
+		return
+	}
+
+	request.EmpID = template.EmpID
+	request.CreatedAt = template.CreatedAt
+	request.CreatedBy = template.CreatedBy
+	request.UpdatedAt = time.Now()
+	request.UpdatedBy = user.EmpID
+	request.IsDeleted = "0"
+	h.setTemplateNil(&request)
+
+	if err := config.DB.Save(&request).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": request})
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a personal booking template
+// @Description This endpoint deletes a booking template of the current user.
+// @Tags Booking-template
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_template_uid path string true "TrnRequestTemplateUID (trn_request_template_uid)"
+// @Router /api/booking-template/delete-template/{trn_request_template_uid} [delete]
+func (h *BookingTemplateHandler) DeleteTemplate(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var template models.VmsTrnRequestTemplate
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&template, "trn_request_template_uid = ?", c.Param("trn_request_template_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found", "message": messages.ErrTemplateNotFound.Error()})
+		return
+	}
+
+	if err := config.DB.Model(&template).UpdateColumns(map[string]interface{}{
+		"is_deleted": "1",
+		"updated_by": user.EmpID,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+func (h *BookingTemplateHandler) setTemplateNil(template *models.VmsTrnRequestTemplate) {
+	if template.MasCarpoolUID != nil && *template.MasCarpoolUID == "" {
+		template.MasCarpoolUID = nil
+	}
+	if template.MasVehicleUID != nil && *template.MasVehicleUID == "" {
+		template.MasVehicleUID = nil
+	}
+	if template.MasCarPoolDriverUID != nil && *template.MasCarPoolDriverUID == "" {
+		template.MasCarPoolDriverUID = nil
+	}
+}
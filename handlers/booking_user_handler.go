@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
@@ -23,12 +24,14 @@ type BookingUserHandler struct {
 }
 
 var MenuNameMapUser = map[string]string{
+	"10":                               "ร่างคำขอ",
 	"20,21,30,31,40,41,50,51,60,70,71": "กำลังดำเนินการ",
 	"80":                               "เสร็จสิ้น",
 	"90":                               "ยกเลิกคำขอ",
 }
 
 var StatusNameMapUser = map[string]string{
+	"10": "ร่างคำขอ",
 	"20": "รออนุมัติ",
 	"21": "ถูกตีกลับ",
 	"30": "รอตรวจสอบ",
@@ -51,13 +54,16 @@ func (h *BookingUserHandler) SetQueryRole(user *models.AuthenUserEmp, query *gor
 func (h *BookingUserHandler) SetQueryStatusCanUpdate(query *gorm.DB) *gorm.DB {
 	return query.Where("ref_request_status_code in ('21','31','41') and is_deleted = '0'")
 }
+func (h *BookingUserHandler) SetQueryStatusDraft(query *gorm.DB) *gorm.DB {
+	return query.Where("ref_request_status_code = '10' and is_deleted = '0'")
+}
 func (h *BookingUserHandler) SetQueryStatusCanCancel(query *gorm.DB) *gorm.DB {
 	return query.Where("ref_request_status_code in ('20','21','31','30','40','41') and is_deleted = '0'")
 }
 
 // CreateRequest godoc
 // @Summary Create a new booking request
-// @Description This endpoint allows a booking user to create a new request.
+// @Description This endpoint allows a booking user to create a new request. Pass trn_waitlist_uid to accept a vehicle held from the waitlist.
 // @Tags Booking-user
 // @Accept json
 // @Produce json
@@ -76,6 +82,25 @@ func (h *BookingUserHandler) CreateRequest(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON input", "message": messages.ErrInvalidJSONInput.Error()})
 		return
 	}
+
+	var waitlist models.VmsTrnWaitlist
+	if request.TrnWaitlistUID != "" {
+		if err := config.DB.First(&waitlist, "trn_waitlist_uid = ? AND emp_id = ? AND waitlist_status_code = ? AND hold_expire_datetime > ? AND is_deleted = ?",
+			request.TrnWaitlistUID, user.EmpID, "1", time.Now(), "0").Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Waitlist hold not found or expired", "message": messages.ErrWaitlistHoldExpired.Error()})
+			return
+		}
+		//use the held vehicle and window
+		request.MasVehicleUID = waitlist.OfferedMasVehicleUID
+		request.ReserveStartDatetime = waitlist.StartDatetime
+		request.ReserveEndDatetime = waitlist.EndDatetime
+	}
+	if request.MasVehicleUID != nil && *request.MasVehicleUID != "" &&
+		funcs.IsVehicleOnHold(*request.MasVehicleUID, request.ReserveStartDatetime.Time, request.ReserveEndDatetime.Time, user.EmpID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Vehicle is on hold for a waitlisted user", "message": messages.ErrVehicleOnHold.Error()})
+		return
+	}
+
 	request.TrnRequestUID = uuid.New().String()
 	request.CreatedAt = time.Now()
 	request.CreatedBy = user.EmpID
@@ -83,174 +108,428 @@ func (h *BookingUserHandler) CreateRequest(c *gin.Context) {
 	request.UpdatedBy = user.EmpID
 	request.IsDeleted = "0"
 
-	createUser := funcs.GetUserEmpInfo(user.EmpID)
-	request.CreatedRequestEmpID = createUser.EmpID
-	request.CreatedRequestEmpName = createUser.FullName
-	request.CreatedRequestDeptSAP = createUser.DeptSAP
-	request.CreatedRequestDeptNameShort = createUser.DeptSAPShort
-	request.CreatedRequestDeptNameFull = createUser.DeptSAPFull
-	request.CreatedRequestDeskPhone = createUser.TelInternal
-	request.CreatedRequestMobilePhone = createUser.TelMobile
-	request.CreatedRequestPosition = createUser.Position
-	request.CreatedRequestDatetime = models.TimeWithZone{Time: time.Now()}
+	vehicleUser := h.setRequestEmpInfo(&request, user.EmpID)
+	h.setRequestVehicleDriver(&request)
 
-	vehicleUser := funcs.GetUserEmpInfo(request.VehicleUserEmpID)
-	request.VehicleUserEmpID = vehicleUser.EmpID
-	request.VehicleUserEmpName = vehicleUser.FullName
-	request.VehicleUserDeptSAP = vehicleUser.DeptSAP
-	request.VehicleUserDeptNameShort = vehicleUser.DeptSAPShort
-	request.VehicleUserDeptNameFull = vehicleUser.DeptSAPFull
-	//request.VehicleUserDeskPhone = vehicleUser.TelInternal
-	//request.VehicleUserMobilePhone = vehicleUser.TelMobile
-	request.VehicleUserPosition = vehicleUser.Position
+	requestNo, err := funcs.GenerateRequestNo(vehicleUser.BusinessArea)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate running number", "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	request.RequestNo = requestNo
+	request.RefRequestStatusCode = "20" // รออนุมัติจากต้นสังกัด
 
-	confirmUser := funcs.GetUserEmpInfo(request.ConfirmedRequestEmpID)
-	request.ConfirmedRequestEmpID = confirmUser.EmpID
-	request.ConfirmedRequestEmpName = confirmUser.FullName
-	request.ConfirmedRequestDeptSAP = confirmUser.DeptSAP
-	request.ConfirmedRequestDeptNameShort = confirmUser.DeptSAPShort
-	request.ConfirmedRequestDeptNameFull = confirmUser.DeptSAPFull
-	request.ConfirmedRequestDeskPhone = confirmUser.TelInternal
-	request.ConfirmedRequestMobilePhone = confirmUser.TelMobile
-	request.ConfirmedRequestPosition = confirmUser.Position
+	if err := config.DB.Create(&request).
+		Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request", "message": messages.ErrCreateRequest.Error()})
+		return
+	}
 
-	request.IsAdminChooseDriver = "0"
-	request.RefRequestTypeCode = 1
-	request.IsHaveSubRequest = "0"
-	request.MasVehicleEvUID = ""
+	var result struct {
+		models.VmsTrnRequestRequest
+		RequestNo string `gorm:"column:request_no" json:"request_no"`
+	}
+	if err := config.DB.First(&result, "trn_request_uid = ? and is_deleted = ?", request.TrnRequestUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+	funcs.CreateTrnRequestActionLog(result.TrnRequestUID,
+		result.RefRequestStatusCode,
+		"รออนุมัติ จากต้นสังกัด",
+		user.EmpID,
+		"vehicle-user",
+		"",
+	)
 
-	if request.MasVehicleUID != nil && *request.MasVehicleUID != "" {
-		var vehicle models.VmsMasVehicleDepartment
-		if err := config.DB.First(&vehicle, "mas_vehicle_uid = ? AND is_deleted = '0'", request.MasVehicleUID).Error; err == nil {
-			request.MasVehicleDepartmentUID = &vehicle.MasVehicleDepartmentUID
-		}
-		var carpool models.VmsMasCarpoolVehicle
-		if err := config.DB.First(&carpool, "mas_vehicle_uid = ? AND is_deleted = '0'", request.MasVehicleUID).Error; err == nil {
-			request.MasCarpoolUID = &carpool.MasCarpoolUID
+	if waitlist.TrnWaitlistUID != "" {
+		waitlist.WaitlistStatusCode = "2"
+		waitlist.AcceptedTrnRequestUID = &request.TrnRequestUID
+		waitlist.UpdatedAt = time.Now()
+		waitlist.UpdatedBy = user.EmpID
+		if err := config.DB.Save(&waitlist).Error; err != nil {
+			fmt.Println("Error accepting waitlist:", err)
 		}
 	}
 
-	if request.MasCarpoolUID == nil || *request.MasCarpoolUID == "" {
-		request.MasCarpoolUID = nil
-	} else {
-		var carpool models.VmsMasCarpoolRequest
-		if err := config.DB.First(&carpool, "mas_carpool_uid = ? AND is_deleted = '0'", request.MasCarpoolUID).Error; err == nil {
-			request.MasCarpoolUID = &carpool.MasCarpoolUID
-			vehicleUser, _ := userhub.GetUserInfo(request.VehicleUserEmpID)
+	funcs.CheckMustPassStatus(request.TrnRequestUID)
 
-			if carpool.RefCarpoolChooseCarID == 3 {
-				query := config.DB.Raw(`SELECT mas_vehicle_uid FROM fn_get_available_vehicles_view (?, ?, ?, ?) where mas_carpool_uid = ? and "CarTypeDetail" = ?`,
-					request.ReserveStartDatetime, request.ReserveEndDatetime, vehicleUser.BureauDeptSap, vehicleUser.BusinessArea, request.MasCarpoolUID, request.RequestedVehicleType)
+	c.JSON(http.StatusCreated, gin.H{"message": "Request created successfully",
+		"data":            result,
+		"request_no":      request.RequestNo,
+		"trn_request_uid": request.TrnRequestUID,
+	})
+}
 
-				var vehicle models.VmsMasVehicle
-				if err := query.First(&vehicle).
-					Select("mas_vehicle_uid").
-					Order("vehicle_mileage").
-					Limit(1).Error; err == nil && vehicle.MasVehicleUID != "" {
-					request.MasVehicleUID = &vehicle.MasVehicleUID
-				}
-			}
-			if carpool.RefCarpoolChooseDriverID == 3 {
-				query := config.DB.Raw(`SELECT mas_driver_uid FROM fn_get_available_drivers_view (?, ?, ?, ?,?) where mas_carpool_uid = ?`,
-					request.ReserveStartDatetime,
-					request.ReserveEndDatetime,
-					vehicleUser.BureauDeptSap,
-					vehicleUser.BusinessArea,
-					request.RefTripTypeCode,
-					request.MasCarpoolUID)
+// CreateDraft godoc
+// @Summary Save a new booking request as draft
+// @Description This endpoint saves the booking form after any step as a draft. The draft has no request number and is not sent for approval until it is submitted.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestRequest true "VmsTrnRequestRequest data"
+// @Router /api/booking-user/create-draft [post]
+func (h *BookingUserHandler) CreateDraft(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
 
-				var driver models.VmsMasDriver
-				if err := query.Scan(&driver).
-					Select("mas_driver_uid, w_thismth.job_count, w_thismth.total_days").
-					Joins("LEFT JOIN public.vms_trn_driver_monthly_workload AS w_thismth ON w_thismth.workload_year = ? AND w_thismth.workload_month = ? AND w_thismth.driver_emp_id = d.driver_id AND w_thismth.is_deleted = ?", request.ReserveStartDatetime.Year(), request.ReserveStartDatetime.Month(), "0").
-					Order("total_days, job_count").
-					Limit(1).Error; err == nil && driver.MasDriverUID != "" {
-					request.MasCarPoolDriverUID = &driver.MasDriverUID
-				}
-			}
-		}
+	var request models.VmsTrnRequestRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON input", "message": messages.ErrInvalidJSONInput.Error()})
+		return
 	}
 
-	if request.MasVehicleUID != nil && *request.MasVehicleUID != "" {
-		var vehicle models.VmsMasVehicleDepartment
-		if err := config.DB.First(&vehicle, "mas_vehicle_uid = ? AND is_deleted = '0'", request.MasVehicleUID).Error; err == nil {
-			request.MasVehicleDepartmentUID = &vehicle.MasVehicleDepartmentUID
-		}
+	request.TrnRequestUID = uuid.New().String()
+	request.CreatedAt = time.Now()
+	request.CreatedBy = user.EmpID
+	request.UpdatedAt = time.Now()
+	request.UpdatedBy = user.EmpID
+	request.IsDeleted = "0"
+	h.setRequestEmpInfo(&request, user.EmpID)
+	h.setRequestDraftNil(&request)
+	request.RefRequestStatusCode = "10" // ร่างคำขอ
+
+	//request_no is generated on submit
+	if err := config.DB.Omit("request_no").Create(&request).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request", "message": messages.ErrCreateRequest.Error()})
+		return
 	}
 
-	if request.IsPEAEmployeeDriver == "1" && request.DriverEmpID != "" {
-		driverUser := funcs.GetUserEmpInfo(request.DriverEmpID)
-		request.DriverEmpID = driverUser.EmpID
-		request.DriverEmpName = driverUser.FullName
-		request.DriverEmpDeptSAP = driverUser.DeptSAP
-		request.DriverEmpPosition = driverUser.Position
-		request.DriverEmpDeptNameShort = funcs.GetDeptSAPShort(driverUser.DeptSAP)
-		request.DriverEmpDeptNameFull = funcs.GetDeptSAPFull(driverUser.DeptSAP)
-		//request.DriverEmpDeskPhone = driverUser.TelInternal
-		//request.DriverEmpMobilePhone = driverUser.TelMobile
+	c.JSON(http.StatusCreated, gin.H{"message": "Draft created successfully",
+		"trn_request_uid": request.TrnRequestUID,
+		"validation":      funcs.ValidateRequestDraft(request),
+	})
+}
 
-	} else if request.MasCarPoolDriverUID != nil && *request.MasCarPoolDriverUID != "" {
-		var driver models.VmsMasDriver
-		if err := config.DB.First(&driver, "mas_driver_uid = ? AND is_deleted = '0'", request.MasCarPoolDriverUID).Error; err == nil {
-			request.DriverEmpID = driver.DriverID
-			request.DriverEmpName = driver.DriverName
-			request.DriverEmpDeptSAP = driver.DriverDeptSAP
-			request.DriverEmpDeptNameShort = funcs.GetDeptSAPShort(driver.DriverDeptSAP)
-			request.DriverEmpDeptNameFull = funcs.GetDeptSAPFull(driver.DriverDeptSAP)
-		}
+// UpdateDraft godoc
+// @Summary Save a step of a draft booking request
+// @Description This endpoint updates a draft with the fields sent in the body. Fields not sent keep their saved values.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestRequest true "VmsTrnRequestRequest data with trn_request_uid"
+// @Router /api/booking-user/update-draft [put]
+func (h *BookingUserHandler) UpdateDraft(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
 	}
 
-	if request.MasVehicleDepartmentUID == nil || *request.MasVehicleDepartmentUID == "" {
-		request.MasVehicleDepartmentUID = nil
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
 	}
-	if request.MasVehicleUID == nil || *request.MasVehicleUID == "" {
-		request.MasVehicleUID = nil
+	var draft models.VmsTrnRequestDraft
+	if err := json.Unmarshal(body, &draft); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
 	}
-	if request.MasCarPoolDriverUID == nil || *request.MasCarPoolDriverUID == "" {
-		request.MasCarPoolDriverUID = nil
+
+	var request models.VmsTrnRequestRequest
+	query := h.SetQueryRole(user, config.DB)
+	query = h.SetQueryStatusDraft(query)
+	if err := query.First(&request, "trn_request_uid = ?", draft.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+	trnRequest := request
+	//fields in the body overwrite the saved draft
+	if err := json.Unmarshal(body, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	request.UpdatedAt = time.Now()
+	request.UpdatedBy = user.EmpID
+	h.setRequestEmpInfo(&request, user.EmpID)
+	h.setRequestDraftNil(&request)
+
+	if err := config.DB.Model(&models.VmsTrnRequestRequest{}).
+		Where("trn_request_uid = ?", request.TrnRequestUID).
+		Select("*").
+		Omit("trn_request_uid", "request_no", "ref_request_status_code", "created_at", "created_by", "is_deleted").
+		Updates(&request).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "draft", &trnRequest, &request, user.EmpID, "vehicle-user")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully",
+		"trn_request_uid": request.TrnRequestUID,
+		"validation":      funcs.ValidateRequestDraft(request),
+	})
+}
+
+// ValidateDraft godoc
+// @Summary Check the steps of a draft booking request
+// @Description This endpoint returns the missing fields of each step of a draft.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/booking-user/validate-draft/{trn_request_uid} [get]
+func (h *BookingUserHandler) ValidateDraft(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnRequestRequest
+	query := h.SetQueryRole(user, config.DB)
+	query = h.SetQueryStatusDraft(query)
+	if err := query.First(&request, "trn_request_uid = ?", c.Param("trn_request_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, funcs.ValidateRequestDraft(request))
+}
+
+// UpdateSubmitDraft godoc
+// @Summary Submit a draft booking request
+// @Description This endpoint submits a complete draft. The request number is generated and the request is sent for approval as a new request.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestDraft true "VmsTrnRequestDraft data"
+// @Router /api/booking-user/update-submit-draft [put]
+func (h *BookingUserHandler) UpdateSubmitDraft(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var draft models.VmsTrnRequestDraft
+	if err := c.ShouldBindJSON(&draft); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var request models.VmsTrnRequestRequest
+	query := h.SetQueryRole(user, config.DB)
+	query = h.SetQueryStatusDraft(query)
+	if err := query.First(&request, "trn_request_uid = ?", draft.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	validation := funcs.ValidateRequestDraft(request)
+	if !validation.IsComplete {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Draft is not complete", "message": messages.ErrDraftNotComplete.Error(), "validation": validation})
+		return
+	}
+	if request.MasVehicleUID != nil && *request.MasVehicleUID != "" &&
+		funcs.IsVehicleOnHold(*request.MasVehicleUID, request.ReserveStartDatetime.Time, request.ReserveEndDatetime.Time, user.EmpID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Vehicle is on hold for a waitlisted user", "message": messages.ErrVehicleOnHold.Error()})
+		return
+	}
+
+	request.UpdatedAt = time.Now()
+	request.UpdatedBy = user.EmpID
+	vehicleUser := h.setRequestEmpInfo(&request, user.EmpID)
+	h.setRequestVehicleDriver(&request)
+
+	requestNo, err := funcs.GenerateRequestNo(vehicleUser.BusinessArea)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate running number", "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	request.RequestNo = requestNo
+	request.RefRequestStatusCode = "20" // รออนุมัติจากต้นสังกัด
+
+	updateResult := config.DB.Model(&models.VmsTrnRequestRequest{}).
+		Where("trn_request_uid = ? AND ref_request_status_code = ? AND is_deleted = ?", request.TrnRequestUID, "10", "0").
+		Select("*").
+		Omit("trn_request_uid", "created_at", "created_by", "is_deleted").
+		Updates(&request)
+	if updateResult.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", updateResult.Error), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	// the draft was submitted or deleted by another call since it was read
+	if updateResult.RowsAffected == 0 {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	var result struct {
+		models.VmsTrnRequestRequest
+		RequestNo string `gorm:"column:request_no" json:"request_no"`
+	}
+	if err := config.DB.First(&result, "trn_request_uid = ? and is_deleted = ?", request.TrnRequestUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+	funcs.CreateTrnRequestActionLog(result.TrnRequestUID,
+		result.RefRequestStatusCode,
+		"รออนุมัติ จากต้นสังกัด",
+		user.EmpID,
+		"vehicle-user",
+		"",
+	)
+	funcs.CheckMustPassStatus(request.TrnRequestUID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully",
+		"data":            result,
+		"request_no":      request.RequestNo,
+		"trn_request_uid": request.TrnRequestUID,
+	})
+}
+
+// DeleteDraft godoc
+// @Summary Delete a draft booking request
+// @Description This endpoint discards a draft of the current user.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/booking-user/delete-draft/{trn_request_uid} [delete]
+func (h *BookingUserHandler) DeleteDraft(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var draft models.VmsTrnRequestDraft
+	query := h.SetQueryRole(user, config.DB)
+	query = h.SetQueryStatusDraft(query)
+	if err := query.First(&draft, "trn_request_uid = ?", c.Param("trn_request_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	if err := funcs.DeleteRequestDrafts([]string{draft.TrnRequestUID}, user.EmpID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// CreateDuplicateRequest godoc
+// @Summary Copy a previous booking request into a new draft
+// @Description This endpoint copies the trip, cost, vehicle, driver and confirmer of a request into a new draft with new dates. The vehicle, driver and people are checked again and cleared when they can no longer be used.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestDuplicate true "VmsTrnRequestDuplicate data"
+// @Router /api/booking-user/create-duplicate-request [post]
+func (h *BookingUserHandler) CreateDuplicateRequest(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var duplicate models.VmsTrnRequestDuplicate
+	if err := c.ShouldBindJSON(&duplicate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if duplicate.StartDatetime.IsZero() || !duplicate.EndDatetime.After(duplicate.StartDatetime.Time) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "End Date must be after Start Date", "message": messages.ErrInvalidDate.Error()})
+		return
+	}
+
+	var request models.VmsTrnRequestRequest
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&request, "trn_request_uid = ? AND is_deleted = ?", duplicate.TrnRequestUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	//keep the pickup time relative to the start of the trip
+	if !request.PickupDateTime.IsZero() {
+		request.PickupDateTime = models.TimeWithZone{Time: duplicate.StartDatetime.Add(request.PickupDateTime.Sub(request.ReserveStartDatetime.Time))}
+	}
+	request.ReserveStartDatetime = duplicate.StartDatetime
+	request.ReserveEndDatetime = duplicate.EndDatetime
+	request.DocNo = ""
+	request.DocFile = ""
+	request.DocFileName = ""
+
+	h.createDraftCopy(c, user, &request)
+}
+
+// CreateDraftFromTemplate godoc
+// @Summary Create a draft booking request from a template
+// @Description This endpoint creates a new draft from a personal booking template with the given dates. The vehicle, driver and people are checked again and cleared when they can no longer be used.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestTemplateDraft true "VmsTrnRequestTemplateDraft data"
+// @Router /api/booking-user/create-draft-from-template [post]
+func (h *BookingUserHandler) CreateDraftFromTemplate(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
 	}
 
-	YY := time.Now().Year() + 543
-	BCode := vehicleUser.BusinessArea[0:1]
-	var running int
-	err := config.DB.Raw("SELECT nextval('vehicle_request_seq_' || lower(?))", BCode).Scan(&running).Error
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate running number", "message": messages.ErrInternalServer.Error()})
+	var templateDraft models.VmsTrnRequestTemplateDraft
+	if err := c.ShouldBindJSON(&templateDraft); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
 		return
 	}
-	//'V' + BCode + 'YY' + 'RA' + Running 6 หลัก เช่น VZ68RA000001
-	request.RequestNo = "V" + BCode + fmt.Sprintf("%02d", YY%100) + "RA" + fmt.Sprintf("%06d", running)
-	request.RefRequestStatusCode = "20" // รออนุมัติจากต้นสังกัด
-
-	if err := config.DB.Create(&request).
-		Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request", "message": messages.ErrCreateRequest.Error()})
+	if templateDraft.StartDatetime.IsZero() || !templateDraft.EndDatetime.After(templateDraft.StartDatetime.Time) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "End Date must be after Start Date", "message": messages.ErrInvalidDate.Error()})
 		return
 	}
 
-	var result struct {
-		models.VmsTrnRequestRequest
-		RequestNo string `gorm:"column:request_no" json:"request_no"`
-	}
-	if err := config.DB.First(&result, "trn_request_uid = ? and is_deleted = ?", request.TrnRequestUID, "0").Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+	var template models.VmsTrnRequestTemplate
+	if err := config.DB.First(&template, "trn_request_template_uid = ? AND emp_id = ? AND is_deleted = ?", templateDraft.TrnRequestTemplateUID, user.EmpID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found", "message": messages.ErrTemplateNotFound.Error()})
 		return
 	}
-	funcs.CreateTrnRequestActionLog(result.TrnRequestUID,
-		result.RefRequestStatusCode,
-		"รออนุมัติ จากต้นสังกัด",
-		user.EmpID,
-		"vehicle-user",
-		"",
-	)
 
-	funcs.CheckMustPassStatus(request.TrnRequestUID)
+	request := models.VmsTrnRequestRequest{
+		VehicleUserEmpID:       template.VehicleUserEmpID,
+		VehicleUserDeskPhone:   template.VehicleUserDeskPhone,
+		VehicleUserMobilePhone: template.VehicleUserMobilePhone,
+		ReserveStartDatetime:   templateDraft.StartDatetime,
+		ReserveEndDatetime:     templateDraft.EndDatetime,
+		RefTripTypeCode:        template.RefTripTypeCode,
+		WorkPlace:              template.WorkPlace,
+		WorkDescription:        template.WorkDescription,
+		NumberOfPassengers:     template.NumberOfPassengers,
+		Remark:                 template.Remark,
+		RefCostTypeCode:        template.RefCostTypeCode,
+		CostCenter:             template.CostCenter,
+		WbsNo:                  template.WbsNo,
+		NetworkNo:              template.NetworkNo,
+		ActivityNo:             template.ActivityNo,
+		PmOrderNo:              template.PmOrderNo,
+		MasCarpoolUID:          template.MasCarpoolUID,
+		RequestedVehicleType:   template.RequestedVehicleType,
+		MasVehicleUID:          template.MasVehicleUID,
+		MasCarPoolDriverUID:    template.MasCarPoolDriverUID,
+		IsPEAEmployeeDriver:    template.IsPEAEmployeeDriver,
+		DriverEmpID:            template.DriverEmpID,
+		DriverEmpDeskPhone:     template.DriverEmpDeskPhone,
+		DriverEmpMobilePhone:   template.DriverEmpMobilePhone,
+		PickupPlace:            template.PickupPlace,
+		ConfirmedRequestEmpID:  template.ConfirmedRequestEmpID,
+	}
+	if request.IsPEAEmployeeDriver == "1" && request.DriverEmpID != "" {
+		driverUser := funcs.GetUserEmpInfo(request.DriverEmpID)
+		request.DriverEmpName = driverUser.FullName
+	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Request created successfully",
-		"data":            result,
-		"request_no":      request.RequestNo,
-		"trn_request_uid": request.TrnRequestUID,
-	})
+	h.createDraftCopy(c, user, &request)
 }
 
 // MenuRequests godoc
@@ -724,6 +1003,7 @@ func (h *BookingUserHandler) UpdateVehicleUser(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "vehicle-user", &trnRequest, &request, user.EmpID, "vehicle-user")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -773,6 +1053,7 @@ func (h *BookingUserHandler) UpdateTrip(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "trip", &trnRequest, &request, user.EmpID, "vehicle-user")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -819,6 +1100,7 @@ func (h *BookingUserHandler) UpdatePickup(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "pickup", &trnRequest, &request, user.EmpID, "vehicle-user")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -864,6 +1146,7 @@ func (h *BookingUserHandler) UpdateDocument(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "document", &trnRequest, &request, user.EmpID, "vehicle-user")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -911,6 +1194,7 @@ func (h *BookingUserHandler) UpdateCost(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "cost", &trnRequest, &request, user.EmpID, "vehicle-user")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -958,6 +1242,7 @@ func (h *BookingUserHandler) UpdateVehicleType(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "vehicle-type", &trnRequest, &request, user.EmpID, "vehicle-user")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -1014,6 +1299,7 @@ func (h *BookingUserHandler) UpdateConfirmer(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	funcs.CreateTrnRequestChangeLog(request.TrnRequestUID, "confirmer", &trnRequest, &request, user.EmpID, "vehicle-user")
 
 	if err := config.DB.First(&result, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
@@ -1142,5 +1428,291 @@ func (h *BookingUserHandler) UpdateCanceled(c *gin.Context) {
 		request.CanceledRequestReason,
 	)
 
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
+
+func (h *BookingUserHandler) setRequestEmpInfo(request *models.VmsTrnRequestRequest, empID string) models.MasUserEmp {
+	createUser := funcs.GetUserEmpInfo(empID)
+	request.CreatedRequestEmpID = createUser.EmpID
+	request.CreatedRequestEmpName = createUser.FullName
+	request.CreatedRequestDeptSAP = createUser.DeptSAP
+	request.CreatedRequestDeptNameShort = createUser.DeptSAPShort
+	request.CreatedRequestDeptNameFull = createUser.DeptSAPFull
+	request.CreatedRequestDeskPhone = createUser.TelInternal
+	request.CreatedRequestMobilePhone = createUser.TelMobile
+	request.CreatedRequestPosition = createUser.Position
+	request.CreatedRequestDatetime = models.TimeWithZone{Time: time.Now()}
+
+	vehicleUser := funcs.GetUserEmpInfo(request.VehicleUserEmpID)
+	request.VehicleUserEmpID = vehicleUser.EmpID
+	request.VehicleUserEmpName = vehicleUser.FullName
+	request.VehicleUserDeptSAP = vehicleUser.DeptSAP
+	request.VehicleUserDeptNameShort = vehicleUser.DeptSAPShort
+	request.VehicleUserDeptNameFull = vehicleUser.DeptSAPFull
+	//request.VehicleUserDeskPhone = vehicleUser.TelInternal
+	//request.VehicleUserMobilePhone = vehicleUser.TelMobile
+	request.VehicleUserPosition = vehicleUser.Position
+
+	confirmUser := funcs.GetUserEmpInfo(request.ConfirmedRequestEmpID)
+	request.ConfirmedRequestEmpID = confirmUser.EmpID
+	request.ConfirmedRequestEmpName = confirmUser.FullName
+	request.ConfirmedRequestDeptSAP = confirmUser.DeptSAP
+	request.ConfirmedRequestDeptNameShort = confirmUser.DeptSAPShort
+	request.ConfirmedRequestDeptNameFull = confirmUser.DeptSAPFull
+	request.ConfirmedRequestDeskPhone = confirmUser.TelInternal
+	request.ConfirmedRequestMobilePhone = confirmUser.TelMobile
+	request.ConfirmedRequestPosition = confirmUser.Position
+	return vehicleUser
+}
+
+func (h *BookingUserHandler) setRequestVehicleDriver(request *models.VmsTrnRequestRequest) {
+	request.IsAdminChooseDriver = "0"
+	request.RefRequestTypeCode = 1
+	request.IsHaveSubRequest = "0"
+	request.MasVehicleEvUID = ""
+
+	if request.MasVehicleUID != nil && *request.MasVehicleUID != "" {
+		var vehicle models.VmsMasVehicleDepartment
+		if err := config.DB.First(&vehicle, "mas_vehicle_uid = ? AND is_deleted = '0'", request.MasVehicleUID).Error; err == nil {
+			request.MasVehicleDepartmentUID = &vehicle.MasVehicleDepartmentUID
+		}
+		var carpool models.VmsMasCarpoolVehicle
+		if err := config.DB.First(&carpool, "mas_vehicle_uid = ? AND is_deleted = '0'", request.MasVehicleUID).Error; err == nil {
+			request.MasCarpoolUID = &carpool.MasCarpoolUID
+		}
+	}
+
+	if request.MasCarpoolUID == nil || *request.MasCarpoolUID == "" {
+		request.MasCarpoolUID = nil
+	} else {
+		var carpool models.VmsMasCarpoolRequest
+		if err := config.DB.First(&carpool, "mas_carpool_uid = ? AND is_deleted = '0'", request.MasCarpoolUID).Error; err == nil {
+			request.MasCarpoolUID = &carpool.MasCarpoolUID
+			vehicleUser, _ := userhub.GetUserInfo(request.VehicleUserEmpID)
+
+			if carpool.RefCarpoolChooseCarID == 3 {
+				query := config.DB.Raw(`SELECT mas_vehicle_uid FROM fn_get_available_vehicles_view (?, ?, ?, ?) where mas_carpool_uid = ? and "CarTypeDetail" = ?`,
+					request.ReserveStartDatetime, request.ReserveEndDatetime, vehicleUser.BureauDeptSap, vehicleUser.BusinessArea, request.MasCarpoolUID, request.RequestedVehicleType)
+
+				var vehicle models.VmsMasVehicle
+				if err := query.First(&vehicle).
+					Select("mas_vehicle_uid").
+					Order("vehicle_mileage").
+					Limit(1).Error; err == nil && vehicle.MasVehicleUID != "" {
+					request.MasVehicleUID = &vehicle.MasVehicleUID
+				}
+			}
+			if carpool.RefCarpoolChooseDriverID == 3 {
+				query := config.DB.Raw(`SELECT mas_driver_uid FROM fn_get_available_drivers_view (?, ?, ?, ?,?) where mas_carpool_uid = ?`,
+					request.ReserveStartDatetime,
+					request.ReserveEndDatetime,
+					vehicleUser.BureauDeptSap,
+					vehicleUser.BusinessArea,
+					request.RefTripTypeCode,
+					request.MasCarpoolUID)
+
+				var driver models.VmsMasDriver
+				if err := query.Scan(&driver).
+					Select("mas_driver_uid, w_thismth.job_count, w_thismth.total_days").
+					Joins("LEFT JOIN public.vms_trn_driver_monthly_workload AS w_thismth ON w_thismth.workload_year = ? AND w_thismth.workload_month = ? AND w_thismth.driver_emp_id = d.driver_id AND w_thismth.is_deleted = ?", request.ReserveStartDatetime.Year(), request.ReserveStartDatetime.Month(), "0").
+					Order("total_days, job_count").
+					Limit(1).Error; err == nil && driver.MasDriverUID != "" {
+					request.MasCarPoolDriverUID = &driver.MasDriverUID
+				}
+			}
+		}
+	}
+
+	if request.MasVehicleUID != nil && *request.MasVehicleUID != "" {
+		var vehicle models.VmsMasVehicleDepartment
+		if err := config.DB.First(&vehicle, "mas_vehicle_uid = ? AND is_deleted = '0'", request.MasVehicleUID).Error; err == nil {
+			request.MasVehicleDepartmentUID = &vehicle.MasVehicleDepartmentUID
+		}
+	}
+
+	if request.IsPEAEmployeeDriver == "1" && request.DriverEmpID != "" {
+		driverUser := funcs.GetUserEmpInfo(request.DriverEmpID)
+		request.DriverEmpID = driverUser.EmpID
+		request.DriverEmpName = driverUser.FullName
+		request.DriverEmpDeptSAP = driverUser.DeptSAP
+		request.DriverEmpPosition = driverUser.Position
+		request.DriverEmpDeptNameShort = funcs.GetDeptSAPShort(driverUser.DeptSAP)
+		request.DriverEmpDeptNameFull = funcs.GetDeptSAPFull(driverUser.DeptSAP)
+		//request.DriverEmpDeskPhone = driverUser.TelInternal
+		//request.DriverEmpMobilePhone = driverUser.TelMobile
+
+	} else if request.MasCarPoolDriverUID != nil && *request.MasCarPoolDriverUID != "" {
+		var driver models.VmsMasDriver
+		if err := config.DB.First(&driver, "mas_driver_uid = ? AND is_deleted = '0'", request.MasCarPoolDriverUID).Error; err == nil {
+			request.DriverEmpID = driver.DriverID
+			request.DriverEmpName = driver.DriverName
+			request.DriverEmpDeptSAP = driver.DriverDeptSAP
+			request.DriverEmpDeptNameShort = funcs.GetDeptSAPShort(driver.DriverDeptSAP)
+			request.DriverEmpDeptNameFull = funcs.GetDeptSAPFull(driver.DriverDeptSAP)
+		}
+	}
+
+	if request.MasVehicleDepartmentUID == nil || *request.MasVehicleDepartmentUID == "" {
+		request.MasVehicleDepartmentUID = nil
+	}
+	if request.MasVehicleUID == nil || *request.MasVehicleUID == "" {
+		request.MasVehicleUID = nil
+	}
+	if request.MasCarPoolDriverUID == nil || *request.MasCarPoolDriverUID == "" {
+		request.MasCarPoolDriverUID = nil
+	}
+}
+
+func (h *BookingUserHandler) setRequestDraftNil(request *models.VmsTrnRequestRequest) {
+	if request.MasCarpoolUID != nil && *request.MasCarpoolUID == "" {
+		request.MasCarpoolUID = nil
+	}
+	if request.MasVehicleUID != nil && *request.MasVehicleUID == "" {
+		request.MasVehicleUID = nil
+	}
+	if request.MasVehicleDepartmentUID != nil && *request.MasVehicleDepartmentUID == "" {
+		request.MasVehicleDepartmentUID = nil
+	}
+	if request.MasCarPoolDriverUID != nil && *request.MasCarPoolDriverUID == "" {
+		request.MasCarPoolDriverUID = nil
+	}
+}
+
+func (h *BookingUserHandler) createDraftCopy(c *gin.Context, user *models.AuthenUserEmp, request *models.VmsTrnRequestRequest) {
+	request.TrnRequestUID = uuid.New().String()
+	request.RequestNo = ""
+	request.RefRequestStatusCode = "10" // ร่างคำขอ
+	request.CreatedAt = time.Now()
+	request.CreatedBy = user.EmpID
+	request.UpdatedAt = time.Now()
+	request.UpdatedBy = user.EmpID
+	request.IsDeleted = "0"
+	request.CreatedRequestEmpID = user.EmpID
+
+	clearedFields := funcs.RevalidateRequestCopy(request)
+	h.setRequestEmpInfo(request, user.EmpID)
+	h.setRequestDraftNil(request)
+
+	//request_no is generated on submit
+	if err := config.DB.Omit("request_no").Create(request).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request", "message": messages.ErrCreateRequest.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Draft created successfully",
+		"trn_request_uid": request.TrnRequestUID,
+		"cleared_fields":  clearedFields,
+		"validation":      funcs.ValidateRequestDraft(*request),
+	})
+}
+
+// GetAttachments godoc
+// @Summary Retrieve the attachments of a booking request
+// @Description This endpoint fetches the documents attached to a booking request. A document saved only in doc_no/doc_file is listed as an official letter with is_legacy_document set.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/booking-user/attachments/{trn_request_uid} [get]
+func (h *BookingUserHandler) GetAttachments(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var trnRequest models.VmsTrnRequestDocument
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&trnRequest, "trn_request_uid = ? and is_deleted = '0'", c.Param("trn_request_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, funcs.GetRequestAttachments(trnRequest.TrnRequestUID))
+}
+
+// AddAttachment godoc
+// @Summary Add an attachment to a booking request
+// @Description This endpoint attaches a document uploaded through /api/upload to a booking request until the trip starts. ref_request_attachment_type_code is one of /api/ref/request-attachment-type. The first attachment also fills doc_no/doc_file when they are empty.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestAttachmentRequest true "VmsTrnRequestAttachmentRequest data"
+// @Router /api/booking-user/add-attachment [post]
+func (h *BookingUserHandler) AddAttachment(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestAttachmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var trnRequest models.VmsTrnRequestDocument
+	query := h.SetQueryRole(user, config.DB)
+	query = funcs.SetQueryAttachmentCanUpdate(query)
+	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	beforeAttachments := funcs.GetAttachmentChangeValues(funcs.GetRequestAttachments(trnRequest.TrnRequestUID))
+	attachment, err := funcs.AddRequestAttachment(request, user.EmpID)
+	if err == messages.ErrAttachmentInvalid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.CreateTrnRequestChangeLog(trnRequest.TrnRequestUID, "attachment", beforeAttachments, funcs.GetAttachmentChangeValues(funcs.GetRequestAttachments(trnRequest.TrnRequestUID)), user.EmpID, "vehicle-user")
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Attachment created successfully", "result": attachment})
+}
+
+// DeleteAttachment godoc
+// @Summary Remove an attachment from a booking request
+// @Description This endpoint removes a document attached to a booking request until the trip starts. When doc_no/doc_file point to it they move to the next attachment.
+// @Tags Booking-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_attachment_uid path string true "TrnRequestAttachmentUID (trn_request_attachment_uid)"
+// @Router /api/booking-user/delete-attachment/{trn_request_attachment_uid} [delete]
+func (h *BookingUserHandler) DeleteAttachment(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var attachment models.VmsTrnRequestAttachment
+	if err := config.DB.First(&attachment, "trn_request_attachment_uid = ? and is_deleted = '0'", c.Param("trn_request_attachment_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found", "message": messages.ErrAttachmentNotFound.Error()})
+		return
+	}
+
+	var trnRequest models.VmsTrnRequestDocument
+	query := h.SetQueryRole(user, config.DB)
+	query = funcs.SetQueryAttachmentCanUpdate(query)
+	if err := query.First(&trnRequest, "trn_request_uid = ?", attachment.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	beforeAttachments := funcs.GetAttachmentChangeValues(funcs.GetRequestAttachments(trnRequest.TrnRequestUID))
+	if err := funcs.DeleteRequestAttachment(attachment, user.EmpID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.CreateTrnRequestChangeLog(trnRequest.TrnRequestUID, "attachment", beforeAttachments, funcs.GetAttachmentChangeValues(funcs.GetRequestAttachments(trnRequest.TrnRequestUID)), user.EmpID, "vehicle-user")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BookingWaitlistHandler struct {
+	Role string
+}
+
+func (h *BookingWaitlistHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
+	return query.Where("emp_id = ?", user.EmpID)
+}
+
+func (h *BookingWaitlistHandler) SetQueryStatusCanCancel(query *gorm.DB) *gorm.DB {
+	return query.Where("waitlist_status_code in ('0','1') and is_deleted = '0'")
+}
+
+// CreateWaitlist godoc
+// @Summary Join the booking waitlist
+// @Description This endpoint allows a booking user to wait for a carpool, vehicle or vehicle type in a window when nothing is available.
+// @Tags Booking-waitlist
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnWaitlistCreate true "VmsTrnWaitlistCreate data"
+// @Router /api/booking-waitlist/create-waitlist [post]
+func (h *BookingWaitlistHandler) CreateWaitlist(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnWaitlistCreate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if request.StartDatetime.IsZero() || !request.EndDatetime.After(request.StartDatetime.Time) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "End Date must be after Start Date", "message": messages.ErrInvalidDate.Error()})
+		return
+	}
+	if (request.MasCarpoolUID == nil || *request.MasCarpoolUID == "") &&
+		(request.MasVehicleUID == nil || *request.MasVehicleUID == "") &&
+		request.RequestedVehicleType == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mas_carpool_uid, mas_vehicle_uid or requested_vehicle_type is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	empUser := funcs.GetUserEmpInfo(user.EmpID)
+	waitlist := models.VmsTrnWaitlist{
+		TrnWaitlistUID:       uuid.New().String(),
+		EmpID:                empUser.EmpID,
+		EmpName:              empUser.FullName,
+		DeptSAP:              empUser.DeptSAP,
+		BureauDeptSap:        empUser.BureauDeptSap,
+		BusinessArea:         empUser.BusinessArea,
+		MasCarpoolUID:        request.MasCarpoolUID,
+		MasVehicleUID:        request.MasVehicleUID,
+		RequestedVehicleType: request.RequestedVehicleType,
+		StartDatetime:        request.StartDatetime,
+		EndDatetime:          request.EndDatetime,
+		WaitlistStatusCode:   "0",
+		CreatedAt:            time.Now(),
+		CreatedBy:            user.EmpID,
+		UpdatedAt:            time.Now(),
+		UpdatedBy:            user.EmpID,
+		IsDeleted:            "0",
+	}
+	if waitlist.MasCarpoolUID != nil && *waitlist.MasCarpoolUID == "" {
+		waitlist.MasCarpoolUID = nil
+	}
+	if waitlist.MasVehicleUID != nil && *waitlist.MasVehicleUID == "" {
+		waitlist.MasVehicleUID = nil
+	}
+
+	if err := config.DB.Create(&waitlist).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	waitlist.WaitlistStatusName = funcs.WaitlistStatusNameMap[waitlist.WaitlistStatusCode]
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Waitlist created successfully", "result": waitlist})
+}
+
+// SearchWaitlists godoc
+// @Summary List waitlist entries of the current user
+// @Description This endpoint returns the waitlist entries and active holds of the current user.
+// @Tags Booking-waitlist
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param waitlist_status_code query string false "Filter by waitlist status code (0: waiting, 1: offered, 2: accepted, 3: expired, 9: cancelled)"
+// @Router /api/booking-waitlist/search-waitlists [get]
+func (h *BookingWaitlistHandler) SearchWaitlists(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var waitlists []models.VmsTrnWaitlist
+	query := h.SetQueryRole(user, config.DB)
+	query = query.Where("is_deleted = ?", "0")
+	if statusCode := c.Query("waitlist_status_code"); statusCode != "" {
+		query = query.Where("waitlist_status_code = ?", statusCode)
+	}
+	if err := query.Order("created_at DESC").Find(&waitlists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	for i := range waitlists {
+		waitlists[i].WaitlistStatusName = funcs.WaitlistStatusNameMap[waitlists[i].WaitlistStatusCode]
+	}
+
+	c.JSON(http.StatusOK, waitlists)
+}
+
+// GetWaitlist godoc
+// @Summary Retrieve a specific waitlist entry
+// @Description This endpoint fetches details of a waitlist entry of the current user.
+// @Tags Booking-waitlist
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_waitlist_uid path string true "TrnWaitlistUID (trn_waitlist_uid)"
+// @Router /api/booking-waitlist/waitlist/{trn_waitlist_uid} [get]
+func (h *BookingWaitlistHandler) GetWaitlist(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var waitlist models.VmsTrnWaitlist
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&waitlist, "trn_waitlist_uid = ? AND is_deleted = ?", c.Param("trn_waitlist_uid"), "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Waitlist not found", "message": messages.ErrWaitlistNotFound.Error()})
+		return
+	}
+	waitlist.WaitlistStatusName = funcs.WaitlistStatusNameMap[waitlist.WaitlistStatusCode]
+
+	c.JSON(http.StatusOK, waitlist)
+}
+
+// UpdateCanceled godoc
+// @Summary Leave the waitlist or decline an offered vehicle
+// @Description This endpoint cancels a waitlist entry. If a vehicle is on hold for the entry, the vehicle is offered to the next waitlisted user.
+// @Tags Booking-waitlist
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnWaitlistUpdate true "VmsTrnWaitlistUpdate data"
+// @Router /api/booking-waitlist/update-canceled [put]
+func (h *BookingWaitlistHandler) UpdateCanceled(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnWaitlistUpdate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var waitlist models.VmsTrnWaitlist
+	query := h.SetQueryRole(user, config.DB)
+	query = h.SetQueryStatusCanCancel(query)
+	if err := query.First(&waitlist, "trn_waitlist_uid = ?", request.TrnWaitlistUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Waitlist can not update", "message": messages.ErrWaitlistNotFound.Error()})
+		return
+	}
+
+	if err := funcs.ReleaseWaitlistHold(waitlist, "9", user.EmpID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	if err := config.DB.First(&waitlist, "trn_waitlist_uid = ?", request.TrnWaitlistUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Waitlist not found", "message": messages.ErrWaitlistNotFound.Error()})
+		return
+	}
+	waitlist.WaitlistStatusName = funcs.WaitlistStatusNameMap[waitlist.WaitlistStatusCode]
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": waitlist})
+}
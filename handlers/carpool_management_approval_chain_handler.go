@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetCarpoolApprovalChain godoc
+// @Summary Get the approval chain of a carpool
+// @Description Get the ordered approval steps of a carpool with their conditions and approvers
+// @Tags Carpool-management
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_carpool_uid path string true "MasCarpoolUID (mas_carpool_uid)"
+// @Router /api/carpool-management/approval-chain/{mas_carpool_uid} [get]
+func (h *CarpoolManagementHandler) GetCarpoolApprovalChain(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	masCarpoolUID := c.Param("mas_carpool_uid")
+
+	var existingCarpool models.VmsMasCarpoolRequest
+	queryRole := h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
+	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", masCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	var chain models.VmsMasApprovalChain
+	if err := config.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("step_no") }).Preload("Steps.Approvers").
+		Where("mas_carpool_uid = ? AND is_deleted = ?", masCarpoolUID, "0").
+		First(&chain).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Approval chain not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, chain)
+}
+
+// UpdateCarpoolApprovalChain godoc
+// @Summary Set the approval chain of a carpool
+// @Description Replace the ordered approval steps of a carpool. Steps run in the waiting for approval statuses 20 and 40 and the request keeps that status until every matching step is approved. A step is approved by one manager at level_code or higher (level), any one of the approvers (any) or all of them (all).
+// @Tags Carpool-management
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_carpool_uid path string true "MasCarpoolUID (mas_carpool_uid)"
+// @Param data body models.VmsMasApprovalChainRequest true "VmsMasApprovalChainRequest data"
+// @Router /api/carpool-management/approval-chain-update/{mas_carpool_uid} [put]
+func (h *CarpoolManagementHandler) UpdateCarpoolApprovalChain(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	masCarpoolUID := c.Param("mas_carpool_uid")
+
+	var request models.VmsMasApprovalChainRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var existingCarpool models.VmsMasCarpoolRequest
+	queryRole := h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
+	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", masCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	chain := models.VmsMasApprovalChain{
+		MasApprovalChainUID: uuid.New().String(),
+		MasCarpoolUID:       masCarpoolUID,
+		ChainName:           request.ChainName,
+		IsActive:            request.IsActive,
+		CreatedAt:           time.Now(),
+		CreatedBy:           user.EmpID,
+		UpdatedAt:           time.Now(),
+		UpdatedBy:           user.EmpID,
+		IsDeleted:           "0",
+	}
+	if chain.IsActive != "0" {
+		chain.IsActive = "1"
+	}
+	for i, stepRequest := range request.Steps {
+		if _, ok := funcs.ApprovalChainStatusRoles[stepRequest.RefRequestStatusCode]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("step %d: ref_request_status_code must be 20 or 40", i+1), "message": messages.ErrInvalidRequest.Error()})
+			return
+		}
+		if !funcs.Contains(funcs.ApprovalModes, stepRequest.ApprovalMode) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("step %d: approval_mode must be level, any or all", i+1), "message": messages.ErrInvalidRequest.Error()})
+			return
+		}
+		if (stepRequest.ApprovalMode == "level" && stepRequest.LevelCode == "") || (stepRequest.ApprovalMode != "level" && len(stepRequest.ApproverEmpIDs) == 0) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("step %d: level_code or approver_emp_ids is required", i+1), "message": messages.ErrInvalidRequest.Error()})
+			return
+		}
+
+		step := models.VmsMasApprovalChainStep{
+			MasApprovalChainStepUID:   uuid.New().String(),
+			MasApprovalChainUID:       chain.MasApprovalChainUID,
+			StepNo:                    i + 1,
+			StepName:                  stepRequest.StepName,
+			RefRequestStatusCode:      stepRequest.RefRequestStatusCode,
+			ApprovalMode:              stepRequest.ApprovalMode,
+			LevelCode:                 stepRequest.LevelCode,
+			ConditionRefTripTypeCode:  stepRequest.ConditionRefTripTypeCode,
+			ConditionMinDurationHours: stepRequest.ConditionMinDurationHours,
+			ConditionIsOutOfProvince:  stepRequest.ConditionIsOutOfProvince,
+			ConditionRefCostTypeCode:  stepRequest.ConditionRefCostTypeCode,
+		}
+		if step.ApprovalMode != "level" {
+			for _, empID := range stepRequest.ApproverEmpIDs {
+				empUser := funcs.GetUserEmpInfo(empID)
+				if empUser.EmpID == "" {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("step %d: approver %s not found", i+1, empID), "message": messages.ErrInvalidRequest.Error()})
+					return
+				}
+				step.Approvers = append(step.Approvers, models.VmsMasApprovalChainApprover{
+					MasApprovalChainApproverUID: uuid.New().String(),
+					MasApprovalChainStepUID:     step.MasApprovalChainStepUID,
+					ApproverEmpID:               empUser.EmpID,
+					ApproverEmpName:             empUser.FullName,
+					ApproverPosition:            empUser.Position,
+					ApproverDeptSAP:             empUser.DeptSAP,
+				})
+			}
+		}
+		chain.Steps = append(chain.Steps, step)
+	}
+
+	chains := funcs.GetMasterDataSnapshots("vms_mas_approval_chain", "mas_approval_chain_uid", "mas_carpool_uid", masCarpoolUID)
+	if err := config.DB.Model(&models.VmsMasApprovalChain{}).
+		Where("mas_carpool_uid = ? AND is_deleted = ?", masCarpoolUID, "0").
+		UpdateColumns(map[string]interface{}{
+			"is_deleted": "1",
+			"updated_by": user.EmpID,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if len(chain.Steps) > 0 {
+		if err := config.DB.Create(&chain).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+			return
+		}
+	}
+
+	funcs.AuditMasterDataRows(c, user, "vms_mas_approval_chain", "mas_approval_chain_uid", "mas_carpool_uid", masCarpoolUID, chains)
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": chain})
+}
@@ -43,6 +43,8 @@ func (h *CarpoolManagementHandler) SearchCarpoolDriver(c *gin.Context) {
 
 	var existingCarpool models.VmsMasCarpoolRequest
 	queryRole := h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
 	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", masCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 		return
@@ -171,6 +173,8 @@ func (h *CarpoolManagementHandler) CreateCarpoolDriver(c *gin.Context) {
 
 	for i := range requests {
 		queryRole := h.SetQueryRole(user, config.DB)
+		queryRole = h.SetQueryRoleDept(user, queryRole)
+		queryRole = queryRole.Table("vms_mas_carpool cp")
 		if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", requests[i].MasCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 			return
@@ -209,16 +213,18 @@ func (h *CarpoolManagementHandler) CreateCarpoolDriver(c *gin.Context) {
 		requests[i].EndDate = models.TimeWithZone{Time: time.Now().AddDate(1, 0, 0)}
 	}
 
+	masDriverUIDs := []string{}
+	for i := range requests {
+		masDriverUIDs = append(masDriverUIDs, requests[i].MasDriverUID)
+	}
+	snapshots := GetCarpoolSnapshots(requests[0].MasCarpoolUID)
+	AddCarpoolJoinSnapshots(snapshots, masDriverUIDs, nil)
 	if err := config.DB.Create(&requests).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	//update vms_mas_driver set mas_carpool_uid
-	masDriverUIDs := []string{}
-	for i := range requests {
-		masDriverUIDs = append(masDriverUIDs, requests[i].MasDriverUID)
-	}
 	query := config.DB.Model(&models.VmsMasDriver{}).Where("mas_driver_uid in (?)", masDriverUIDs).
 		UpdateColumns(map[string]interface{}{
 			"mas_carpool_uid": requests[0].MasCarpoolUID,
@@ -229,6 +235,7 @@ func (h *CarpoolManagementHandler) CreateCarpoolDriver(c *gin.Context) {
 		return
 	}
 
+	AuditCarpoolChange(c, user, requests[0].MasCarpoolUID, snapshots)
 	c.JSON(http.StatusCreated, gin.H{
 		"message":      "Carpool drivers created successfully",
 		"data":         requests,
@@ -260,10 +267,13 @@ func (h *CarpoolManagementHandler) DeleteCarpoolDriver(c *gin.Context) {
 	}
 	var existingCarpool models.VmsMasCarpoolRequest
 	queryRole := h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
 	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", driver.MasCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	snapshots := GetCarpoolSnapshots(driver.MasCarpoolUID)
 	if err := config.DB.Model(&driver).UpdateColumns(map[string]interface{}{
 		"is_active":  "0",
 		"is_deleted": "1",
@@ -282,6 +292,7 @@ func (h *CarpoolManagementHandler) DeleteCarpoolDriver(c *gin.Context) {
 		return
 	}
 
+	AuditCarpoolChange(c, user, driver.MasCarpoolUID, snapshots)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool driver deleted successfully", "carpool_name": GetCarpoolName(driver.MasCarpoolUID)})
 }
 
@@ -465,6 +476,8 @@ func (h *CarpoolManagementHandler) SetActiveCarpoolDriver(c *gin.Context) {
 
 	var existingCarpool models.VmsMasCarpoolRequest
 	queryRole := h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
 	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", driver.MasCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 		return
@@ -474,6 +487,7 @@ func (h *CarpoolManagementHandler) SetActiveCarpoolDriver(c *gin.Context) {
 	driver.UpdatedAt = time.Now()
 	driver.UpdatedBy = user.EmpID
 
+	before := funcs.GetMasterDataSnapshot("vms_mas_carpool_driver", "mas_carpool_driver_uid", driver.MasCarpoolDriverUID)
 	//update is_active to 1 in carpool_driver
 	if err := config.DB.Model(&models.VmsMasCarpoolDriver{}).Where("mas_carpool_driver_uid = ?", driver.MasCarpoolDriverUID).UpdateColumns(map[string]interface{}{
 		"is_active":  request.IsActive,
@@ -484,6 +498,7 @@ func (h *CarpoolManagementHandler) SetActiveCarpoolDriver(c *gin.Context) {
 		return
 	}
 
+	funcs.AuditMasterDataRow(c, user, "vms_mas_carpool_driver", "mas_carpool_driver_uid", driver.MasCarpoolDriverUID, before)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool driver active status updated successfully", "data": request, "carpool_name": GetCarpoolName(driver.MasCarpoolUID)})
 }
 
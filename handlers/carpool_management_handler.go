@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +11,7 @@ import (
 	"vms_plus_be/funcs"
 	"vms_plus_be/messages"
 	"vms_plus_be/models"
+	"vms_plus_be/permission"
 	"vms_plus_be/userhub"
 
 	"github.com/gin-gonic/gin"
@@ -32,42 +32,15 @@ func (h *CarpoolManagementHandler) SetQueryRole(user *models.AuthenUserEmp, quer
 }
 
 func (h *CarpoolManagementHandler) SetQueryRoleDept(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
-	if slices.Contains(user.Roles, "admin-super") {
-		return query
-	}
-	if slices.Contains(user.Roles, "admin-region") {
-		return query.Where("cp.carpool_main_business_area = ?", user.BusinessArea)
-	}
-	if slices.Contains(user.Roles, "admin-department") {
-		return query.Where("exists (select 1 from vms_mas_carpool_authorized_dept cad where cad.mas_carpool_uid=cp.mas_carpool_uid and public.fn_get_bureau_dept_sap(cad.dept_sap) = ?)", user.BureauDeptSap)
-	}
-	return nil
+	return permission.SetQueryCarpoolScope(user, query, "cp")
 }
 
 func (h *CarpoolManagementHandler) SetQueryRoleDeptVehicle(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
-	if slices.Contains(user.Roles, "admin-super") {
-		return query
-	}
-	if slices.Contains(user.Roles, "admin-region") {
-		return query.Where("d.bureau_ba like ?", user.BusinessArea[:1]+"%")
-	}
-	if slices.Contains(user.Roles, "admin-department") {
-		return query.Where("d.bureau_dept_sap = ?", user.BureauDeptSap)
-	}
-	return nil
+	return permission.SetQueryDepartmentScope(user, query, "d")
 }
 
 func (h *CarpoolManagementHandler) SetQueryRoleDeptDriver(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
-	if slices.Contains(user.Roles, "admin-super") {
-		return query
-	}
-	if slices.Contains(user.Roles, "admin-region") {
-		return query.Where("d.bureau_ba like ?", user.BusinessArea[:1]+"%")
-	}
-	if slices.Contains(user.Roles, "admin-department") {
-		return query.Where("d.bureau_dept_sap = ?", user.BureauDeptSap)
-	}
-	return nil
+	return permission.SetQueryDepartmentScope(user, query, "d")
 }
 
 func GetCarpoolTypeName(carpoolType string) string {
@@ -221,6 +194,55 @@ func CheckMainCarpoolApprover(masCarpoolUID string) {
 	}
 }
 
+// carpoolAuditTables are the master-data tables whose rows belong to a carpool through mas_carpool_uid, with their key column
+var carpoolAuditTables = [][2]string{
+	{"vms_mas_carpool", "mas_carpool_uid"},
+	{"vms_mas_carpool_authorized_dept", "mas_carpool_authorized_dept_uid"},
+	{"vms_mas_carpool_admin", "mas_carpool_admin_uid"},
+	{"vms_mas_carpool_approver", "mas_carpool_approver_uid"},
+	{"vms_mas_carpool_vehicle", "mas_carpool_vehicle_uid"},
+	{"vms_mas_carpool_driver", "mas_carpool_driver_uid"},
+	{"vms_mas_driver", "mas_driver_uid"},
+}
+
+// GetCarpoolVehicleUIDs selects the vehicles that are or were in a carpool, their vehicle departments follow the carpool
+func GetCarpoolVehicleUIDs(masCarpoolUID string) *gorm.DB {
+	return config.DB.Table("vms_mas_carpool_vehicle").Select("mas_vehicle_uid").Where("mas_carpool_uid = ?", masCarpoolUID)
+}
+
+// GetCarpoolSnapshots reads every master-data row of a carpool before it is changed, for AuditCarpoolChange
+func GetCarpoolSnapshots(masCarpoolUID string) map[string]map[string]map[string]interface{} {
+	snapshots := make(map[string]map[string]map[string]interface{})
+	for _, table := range carpoolAuditTables {
+		snapshots[table[0]] = funcs.GetMasterDataSnapshots(table[0], table[1], "mas_carpool_uid", masCarpoolUID)
+	}
+	snapshots["vms_mas_vehicle_department"] = funcs.GetMasterDataSnapshots("vms_mas_vehicle_department", "mas_vehicle_department_uid", "mas_vehicle_uid", GetCarpoolVehicleUIDs(masCarpoolUID))
+	return snapshots
+}
+
+// AddCarpoolJoinSnapshots reads the drivers and vehicle departments about to join a carpool,
+// so they are recorded as updated rather than created
+func AddCarpoolJoinSnapshots(snapshots map[string]map[string]map[string]interface{}, masDriverUIDs, masVehicleUIDs []string) {
+	if len(masDriverUIDs) > 0 {
+		for key, row := range funcs.GetMasterDataSnapshots("vms_mas_driver", "mas_driver_uid", "mas_driver_uid", masDriverUIDs) {
+			snapshots["vms_mas_driver"][key] = row
+		}
+	}
+	if len(masVehicleUIDs) > 0 {
+		for key, row := range funcs.GetMasterDataSnapshots("vms_mas_vehicle_department", "mas_vehicle_department_uid", "mas_vehicle_uid", masVehicleUIDs) {
+			snapshots["vms_mas_vehicle_department"][key] = row
+		}
+	}
+}
+
+// AuditCarpoolChange records every master-data row of a carpool that changed since GetCarpoolSnapshots
+func AuditCarpoolChange(c *gin.Context, user *models.AuthenUserEmp, masCarpoolUID string, snapshots map[string]map[string]map[string]interface{}) {
+	for _, table := range carpoolAuditTables {
+		funcs.AuditMasterDataRows(c, user, table[0], table[1], "mas_carpool_uid", masCarpoolUID, snapshots[table[0]])
+	}
+	funcs.AuditMasterDataRows(c, user, "vms_mas_vehicle_department", "mas_vehicle_department_uid", "mas_vehicle_uid", GetCarpoolVehicleUIDs(masCarpoolUID), snapshots["vms_mas_vehicle_department"])
+}
+
 // SearchCarpools godoc
 // @Summary Search carpool management
 // @Description Search carpool management by criteria
@@ -520,6 +542,17 @@ func (h *CarpoolManagementHandler) CreateCarpool(c *gin.Context) {
 		carpool.CarpoolAuthorizedDepts[i].IsDeleted = "0"
 	}
 
+	snapshots := GetCarpoolSnapshots(carpool.MasCarpoolUID)
+	masDriverUIDs := []string{}
+	for i := range carpool.CarPoolDrivers {
+		masDriverUIDs = append(masDriverUIDs, carpool.CarPoolDrivers[i].MasDriverUID)
+	}
+	masVehicleUIDs := []string{}
+	for i := range carpool.CarPoolVehicles {
+		masVehicleUIDs = append(masVehicleUIDs, carpool.CarPoolVehicles[i].MasVehicleUID)
+	}
+	AddCarpoolJoinSnapshots(snapshots, masDriverUIDs, masVehicleUIDs)
+
 	if err := config.DB.Create(&carpool).Error; err != nil {
 		log.Println("DB Error:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
@@ -619,10 +652,6 @@ func (h *CarpoolManagementHandler) CreateCarpool(c *gin.Context) {
 			return
 		}
 		//update vms_mas_driver set mas_carpool_uid
-		masDriverUIDs := []string{}
-		for i := range carpool.CarPoolDrivers {
-			masDriverUIDs = append(masDriverUIDs, carpool.CarPoolDrivers[i].MasDriverUID)
-		}
 		query := config.DB.Model(&models.VmsMasDriver{}).Where("mas_driver_uid in (?)", masDriverUIDs).
 			Update("mas_carpool_uid", carpool.MasCarpoolUID)
 		if err := query.Error; err != nil {
@@ -630,6 +659,7 @@ func (h *CarpoolManagementHandler) CreateCarpool(c *gin.Context) {
 			return
 		}
 	}
+	AuditCarpoolChange(c, user, carpool.MasCarpoolUID, snapshots)
 	c.JSON(http.StatusCreated, gin.H{
 		"message":         "Carpool created successfully",
 		"data":            carpool,
@@ -710,6 +740,7 @@ func (h *CarpoolManagementHandler) UpdateCarpool(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	snapshots := GetCarpoolSnapshots(existingCarpool.MasCarpoolUID)
 	request.MasCarpoolUID = existingCarpool.MasCarpoolUID
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
@@ -764,6 +795,7 @@ func (h *CarpoolManagementHandler) UpdateCarpool(c *gin.Context) {
 		return
 	}
 
+	AuditCarpoolChange(c, user, masCarpoolUID, snapshots)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool updated successfully", "data": request, "carpool_name": GetCarpoolName(masCarpoolUID)})
 }
 
@@ -816,6 +848,7 @@ func (h *CarpoolManagementHandler) DeleteCarpool(c *gin.Context) {
 		})
 		return
 	}
+	snapshots := GetCarpoolSnapshots(request.MasCarpoolUID)
 	if err := config.DB.Model(&carpool).UpdateColumns(map[string]interface{}{
 		"is_deleted": "1",
 		"is_active":  "0",
@@ -867,6 +900,7 @@ func (h *CarpoolManagementHandler) DeleteCarpool(c *gin.Context) {
 		return
 	}
 
+	AuditCarpoolChange(c, user, request.MasCarpoolUID, snapshots)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool deleted successfully", "carpool_name": GetCarpoolName(request.MasCarpoolUID)})
 }
 
@@ -901,6 +935,7 @@ func (h *CarpoolManagementHandler) SetActiveCarpool(c *gin.Context) {
 		return
 	}
 
+	snapshots := GetCarpoolSnapshots(request.MasCarpoolUID)
 	carpool.IsActive = request.IsActive
 	carpool.UpdatedAt = time.Now()
 	carpool.UpdatedBy = user.EmpID
@@ -923,6 +958,7 @@ func (h *CarpoolManagementHandler) SetActiveCarpool(c *gin.Context) {
 		return
 	}
 
+	AuditCarpoolChange(c, user, request.MasCarpoolUID, snapshots)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool active status updated successfully", "data": request, "carpool_name": GetCarpoolName(request.MasCarpoolUID)})
 }
 
@@ -1151,12 +1187,14 @@ func (h *CarpoolManagementHandler) CreateCarpoolAdmin(c *gin.Context) {
 		requests[i].AdminPosition = empUser.Position
 	}
 
+	admins := funcs.GetMasterDataSnapshots("vms_mas_carpool_admin", "mas_carpool_admin_uid", "mas_carpool_uid", requests[0].MasCarpoolUID)
 	if err := config.DB.Create(&requests).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
 		return
 	}
 
 	CheckMainCarpoolAdmin(requests[0].MasCarpoolUID)
+	funcs.AuditMasterDataRows(c, user, "vms_mas_carpool_admin", "mas_carpool_admin_uid", "mas_carpool_uid", requests[0].MasCarpoolUID, admins)
 	c.JSON(http.StatusCreated, gin.H{
 		"message":      "Admin carpools created successfully",
 		"data":         requests,
@@ -1203,6 +1241,7 @@ func (h *CarpoolManagementHandler) UpdateCarpoolAdmin(c *gin.Context) {
 		return
 	}
 
+	admins := funcs.GetMasterDataSnapshots("vms_mas_carpool_admin", "mas_carpool_admin_uid", "mas_carpool_uid", existingAdmin.MasCarpoolUID)
 	request.MasCarpoolAdminUID = existingAdmin.MasCarpoolAdminUID
 	request.CreatedAt = existingAdmin.CreatedAt
 	request.CreatedBy = existingAdmin.CreatedBy
@@ -1221,6 +1260,7 @@ func (h *CarpoolManagementHandler) UpdateCarpoolAdmin(c *gin.Context) {
 		return
 	}
 
+	funcs.AuditMasterDataRows(c, user, "vms_mas_carpool_admin", "mas_carpool_admin_uid", "mas_carpool_uid", existingAdmin.MasCarpoolUID, admins)
 	c.JSON(http.StatusOK, gin.H{"message": "Admin carpool updated successfully", "data": request, "carpool_name": GetCarpoolName(request.MasCarpoolUID)})
 }
 
@@ -1249,6 +1289,16 @@ func (h *CarpoolManagementHandler) UpdateCarpoolMainAdmin(c *gin.Context) {
 		return
 	}
 
+	var carpool models.VmsMasCarpoolList
+	queryRole = h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
+	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", existingAdmin.MasCarpoolUID, "0").First(&carpool).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	admins := funcs.GetMasterDataSnapshots("vms_mas_carpool_admin", "mas_carpool_admin_uid", "mas_carpool_uid", existingAdmin.MasCarpoolUID)
 	if err := config.DB.Model(&models.VmsMasCarpoolAdmin{}).
 		Where("mas_carpool_uid = ?", existingAdmin.MasCarpoolUID).
 		Update("is_main_admin", "0").Error; err != nil {
@@ -1265,6 +1315,7 @@ func (h *CarpoolManagementHandler) UpdateCarpoolMainAdmin(c *gin.Context) {
 		return
 	}
 
+	funcs.AuditMasterDataRows(c, user, "vms_mas_carpool_admin", "mas_carpool_admin_uid", "mas_carpool_uid", existingAdmin.MasCarpoolUID, admins)
 	c.JSON(http.StatusOK, gin.H{"message": "Admin carpool updated successfully", "carpool_name": GetCarpoolName(existingAdmin.MasCarpoolUID)})
 }
 
@@ -1300,6 +1351,7 @@ func (h *CarpoolManagementHandler) DeleteCarpoolAdmin(c *gin.Context) {
 		return
 	}
 
+	admins := funcs.GetMasterDataSnapshots("vms_mas_carpool_admin", "mas_carpool_admin_uid", "mas_carpool_uid", adminCarpool.MasCarpoolUID)
 	if err := config.DB.Model(&adminCarpool).UpdateColumns(map[string]interface{}{
 		"is_deleted": "1",
 		"updated_by": user.EmpID,
@@ -1310,6 +1362,7 @@ func (h *CarpoolManagementHandler) DeleteCarpoolAdmin(c *gin.Context) {
 	}
 
 	CheckMainCarpoolAdmin(adminCarpool.MasCarpoolUID)
+	funcs.AuditMasterDataRows(c, user, "vms_mas_carpool_admin", "mas_carpool_admin_uid", "mas_carpool_uid", adminCarpool.MasCarpoolUID, admins)
 	c.JSON(http.StatusOK, gin.H{"message": "Admin carpool deleted successfully", "carpool_name": GetCarpoolName(adminCarpool.MasCarpoolUID)})
 }
 
@@ -1623,6 +1676,8 @@ func (h *CarpoolManagementHandler) CreateCarpoolApprover(c *gin.Context) {
 	for i := range requests {
 		var existingCarpool models.VmsMasCarpoolRequest
 		queryRole := h.SetQueryRole(user, config.DB)
+		queryRole = h.SetQueryRoleDept(user, queryRole)
+		queryRole = queryRole.Table("vms_mas_carpool cp")
 		if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", requests[i].MasCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 			return
@@ -1653,12 +1708,14 @@ func (h *CarpoolManagementHandler) CreateCarpoolApprover(c *gin.Context) {
 		requests[i].ApproverPosition = empUser.Position
 	}
 
+	approvers := funcs.GetMasterDataSnapshots("vms_mas_carpool_approver", "mas_carpool_approver_uid", "mas_carpool_uid", requests[0].MasCarpoolUID)
 	if err := config.DB.Create(&requests).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	CheckMainCarpoolApprover(requests[0].MasCarpoolUID)
+	funcs.AuditMasterDataRows(c, user, "vms_mas_carpool_approver", "mas_carpool_approver_uid", "mas_carpool_uid", requests[0].MasCarpoolUID, approvers)
 	c.JSON(http.StatusCreated, gin.H{
 		"message":      "Approver carpools created successfully",
 		"data":         requests,
@@ -1705,6 +1762,7 @@ func (h *CarpoolManagementHandler) UpdateCarpoolApprover(c *gin.Context) {
 		return
 	}
 
+	approvers := funcs.GetMasterDataSnapshots("vms_mas_carpool_approver", "mas_carpool_approver_uid", "mas_carpool_uid", existingApprover.MasCarpoolUID)
 	request.MasCarpoolApproverUID = existingApprover.MasCarpoolApproverUID
 	request.CreatedAt = existingApprover.CreatedAt
 	request.CreatedBy = existingApprover.CreatedBy
@@ -1723,6 +1781,7 @@ func (h *CarpoolManagementHandler) UpdateCarpoolApprover(c *gin.Context) {
 		return
 	}
 
+	funcs.AuditMasterDataRows(c, user, "vms_mas_carpool_approver", "mas_carpool_approver_uid", "mas_carpool_uid", existingApprover.MasCarpoolUID, approvers)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool approver updated successfully", "data": request, "carpool_name": GetCarpoolName(existingApprover.MasCarpoolUID)})
 }
 
@@ -1758,6 +1817,7 @@ func (h *CarpoolManagementHandler) UpdateCarpoolMainApprover(c *gin.Context) {
 		return
 	}
 
+	approvers := funcs.GetMasterDataSnapshots("vms_mas_carpool_approver", "mas_carpool_approver_uid", "mas_carpool_uid", existingApprover.MasCarpoolUID)
 	if err := config.DB.Model(&models.VmsMasCarpoolApprover{}).
 		Where("mas_carpool_uid = ?", existingApprover.MasCarpoolUID).
 		Update("is_main_approver", "0").Error; err != nil {
@@ -1774,6 +1834,7 @@ func (h *CarpoolManagementHandler) UpdateCarpoolMainApprover(c *gin.Context) {
 		return
 	}
 
+	funcs.AuditMasterDataRows(c, user, "vms_mas_carpool_approver", "mas_carpool_approver_uid", "mas_carpool_uid", existingApprover.MasCarpoolUID, approvers)
 	c.JSON(http.StatusOK, gin.H{"message": "Approver carpool updated successfully", "carpool_name": GetCarpoolName(existingApprover.MasCarpoolUID)})
 }
 
@@ -1809,6 +1870,7 @@ func (h *CarpoolManagementHandler) DeleteCarpoolApprover(c *gin.Context) {
 		return
 	}
 
+	approvers := funcs.GetMasterDataSnapshots("vms_mas_carpool_approver", "mas_carpool_approver_uid", "mas_carpool_uid", approver.MasCarpoolUID)
 	if err := config.DB.Model(&approver).UpdateColumns(map[string]interface{}{
 		"is_deleted": "1",
 		"updated_by": user.EmpID,
@@ -1818,5 +1880,6 @@ func (h *CarpoolManagementHandler) DeleteCarpoolApprover(c *gin.Context) {
 		return
 	}
 	CheckMainCarpoolApprover(approver.MasCarpoolUID)
+	funcs.AuditMasterDataRows(c, user, "vms_mas_carpool_approver", "mas_carpool_approver_uid", "mas_carpool_uid", approver.MasCarpoolUID, approvers)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool approver deleted successfully", "carpool_name": GetCarpoolName(approver.MasCarpoolUID)})
 }
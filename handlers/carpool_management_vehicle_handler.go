@@ -43,6 +43,8 @@ func (h *CarpoolManagementHandler) SearchCarpoolVehicle(c *gin.Context) {
 
 	var existingCarpool models.VmsMasCarpoolRequest
 	queryRole := h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
 	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", masCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 		return
@@ -178,6 +180,8 @@ func (h *CarpoolManagementHandler) CreateCarpoolVehicle(c *gin.Context) {
 	var existingCarpool models.VmsMasCarpoolRequest
 	for i := range requests {
 		queryRole := h.SetQueryRole(user, config.DB)
+		queryRole = h.SetQueryRoleDept(user, queryRole)
+		queryRole = queryRole.Table("vms_mas_carpool cp")
 		if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", requests[i].MasCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 			return
@@ -221,6 +225,12 @@ func (h *CarpoolManagementHandler) CreateCarpoolVehicle(c *gin.Context) {
 		}
 	}
 
+	snapshots := GetCarpoolSnapshots(requests[0].MasCarpoolUID)
+	masVehicleUIDs := make([]string, len(requests))
+	for i := range requests {
+		masVehicleUIDs[i] = requests[i].MasVehicleUID
+	}
+	AddCarpoolJoinSnapshots(snapshots, nil, masVehicleUIDs)
 	if err := config.DB.Create(&requests).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
 		return
@@ -238,6 +248,7 @@ func (h *CarpoolManagementHandler) CreateCarpoolVehicle(c *gin.Context) {
 		return
 	}
 
+	AuditCarpoolChange(c, user, requests[0].MasCarpoolUID, snapshots)
 	c.JSON(http.StatusCreated, gin.H{
 		"message":      "Carpool vehicles created successfully",
 		"data":         requests,
@@ -269,10 +280,13 @@ func (h *CarpoolManagementHandler) DeleteCarpoolVehicle(c *gin.Context) {
 	}
 	var existingCarpool models.VmsMasCarpoolRequest
 	queryRole := h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
 	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", vehicle.MasCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	snapshots := GetCarpoolSnapshots(vehicle.MasCarpoolUID)
 	if err := config.DB.Model(&vehicle).UpdateColumns(map[string]interface{}{
 		"is_active":  "0",
 		"is_deleted": "1",
@@ -289,6 +303,7 @@ func (h *CarpoolManagementHandler) DeleteCarpoolVehicle(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vms_mas_department", "message": messages.ErrInternalServer.Error()})
 		return
 	}
+	AuditCarpoolChange(c, user, vehicle.MasCarpoolUID, snapshots)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool vehicle deleted successfully", "carpool_name": GetCarpoolName(vehicle.MasCarpoolUID)})
 }
 
@@ -471,11 +486,14 @@ func (h *CarpoolManagementHandler) SetActiveCarpoolVehicle(c *gin.Context) {
 
 	var existingCarpool models.VmsMasCarpoolRequest
 	queryRole := h.SetQueryRole(user, config.DB)
+	queryRole = h.SetQueryRoleDept(user, queryRole)
+	queryRole = queryRole.Table("vms_mas_carpool cp")
 	if err := queryRole.Where("mas_carpool_uid = ? AND is_deleted = ?", vehicle.MasCarpoolUID, "0").First(&existingCarpool).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Carpool not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
 
+	before := funcs.GetMasterDataSnapshot("vms_mas_carpool_vehicle", "mas_carpool_vehicle_uid", vehicle.MasCarpoolVehicleUID)
 	//update is_active to 1 in carpool_vehicle
 	if err := config.DB.Model(&models.VmsMasCarpoolVehicle{}).Where("mas_carpool_vehicle_uid = ?", vehicle.MasCarpoolVehicleUID).UpdateColumns(map[string]interface{}{
 		"is_active":  request.IsActive,
@@ -486,6 +504,7 @@ func (h *CarpoolManagementHandler) SetActiveCarpoolVehicle(c *gin.Context) {
 		return
 	}
 
+	funcs.AuditMasterDataRow(c, user, "vms_mas_carpool_vehicle", "mas_carpool_vehicle_uid", vehicle.MasCarpoolVehicleUID, before)
 	c.JSON(http.StatusOK, gin.H{"message": "Carpool vehicle active status updated successfully", "data": request, "carpool_name": GetCarpoolName(vehicle.MasCarpoolUID)})
 }
 
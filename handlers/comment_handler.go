@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CommentHandler struct {
+	Role string
+}
+
+// requestRoleScopes are the roles that can see a request and how each of them is scoped, checked in order
+var requestRoleScopes = []struct {
+	UserRoles    []string
+	Role         string
+	SetQueryRole func(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB
+}{
+	{[]string{"vehicle-user"}, "vehicle-user", func(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
+		return query.Where("vms_trn_request.created_request_emp_id = ? or vms_trn_request.vehicle_user_emp_id = ?", user.EmpID, user.EmpID)
+	}},
+	{[]string{"driver"}, "driver", (&ReceivedKeyDriverHandler{}).SetQueryRole},
+	{[]string{"level1-approval"}, "level1-approval", (&BookingConfirmerHandler{}).SetQueryRole},
+	{[]string{"admin-department", "admin-carpool", "admin-department-main"}, "admin-department", (&BookingAdminHandler{}).SetQueryRole},
+	{[]string{"approval-department", "approval-carpool"}, "approval-department", (&BookingFinalHandler{}).SetQueryRole},
+}
+
+// GetRequestRole returns the role the user sees the request with, empty when the user cannot see it
+func (h *CommentHandler) GetRequestRole(user *models.AuthenUserEmp, trnRequestUID string) string {
+	for _, scope := range requestRoleScopes {
+		hasRole := false
+		for _, role := range scope.UserRoles {
+			if funcs.Contains(user.Roles, role) {
+				hasRole = true
+				break
+			}
+		}
+		if !hasRole || user.EmpID == "" {
+			continue
+		}
+		var count int64
+		query := scope.SetQueryRole(user, config.DB.Table("vms_trn_request"))
+		query.Where("vms_trn_request.trn_request_uid = ? and vms_trn_request.is_deleted = '0'", trnRequestUID).Count(&count)
+		if count > 0 {
+			return scope.Role
+		}
+	}
+	return ""
+}
+
+// ListComments godoc
+// @Summary List the comment thread of a request
+// @Description This endpoint returns the comments of a request, oldest first, with replies under the comment they answer. Only users who can see the request can read its comments.
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/comment/comments/{trn_request_uid} [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	trnRequestUID := c.Param("trn_request_uid")
+	if h.GetRequestRole(user, trnRequestUID) == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Request not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	var comments []models.VmsTrnRequestComment
+	if err := config.DB.Preload("Mentions").Preload("Attachments").
+		Where("trn_request_uid = ? and is_deleted = '0'", trnRequestUID).
+		Order("created_at").
+		Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	threads := []models.VmsTrnRequestComment{}
+	replies := map[string][]models.VmsTrnRequestComment{}
+	for _, comment := range comments {
+		comment.RoleOfCreater = GetRoleOfCreater(comment.CommentByRole)
+		comment.Replies = []models.VmsTrnRequestComment{}
+		if comment.ParentCommentUID != nil {
+			replies[*comment.ParentCommentUID] = append(replies[*comment.ParentCommentUID], comment)
+			continue
+		}
+		threads = append(threads, comment)
+	}
+	for i := range threads {
+		if threadReplies, ok := replies[threads[i].TrnRequestCommentUID]; ok {
+			threads[i].Replies = threadReplies
+			delete(replies, threads[i].TrnRequestCommentUID)
+		}
+	}
+	// replies of a deleted comment are shown as threads of their own
+	for _, comment := range comments {
+		if comment.ParentCommentUID == nil {
+			continue
+		}
+		for _, reply := range replies[*comment.ParentCommentUID] {
+			if reply.TrnRequestCommentUID == comment.TrnRequestCommentUID {
+				threads = append(threads, reply)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, threads)
+}
+
+// ListCommentParticipants godoc
+// @Summary List the participants of a request
+// @Description This endpoint returns the people who can be mentioned in the comments of a request and are notified of new comments.
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/comment/participants/{trn_request_uid} [get]
+func (h *CommentHandler) ListCommentParticipants(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	trnRequestUID := c.Param("trn_request_uid")
+	if h.GetRequestRole(user, trnRequestUID) == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Request not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	_, participants, err := funcs.GetRequestCommentParticipants(trnRequestUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, participants)
+}
+
+// CreateComment godoc
+// @Summary Comment on a request
+// @Description This endpoint adds a comment, or a reply when parent_comment_uid is set, to a request. mention_emp_ids must be participants of the request. Attachments are files uploaded through /api/upload. The other participants are notified.
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestCommentRequest true "VmsTrnRequestCommentRequest data"
+// @Router /api/comment/create-comment [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnRequestCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	role := h.GetRequestRole(user, request.TrnRequestUID)
+	if role == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Request not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	comment := models.VmsTrnRequestComment{
+		TrnRequestCommentUID:  uuid.New().String(),
+		TrnRequestUID:         request.TrnRequestUID,
+		CommentText:           request.CommentText,
+		CommentByEmpID:        user.EmpID,
+		CommentByFullName:     user.FullName,
+		CommentByPosition:     user.Position,
+		CommentByDeptSAPShort: user.DeptSAPShort,
+		CommentByRole:         role,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+		IsDeleted:             "0",
+	}
+	if request.ParentCommentUID != nil && *request.ParentCommentUID != "" {
+		var parent models.VmsTrnRequestComment
+		if err := config.DB.First(&parent, "trn_request_comment_uid = ? and trn_request_uid = ? and is_deleted = '0'", *request.ParentCommentUID, request.TrnRequestUID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Parent comment not found", "message": messages.ErrCommentNotFound.Error()})
+			return
+		}
+		// replies stay one level deep, a reply to a reply joins the same thread
+		comment.ParentCommentUID = &parent.TrnRequestCommentUID
+		if parent.ParentCommentUID != nil {
+			comment.ParentCommentUID = parent.ParentCommentUID
+		}
+	}
+
+	trnRequest, participants, err := funcs.GetRequestCommentParticipants(request.TrnRequestUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+	for _, empID := range request.MentionEmpIDs {
+		found := false
+		for _, participant := range participants {
+			if participant.EmpID == empID {
+				comment.Mentions = append(comment.Mentions, models.VmsTrnRequestCommentMention{
+					TrnRequestCommentMentionUID: uuid.New().String(),
+					TrnRequestCommentUID:        comment.TrnRequestCommentUID,
+					EmpID:                       participant.EmpID,
+					EmpName:                     participant.FullName,
+				})
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("emp_id %s is not a participant of the request", empID), "message": messages.ErrMentionInvalid.Error()})
+			return
+		}
+	}
+	for _, attachment := range request.Attachments {
+		comment.Attachments = append(comment.Attachments, models.VmsTrnRequestCommentAttachment{
+			TrnRequestCommentAttachmentUID: uuid.New().String(),
+			TrnRequestCommentUID:           comment.TrnRequestCommentUID,
+			FileName:                       attachment.FileName,
+			FileURL:                        attachment.FileURL,
+		})
+	}
+
+	if err := config.DB.Create(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	comment.RoleOfCreater = GetRoleOfCreater(comment.CommentByRole)
+
+	// the commenter joins the participants so later comments notify them too
+	participants = append(participants, models.VmsTrnRequestCommentParticipant{EmpID: user.EmpID, FullName: user.FullName, Role: role})
+	funcs.CreateRequestCommentNotification(trnRequest, participants, comment)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment created successfully", "result": comment})
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description This endpoint deletes a comment of the user. Replies to it are kept and listed on their own.
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_comment_uid path string true "TrnRequestCommentUID (trn_request_comment_uid)"
+// @Router /api/comment/delete-comment/{trn_request_comment_uid} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var comment models.VmsTrnRequestComment
+	if err := config.DB.First(&comment, "trn_request_comment_uid = ? and comment_by_emp_id = ? and is_deleted = '0'", c.Param("trn_request_comment_uid"), user.EmpID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found", "message": messages.ErrCommentNotFound.Error()})
+		return
+	}
+
+	if err := config.DB.Model(&comment).UpdateColumns(map[string]interface{}{
+		"is_deleted": "1",
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DelegationHandler struct {
+	Role string
+}
+
+func (h *DelegationHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
+	return query.Where("is_deleted = '0' and (delegator_emp_id = ? or delegate_emp_id = ?)", user.EmpID, user.EmpID)
+}
+
+// CreateDelegation godoc
+// @Summary Delegate approver roles to another employee for a period
+// @Description This endpoint lets an approver hand some of their approver roles to another employee while they are away. The delegate sees and can act on the approver's requests and receives their notifications until the end date.
+// @Tags Delegation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnDelegationCreate true "VmsTrnDelegationCreate data"
+// @Router /api/delegation/create-delegation [post]
+func (h *DelegationHandler) CreateDelegation(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnDelegationCreate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if !request.EndDatetime.After(request.StartDatetime.Time) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_datetime must be after start_datetime", "message": messages.ErrInvalidDate.Error()})
+		return
+	}
+	if request.DelegateEmpID == "" || request.DelegateEmpID == user.EmpID || len(request.DelegatedRoles) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "delegate_emp_id and delegated_roles are required", "message": messages.ErrDelegationInvalid.Error()})
+		return
+	}
+	// a role held through someone else's delegation cannot be passed on
+	ownRoles := funcs.GetOwnRoles(user)
+	for _, role := range request.DelegatedRoles {
+		if !funcs.Contains(funcs.DelegableRoles, role) || !funcs.Contains(ownRoles, role) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("role %s can not be delegated", role), "message": messages.ErrDelegationInvalid.Error()})
+			return
+		}
+	}
+
+	delegate := funcs.GetUserEmpInfo(request.DelegateEmpID)
+	if delegate.EmpID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Delegate not found", "message": messages.ErrDelegationInvalid.Error()})
+		return
+	}
+
+	delegation := models.VmsTrnDelegation{
+		TrnDelegationUID:       uuid.New().String(),
+		DelegatorEmpID:         user.EmpID,
+		DelegatorEmpName:       user.FullName,
+		DelegatorDeptNameShort: user.DeptSAPShort,
+		DelegateEmpID:          delegate.EmpID,
+		DelegateEmpName:        delegate.FullName,
+		DelegateDeptNameShort:  delegate.DeptSAPShort,
+		DelegatedRoles:         strings.Join(request.DelegatedRoles, ","),
+		StartDatetime:          request.StartDatetime,
+		EndDatetime:            request.EndDatetime,
+		Remark:                 request.Remark,
+		CreatedAt:              time.Now(),
+		CreatedBy:              user.EmpID,
+		UpdatedAt:              time.Now(),
+		UpdatedBy:              user.EmpID,
+		IsDeleted:              "0",
+	}
+	if err := config.DB.Create(&delegation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Delegation created successfully", "result": delegation})
+}
+
+// SearchDelegations godoc
+// @Summary List delegations given or received by the current user
+// @Description This endpoint returns the delegations the current user has given to others or received from others.
+// @Tags Delegation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param direction query string false "given or received (default: both)"
+// @Param is_active query string false "1 to return only delegations active now"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of records per page (default: 10)"
+// @Router /api/delegation/search-delegations [get]
+func (h *DelegationHandler) SearchDelegations(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := h.SetQueryRole(user, config.DB.Model(&models.VmsTrnDelegation{}))
+	switch c.Query("direction") {
+	case "given":
+		query = query.Where("delegator_emp_id = ?", user.EmpID)
+	case "received":
+		query = query.Where("delegate_emp_id = ?", user.EmpID)
+	}
+	if c.Query("is_active") == "1" {
+		now := time.Now()
+		query = query.Where("start_datetime <= ? and end_datetime > ?", now, now)
+	}
+
+	// Pagination
+	page := c.DefaultQuery("page", "1")
+	limit := c.DefaultQuery("limit", "10")
+	var pageInt, pageSizeInt int
+	fmt.Sscanf(page, "%d", &pageInt)
+	fmt.Sscanf(limit, "%d", &pageSizeInt)
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 10
+	}
+	offset := (pageInt - 1) * pageSizeInt
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	var delegations []models.VmsTrnDelegation
+	if err := query.Order("start_datetime desc").Offset(offset).Limit(pageSizeInt).Find(&delegations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pagination": gin.H{
+			"total":      total,
+			"page":       page,
+			"limit":      pageSizeInt,
+			"totalPages": (total + int64(pageSizeInt) - 1) / int64(pageSizeInt),
+		},
+		"requests": delegations,
+	})
+}
+
+// DeleteDelegation godoc
+// @Summary Cancel a delegation
+// @Description This endpoint cancels a delegation given by the current user. The delegate loses the roles and access immediately.
+// @Tags Delegation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_delegation_uid path string true "TrnDelegationUID (trn_delegation_uid)"
+// @Router /api/delegation/delete-delegation/{trn_delegation_uid} [delete]
+func (h *DelegationHandler) DeleteDelegation(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var delegation models.VmsTrnDelegation
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&delegation, "trn_delegation_uid = ? and delegator_emp_id = ?", c.Param("trn_delegation_uid"), user.EmpID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delegation not found", "message": messages.ErrDelegationNotFound.Error()})
+		return
+	}
+
+	if err := config.DB.Model(&delegation).UpdateColumns(map[string]interface{}{
+		"is_deleted": "1",
+		"updated_by": user.EmpID,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
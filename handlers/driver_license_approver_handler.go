@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -30,7 +31,7 @@ var MenuNameMapLicenseApprover = map[string]string{
 }
 
 func (h *DriverLicenseApproverHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
-	return query.Where("approved_request_emp_id = ?", user.EmpID)
+	return query.Where("approved_request_emp_id in (?)", funcs.GetApprovalEmpIDs(user, "license-approval"))
 }
 
 func (h *DriverLicenseApproverHandler) SetQueryRoleDept(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
@@ -488,7 +489,7 @@ func (h *DriverLicenseApproverHandler) UpdateDriverLicenseAnnualRejected(c *gin.
 	if c.IsAborted() {
 		return
 	}
-	var request, driverLicenseAnnual models.VmsDriverLicenseAnnualRejected
+	var request models.VmsDriverLicenseAnnualRejected
 	var result struct {
 		models.VmsDriverLicenseAnnualRejected
 		models.VmsTrnRequestAnnualDriverNo
@@ -497,11 +498,29 @@ func (h *DriverLicenseApproverHandler) UpdateDriverLicenseAnnualRejected(c *gin.
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
 		return
 	}
+
+	if err := h.updateDriverLicenseAnnualRejected(user, &request); err != nil {
+		if errors.Is(err, messages.ErrAnnualCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Driver license annual can not update", "message": messages.ErrAnnualCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	if err := config.DB.First(&result, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license annual record not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *DriverLicenseApproverHandler) updateDriverLicenseAnnualRejected(user *models.AuthenUserEmp, request *models.VmsDriverLicenseAnnualRejected) error {
+	var driverLicenseAnnual models.VmsDriverLicenseAnnualRejected
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&driverLicenseAnnual, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Driver license annual can not update", "message": messages.ErrAnnualCannotUpdate.Error()})
-		return
+		return messages.ErrAnnualCannotUpdate
 	}
 	request.RefRequestAnnualDriverStatusCode = "21"
 	request.UpdatedAt = time.Now()
@@ -515,17 +534,11 @@ func (h *DriverLicenseApproverHandler) UpdateDriverLicenseAnnualRejected(c *gin.
 	request.RejectedRequestDeptSAPFull = empUser.DeptSAPFull
 	request.RejectedRequestDatetime = models.TimeWithZone{Time: time.Now()}
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update: %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-
-	if err := config.DB.First(&result, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license annual record not found", "message": messages.ErrNotfound.Error()})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update: %v", err)
 	}
 	funcs.CreateRequestAnnualLicenseNotification(request.TrnRequestAnnualDriverUID)
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	return nil
 }
 
 // UpdateDriverLicenseAnnualApproved godoc
@@ -543,7 +556,7 @@ func (h *DriverLicenseApproverHandler) UpdateDriverLicenseAnnualApproved(c *gin.
 	if c.IsAborted() {
 		return
 	}
-	var request, driverLicenseAnnual models.VmsDriverLicenseAnnualApproved
+	var request models.VmsDriverLicenseAnnualApproved
 	var result struct {
 		models.VmsDriverLicenseAnnualApproved
 		models.VmsTrnRequestAnnualDriverNo
@@ -553,11 +566,28 @@ func (h *DriverLicenseApproverHandler) UpdateDriverLicenseAnnualApproved(c *gin.
 		return
 	}
 
+	if err := h.updateDriverLicenseAnnualApproved(user, &request); err != nil {
+		if errors.Is(err, messages.ErrAnnualCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Driver license annual can not update", "message": messages.ErrAnnualCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	if err := config.DB.First(&result, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license annual record not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *DriverLicenseApproverHandler) updateDriverLicenseAnnualApproved(user *models.AuthenUserEmp, request *models.VmsDriverLicenseAnnualApproved) error {
+	var driverLicenseAnnual models.VmsDriverLicenseAnnualApproved
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&driverLicenseAnnual, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Driver license annual can not update", "message": messages.ErrAnnualCannotUpdate.Error()})
-		return
+		return messages.ErrAnnualCannotUpdate
 	}
 	request.RefRequestAnnualDriverStatusCode = "30"
 	request.UpdatedAt = time.Now()
@@ -580,15 +610,71 @@ func (h *DriverLicenseApproverHandler) UpdateDriverLicenseAnnualApproved(c *gin.
 		request.RequestExpireDate = models.TimeWithZone{Time: annualYearEnd}
 	}
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update: %v", err), "message": messages.ErrInternalServer.Error()})
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update: %v", err)
+	}
+	funcs.CreateRequestAnnualLicenseNotification(request.TrnRequestAnnualDriverUID)
+	return nil
+}
+
+// UpdateBulkDriverLicenseAnnualRejected godoc
+// @Summary Reject several driver license annual requests at once
+// @Description This endpoint rejects each listed driver license annual request independently and reports the result of every item. The remark is used as the reason for all of them.
+// @Tags Driver-license-approver
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsDriverLicenseAnnualBulk true "VmsDriverLicenseAnnualBulk data"
+// @Router /api/driver-license-approver/update-bulk-license-annual-rejected [put]
+func (h *DriverLicenseApproverHandler) UpdateBulkDriverLicenseAnnualRejected(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsDriverLicenseAnnualBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestAnnualDriverUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_annual_driver_uids is required", "message": messages.ErrInvalidRequest.Error()})
 		return
 	}
 
-	if err := config.DB.First(&result, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license annual record not found", "message": messages.ErrNotfound.Error()})
+	results, success := funcs.RunBulkAction(request.TrnRequestAnnualDriverUIDs, func(trnRequestAnnualDriverUID string) error {
+		return h.updateDriverLicenseAnnualRejected(user, &models.VmsDriverLicenseAnnualRejected{TrnRequestAnnualDriverUID: trnRequestAnnualDriverUID, RejectedRequestReason: request.Remark})
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
+}
+
+// UpdateBulkDriverLicenseAnnualApproved godoc
+// @Summary Approve several driver license annual requests at once
+// @Description This endpoint approves each listed driver license annual request independently and reports the result of every item.
+// @Tags Driver-license-approver
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsDriverLicenseAnnualBulk true "VmsDriverLicenseAnnualBulk data"
+// @Router /api/driver-license-approver/update-bulk-license-annual-approved [put]
+func (h *DriverLicenseApproverHandler) UpdateBulkDriverLicenseAnnualApproved(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
 		return
 	}
-	funcs.CreateRequestAnnualLicenseNotification(request.TrnRequestAnnualDriverUID)
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	var request models.VmsDriverLicenseAnnualBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestAnnualDriverUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_annual_driver_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestAnnualDriverUIDs, func(trnRequestAnnualDriverUID string) error {
+		return h.updateDriverLicenseAnnualApproved(user, &models.VmsDriverLicenseAnnualApproved{TrnRequestAnnualDriverUID: trnRequestAnnualDriverUID})
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
 }
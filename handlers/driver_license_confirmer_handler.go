@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -28,7 +29,7 @@ var LicenseStatusNameMapConfirmer = map[string]string{
 }
 
 func (h *DriverLicenseConfirmerHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
-	return query.Where("confirmed_request_emp_id = ?", user.EmpID)
+	return query.Where("confirmed_request_emp_id in (?)", funcs.GetApprovalEmpIDs(user, "level1-approval"))
 }
 
 func (h *DriverLicenseConfirmerHandler) SetQueryRoleDept(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
@@ -440,7 +441,7 @@ func (h *DriverLicenseConfirmerHandler) UpdateDriverLicenseAnnualRejected(c *gin
 	if c.IsAborted() {
 		return
 	}
-	var request, driverLicenseAnnual models.VmsDriverLicenseAnnualRejected
+	var request models.VmsDriverLicenseAnnualRejected
 	var result struct {
 		models.VmsDriverLicenseAnnualRejected
 		models.VmsTrnRequestAnnualDriverNo
@@ -449,11 +450,29 @@ func (h *DriverLicenseConfirmerHandler) UpdateDriverLicenseAnnualRejected(c *gin
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
 		return
 	}
+
+	if err := h.updateDriverLicenseAnnualRejected(user, &request); err != nil {
+		if errors.Is(err, messages.ErrAnnualCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Driver license annual can not update", "message": messages.ErrAnnualCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	if err := config.DB.First(&result, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license annual record not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *DriverLicenseConfirmerHandler) updateDriverLicenseAnnualRejected(user *models.AuthenUserEmp, request *models.VmsDriverLicenseAnnualRejected) error {
+	var driverLicenseAnnual models.VmsDriverLicenseAnnualRejected
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&driverLicenseAnnual, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Driver license annual can not update", "message": messages.ErrAnnualCannotUpdate.Error()})
-		return
+		return messages.ErrAnnualCannotUpdate
 	}
 	request.RefRequestAnnualDriverStatusCode = "11"
 	request.UpdatedAt = time.Now()
@@ -467,17 +486,11 @@ func (h *DriverLicenseConfirmerHandler) UpdateDriverLicenseAnnualRejected(c *gin
 	request.RejectedRequestDeptSAPFull = empUser.DeptSAPFull
 	request.RejectedRequestDatetime = models.TimeWithZone{Time: time.Now()}
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update: %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-
-	if err := config.DB.First(&result, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license annual record not found", "message": messages.ErrNotfound.Error()})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update: %v", err)
 	}
 	funcs.CreateRequestAnnualLicenseNotification(request.TrnRequestAnnualDriverUID)
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	return nil
 }
 
 // UpdateDriverLicenseAnnualConfirmed godoc
@@ -495,7 +508,7 @@ func (h *DriverLicenseConfirmerHandler) UpdateDriverLicenseAnnualConfirmed(c *gi
 	if c.IsAborted() {
 		return
 	}
-	var request, driverLicenseAnnual models.VmsDriverLicenseAnnualConfirmed
+	var request models.VmsDriverLicenseAnnualConfirmed
 	var result struct {
 		models.VmsDriverLicenseAnnualConfirmed
 		models.VmsTrnRequestAnnualDriverNo
@@ -505,11 +518,28 @@ func (h *DriverLicenseConfirmerHandler) UpdateDriverLicenseAnnualConfirmed(c *gi
 		return
 	}
 
+	if err := h.updateDriverLicenseAnnualConfirmed(user, &request); err != nil {
+		if errors.Is(err, messages.ErrAnnualCannotUpdate) {
+			c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Driver license annual can not update", "message": messages.ErrAnnualCannotUpdate.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	if err := config.DB.First(&result, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license annual record not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+}
+
+func (h *DriverLicenseConfirmerHandler) updateDriverLicenseAnnualConfirmed(user *models.AuthenUserEmp, request *models.VmsDriverLicenseAnnualConfirmed) error {
+	var driverLicenseAnnual models.VmsDriverLicenseAnnualConfirmed
 	query := h.SetQueryRole(user, config.DB)
 	query = h.SetQueryStatusCanUpdate(query)
 	if err := query.First(&driverLicenseAnnual, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Driver license annual can not update", "message": messages.ErrAnnualCannotUpdate.Error()})
-		return
+		return messages.ErrAnnualCannotUpdate
 	}
 	request.RefRequestAnnualDriverStatusCode = "20"
 	request.UpdatedAt = time.Now()
@@ -523,17 +553,11 @@ func (h *DriverLicenseConfirmerHandler) UpdateDriverLicenseAnnualConfirmed(c *gi
 	request.ConfirmedRequestDeptSAPFull = empUser.DeptSAPFull
 	request.ConfirmedRequestDatetime = models.TimeWithZone{Time: time.Now()}
 
-	if err := config.DB.Save(&request).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update: %v", err), "message": messages.ErrInternalServer.Error()})
-		return
-	}
-
-	if err := config.DB.First(&result, "trn_request_annual_driver_uid = ? AND is_deleted = ?", request.TrnRequestAnnualDriverUID, "0").Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license annual record not found", "message": messages.ErrNotfound.Error()})
-		return
+	if err := config.DB.Save(request).Error; err != nil {
+		return fmt.Errorf("failed to update: %v", err)
 	}
 	funcs.CreateRequestAnnualLicenseNotification(request.TrnRequestAnnualDriverUID)
-	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
+	return nil
 }
 
 // UpdateDriverLicenseAnnualApprover godoc
@@ -590,3 +614,65 @@ func (h *DriverLicenseConfirmerHandler) UpdateDriverLicenseAnnualApprover(c *gin
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
+
+// UpdateBulkDriverLicenseAnnualRejected godoc
+// @Summary Reject several driver license annual requests at once
+// @Description This endpoint rejects each listed driver license annual request independently and reports the result of every item. The remark is used as the reason for all of them.
+// @Tags Driver-license-confirmer
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsDriverLicenseAnnualBulk true "VmsDriverLicenseAnnualBulk data"
+// @Router /api/driver-license-confirmer/update-bulk-license-annual-rejected [put]
+func (h *DriverLicenseConfirmerHandler) UpdateBulkDriverLicenseAnnualRejected(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsDriverLicenseAnnualBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestAnnualDriverUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_annual_driver_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestAnnualDriverUIDs, func(trnRequestAnnualDriverUID string) error {
+		return h.updateDriverLicenseAnnualRejected(user, &models.VmsDriverLicenseAnnualRejected{TrnRequestAnnualDriverUID: trnRequestAnnualDriverUID, RejectedRequestReason: request.Remark})
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
+}
+
+// UpdateBulkDriverLicenseAnnualConfirmed godoc
+// @Summary Confirm several driver license annual requests at once
+// @Description This endpoint confirms each listed driver license annual request independently and reports the result of every item.
+// @Tags Driver-license-confirmer
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsDriverLicenseAnnualBulk true "VmsDriverLicenseAnnualBulk data"
+// @Router /api/driver-license-confirmer/update-bulk-license-annual-confirmed [put]
+func (h *DriverLicenseConfirmerHandler) UpdateBulkDriverLicenseAnnualConfirmed(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsDriverLicenseAnnualBulk
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if len(request.TrnRequestAnnualDriverUIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "trn_request_annual_driver_uids is required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	results, success := funcs.RunBulkAction(request.TrnRequestAnnualDriverUIDs, func(trnRequestAnnualDriverUID string) error {
+		return h.updateDriverLicenseAnnualConfirmed(user, &models.VmsDriverLicenseAnnualConfirmed{TrnRequestAnnualDriverUID: trnRequestAnnualDriverUID})
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "total": len(results), "success": success, "result": results})
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,6 +10,7 @@ import (
 	"vms_plus_be/funcs"
 	"vms_plus_be/messages"
 	"vms_plus_be/models"
+	"vms_plus_be/permission"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -30,16 +30,7 @@ func (h *DriverManagementHandler) SetQueryRole(user *models.AuthenUserEmp, query
 }
 
 func (h *DriverManagementHandler) SetQueryRoleDept(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
-	if slices.Contains(user.Roles, "admin-super") {
-		return query
-	}
-	if slices.Contains(user.Roles, "admin-region") {
-		return query.Where("d.bureau_ba like ?", user.BusinessArea[:1]+"%")
-	}
-	if slices.Contains(user.Roles, "admin-department") {
-		return query.Where("d.bureau_dept_sap = ?", user.BureauDeptSap)
-	}
-	return nil
+	return permission.SetQueryDepartmentScope(user, query, "d")
 }
 
 func (h *DriverManagementHandler) CheckRoleDriverOwner(user *models.AuthenUserEmp, masDriverUID string) bool {
@@ -281,6 +272,15 @@ func (h *DriverManagementHandler) CreateDriver(c *gin.Context) {
 	funcs.UpdateBusinessArea(driver.MasDriverUID)
 	funcs.CheckDriverIsActive(driver.MasDriverUID)
 
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, nil)
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver_license", "mas_driver_license_uid", driverLicense.MasDriverLicenseUID, nil)
+	if driverCertificate.DriverCertificateNo != "" {
+		funcs.AuditMasterDataRow(c, user, "vms_mas_driver_certificate", "mas_driver_certificate_uid", driverCertificate.MasDriverCertificateUID, nil)
+	}
+	for _, document := range driverDocuments {
+		funcs.AuditMasterDataRow(c, user, "vms_mas_driver_document", "mas_driver_document_uid", document.MasDriverDocumentUID, nil)
+	}
+
 	driver.DriverCertificate = driverCertificate
 	driver.DriverLicense = driverLicense
 	driver.DriverDocuments = driverDocuments
@@ -388,6 +388,7 @@ func (h *DriverManagementHandler) UpdateDriverDetail(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	before := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", driver.MasDriverUID)
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 
@@ -401,6 +402,7 @@ func (h *DriverManagementHandler) UpdateDriverDetail(c *gin.Context) {
 		return
 
 	}
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, before)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -439,6 +441,7 @@ func (h *DriverManagementHandler) UpdateDriverContract(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	before := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", driver.MasDriverUID)
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 
@@ -454,6 +457,7 @@ func (h *DriverManagementHandler) UpdateDriverContract(c *gin.Context) {
 	}
 	funcs.UpdateBusinessArea(driver.MasDriverUID)
 	funcs.CheckDriverIsActive(driver.MasDriverUID)
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, before)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -497,6 +501,8 @@ func (h *DriverManagementHandler) UpdateDriverLicense(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver license not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	licenseBefore := funcs.GetMasterDataSnapshot("vms_mas_driver_license", "mas_driver_license_uid", driverLicense.MasDriverLicenseUID)
+	certificates := funcs.GetMasterDataSnapshots("vms_mas_driver_certificate", "mas_driver_certificate_uid", "mas_driver_uid", driver.MasDriverUID)
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 	request.MasDriverUID = driver.MasDriverUID
@@ -567,6 +573,8 @@ func (h *DriverManagementHandler) UpdateDriverLicense(c *gin.Context) {
 	result.DriverName = driver.DriverName
 	result.DriverNickname = driver.DriverNickname
 	result.DriverCertificate = request.DriverCertificate
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver_license", "mas_driver_license_uid", driverLicense.MasDriverLicenseUID, licenseBefore)
+	funcs.AuditMasterDataRows(c, user, "vms_mas_driver_certificate", "mas_driver_certificate_uid", "mas_driver_uid", driver.MasDriverUID, certificates)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -606,6 +614,8 @@ func (h *DriverManagementHandler) UpdateDriverDocuments(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	documents := funcs.GetMasterDataSnapshots("vms_mas_driver_document", "mas_driver_document_uid", "mas_driver_uid", driver.MasDriverUID)
+	licenses := funcs.GetMasterDataSnapshots("vms_mas_driver_license", "mas_driver_license_uid", "mas_driver_uid", driver.MasDriverUID)
 
 	if err := config.DB.Where("mas_driver_uid = ? AND is_deleted = ?", request.MasDriverUID, "0").
 		Delete(&models.VmsMasDriverDocument{}).Error; err != nil {
@@ -653,6 +663,9 @@ func (h *DriverManagementHandler) UpdateDriverDocuments(c *gin.Context) {
 		return
 	}
 
+	funcs.AuditMasterDataRows(c, user, "vms_mas_driver_document", "mas_driver_document_uid", "mas_driver_uid", driver.MasDriverUID, documents)
+	funcs.AuditMasterDataRows(c, user, "vms_mas_driver_license", "mas_driver_license_uid", "mas_driver_uid", driver.MasDriverUID, licenses)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -691,6 +704,7 @@ func (h *DriverManagementHandler) UpdateDriverLeaveStatus(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	before := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", driver.MasDriverUID)
 
 	request.TrnDriverLeaveUID = uuid.NewString()
 	request.CreatedAt = time.Now()
@@ -714,6 +728,9 @@ func (h *DriverManagementHandler) UpdateDriverLeaveStatus(c *gin.Context) {
 	result.DriverName = driver.DriverName
 	result.DriverNickname = driver.DriverNickname
 
+	funcs.AuditMasterDataRow(c, user, "vms_trn_driver_leave", "trn_driver_leave_uid", request.TrnDriverLeaveUID, nil)
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, before)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -751,6 +768,7 @@ func (h *DriverManagementHandler) UpdateDriverIsActive(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	before := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", driver.MasDriverUID)
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 
@@ -766,6 +784,7 @@ func (h *DriverManagementHandler) UpdateDriverIsActive(c *gin.Context) {
 	result.DriverID = driver.DriverID
 	result.DriverName = driver.DriverName
 	result.DriverNickname = driver.DriverNickname
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, before)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -798,6 +817,7 @@ func (h *DriverManagementHandler) DeleteDriver(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	before := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", driver.MasDriverUID)
 
 	if err := config.DB.Model(&driver).UpdateColumns(map[string]interface{}{
 		"is_active":  "0",
@@ -808,6 +828,8 @@ func (h *DriverManagementHandler) DeleteDriver(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete", "message": messages.ErrInternalServer.Error()})
 	}
 
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, before)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
 }
 
@@ -846,6 +868,8 @@ func (h *DriverManagementHandler) UpdateDriverLayoffStatus(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	before := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", driver.MasDriverUID)
+	replacedBefore := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", request.ReplacedMasDriverUID)
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 	request.RefDriverStatusCode = 4
@@ -876,6 +900,10 @@ func (h *DriverManagementHandler) UpdateDriverLayoffStatus(c *gin.Context) {
 	result.DriverID = driver.DriverID
 	result.DriverName = driver.DriverName
 	result.DriverNickname = driver.DriverNickname
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, before)
+	if request.ReplacedMasDriverUID != "" {
+		funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", request.ReplacedMasDriverUID, replacedBefore)
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -914,6 +942,8 @@ func (h *DriverManagementHandler) UpdateDriverResignStatus(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	before := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", driver.MasDriverUID)
+	replacedBefore := funcs.GetMasterDataSnapshot("vms_mas_driver", "mas_driver_uid", request.ReplacedMasDriverUID)
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 	request.RefDriverStatusCode = 3
@@ -939,6 +969,10 @@ func (h *DriverManagementHandler) UpdateDriverResignStatus(c *gin.Context) {
 	result.DriverID = driver.DriverID
 	result.DriverName = driver.DriverName
 	result.DriverNickname = driver.DriverNickname
+	funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, before)
+	if request.ReplacedMasDriverUID != "" {
+		funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", request.ReplacedMasDriverUID, replacedBefore)
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -1265,6 +1299,8 @@ func (h *DriverManagementHandler) ImportDriver(c *gin.Context) {
 		for _, driver := range drivers {
 			funcs.UpdateBusinessArea(driver.MasDriverUID)
 			funcs.CheckDriverIsActive(driver.MasDriverUID)
+			funcs.AuditMasterDataRow(c, user, "vms_mas_driver", "mas_driver_uid", driver.MasDriverUID, nil)
+			funcs.AuditMasterDataRow(c, user, "vms_mas_driver_license", "mas_driver_license_uid", driver.DriverLicense.MasDriverLicenseUID, nil)
 		}
 	}
 
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+	"vms_plus_be/userhub"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImpersonationHandler struct {
+	Role string
+}
+
+// StartImpersonation godoc
+// @Summary Start impersonating an employee
+// @Description This endpoint lets support staff see the system as an employee does. It returns a token carrying both identities that expires after duration_minutes (default 30, at most IMPERSONATION_MAX_MINUTES) and has no refresh token. The token is read-only: every call other than GET is refused except stopping the impersonation. Admin and support users cannot be impersonated. Every call made with the token is written to the audit log under entity_type impersonation.
+// @Tags Impersonation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.ImpersonationRequest true "ImpersonationRequest data"
+// @Success 201 {object} models.ImpersonationResponse
+// @Router /api/impersonation/start [post]
+func (h *ImpersonationHandler) StartImpersonation(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	if user.IsImpersonated {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot impersonate while impersonating", "message": messages.ErrImpersonateBlocked.Error()})
+		return
+	}
+
+	var request models.ImpersonationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if request.EmpID == user.EmpID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot impersonate yourself", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+	if request.DurationMinutes <= 0 {
+		request.DurationMinutes = 30
+	}
+	if request.DurationMinutes > config.AppConfig.ImpersonationMaxMinutes {
+		request.DurationMinutes = config.AppConfig.ImpersonationMaxMinutes
+	}
+
+	target, err := userhub.GetUserInfo(request.EmpID)
+	if err != nil || target.EmpID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	target.IsEmployee = true
+	if !funcs.IsImpersonationTargetAllowed(target) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot impersonate an admin or support user", "message": messages.ErrImpersonateTarget.Error()})
+		return
+	}
+
+	result, err := funcs.StartImpersonation(c, user, target, request.Reason, time.Duration(request.DurationMinutes)*time.Minute)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// StopImpersonation godoc
+// @Summary Stop impersonating
+// @Description This endpoint revokes the impersonation token the call is made with.
+// @Tags Impersonation
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Router /api/impersonation/stop [post]
+func (h *ImpersonationHandler) StopImpersonation(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	if !user.IsImpersonated {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not impersonating", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	if err := funcs.StopImpersonation(c, user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Impersonation stopped successfully"})
+}
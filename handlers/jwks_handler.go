@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+	"vms_plus_be/funcs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+}
+
+// GetJWKS godoc
+// @Summary Retrieve the public keys VMS tokens are signed with
+// @Description This endpoint publishes the JSON Web Key Set other services verify VMS access tokens with. Pick the key by the kid header of the token. The set is empty while tokens are signed with the shared secret.
+// @Tags Login
+// @Produce json
+// @Success 200 {object} models.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, funcs.GetJWKS())
+}
@@ -35,6 +35,7 @@ func GetRoleOfCreater(role string) string {
 
 // GetLogRequest godoc
 // @Summary Get log requests by trnRequestUID
+// @Description This endpoint returns the action log of a request, paged, and every edit of the request with the value of each changed field before and after, newest first.
 // @Tags Log
 // @Accept json
 // @Produce json
@@ -68,11 +69,24 @@ func (h *LogHandler) GetLogRequest(c *gin.Context) {
 		logRequests[i].RoleOfCreater = GetRoleOfCreater(logRequests[i].ActionByRole)
 	}
 
+	changes := []models.VmsLogRequestChange{}
+	if err := config.DB.Preload("Fields").
+		Where("trn_request_uid = ? AND is_deleted = ?", trnRequestUID, "0").
+		Order("change_datetime desc").
+		Find(&changes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range changes {
+		changes[i].RoleOfCreater = GetRoleOfCreater(changes[i].ActionByRole)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"total":      total,
 		"page":       page,
 		"limit":      limit,
 		"totalPages": (total + int64(limit) - 1) / int64(limit), // Calculate total pages
 		"logs":       logRequests,
+		"changes":    changes,
 	})
 }
@@ -1,14 +1,13 @@
 package handlers
 
 import (
-	"bytes"
 	"encoding/base64"
 	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 	"vms_plus_be/config"
@@ -18,7 +17,6 @@ import (
 	"vms_plus_be/userhub"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
@@ -139,22 +137,13 @@ func (h *LoginHandler) AuthenKeyCloak(c *gin.Context) {
 				IsEmployee:    true,
 			}
 			user.LoginBy = "keycloak"
-			newAccessToken, err := funcs.GenerateJWT(user, "access", time.Duration(config.AppConfig.JwtAccessTokenTime)*time.Minute)
+			tokens, err := funcs.CreateSession(c, user)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating access token"})
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
 				return
 			}
 
-			newRefreshToken, err := funcs.GenerateRefreshJWT(user, "refresh", time.Duration(config.AppConfig.JwtRefreshTokenTime)*time.Hour)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating refresh token"})
-				return
-			}
-
-			c.JSON(http.StatusOK, models.Login_Response{
-				AccessToken:  newAccessToken,
-				RefreshToken: newRefreshToken,
-			})
+			c.JSON(http.StatusOK, tokens)
 			return
 		}
 	}
@@ -299,22 +288,13 @@ func (h *LoginHandler) AuthenThaiID(c *gin.Context) {
 			}
 		}
 		user.LoginBy = "thaiid"
-		newAccessToken, err := funcs.GenerateJWT(user, "access", time.Duration(config.AppConfig.JwtAccessTokenTime)*time.Minute)
+		tokens, err := funcs.CreateSession(c, user)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating access token"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
 			return
 		}
 
-		newRefreshToken, err := funcs.GenerateRefreshJWT(user, "refresh", time.Duration(config.AppConfig.JwtRefreshTokenTime)*time.Hour)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating refresh token"})
-			return
-		}
-
-		c.JSON(http.StatusOK, models.Login_Response{
-			AccessToken:  newAccessToken,
-			RefreshToken: newRefreshToken,
-		})
+		c.JSON(http.StatusOK, tokens)
 		return
 
 	}
@@ -353,9 +333,16 @@ func GetUserInfo(accessToken string) (models.KeyCloak_UserInfo, error) {
 	return userInfo, nil
 }
 
+// abortOTPLocked answers 429 while a phone number or an IP address is locked out of OTP login
+func abortOTPLocked(c *gin.Context, lockedUntil time.Time) {
+	c.Header("Retry-After", strconv.Itoa(int(time.Until(lockedUntil).Seconds())+1))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many OTP attempts", "message": messages.ErrOTPLocked.Error(), "locked_until": lockedUntil})
+	c.Abort()
+}
+
 // RequestOTP godoc
 // @Summary Request One-Time Password (OTP) for user authentication
-// @Description This endpoint allows a user to request a One-Time Password (OTP) for authentication purposes.
+// @Description This endpoint allows a user to request a One-Time Password (OTP) for authentication purposes. Requests are limited per phone number and per IP address; over the limit the phone number or IP address is locked out with a cooldown that doubles on every lockout.
 // @Tags Login
 // @Accept json
 // @Produce json
@@ -369,6 +356,30 @@ func (h *LoginHandler) RequestOTP(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid JSON input", "message": messages.ErrInvalidJSONInput.Error()})
 		return
 	}
+	phoneKey := funcs.GetOTPPhoneKey(req.Phone)
+	ipKey := funcs.GetOTPIPKey(c.ClientIP())
+	lockedUntil, err := funcs.CheckOTPLockout(phoneKey, ipKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrTryAgain.Error()})
+		return
+	}
+	if !lockedUntil.IsZero() {
+		abortOTPLocked(c, lockedUntil)
+		return
+	}
+	lockedUntil, err = funcs.HitOTPLimits(c, time.Duration(config.AppConfig.OtpRequestWindowMinutes)*time.Minute,
+		funcs.OTPLimit{Key: phoneKey, Limit: config.AppConfig.OtpRequestLimitPhone},
+		funcs.OTPLimit{Key: ipKey, Limit: config.AppConfig.OtpRequestLimitIP},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrTryAgain.Error()})
+		return
+	}
+	if !lockedUntil.IsZero() {
+		abortOTPLocked(c, lockedUntil)
+		return
+	}
+
 	ok, err := userhub.CheckPhoneNumber(req.Phone)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking phone number", "message": err.Error()})
@@ -385,7 +396,7 @@ func (h *LoginHandler) RequestOTP(c *gin.Context) {
 
 	refCode := funcs.RandomRefCode(4)
 	expiry := time.Minute * time.Duration(config.AppConfig.OtpExpired)
-	otpID, otpErr := SendOTP(req.Phone, refCode, expiry)
+	otpID, otpErr := funcs.GetOTPProvider().SendOTP(req.Phone, refCode, expiry)
 	if otpErr != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "OTP sending failed" + otpErr.Error(), "message": messages.ErrTryAgain.Error()})
 		return
@@ -411,60 +422,9 @@ func (h *LoginHandler) RequestOTP(c *gin.Context) {
 	})
 }
 
-func SendOTP(phone string, refCode string, expiry time.Duration) (string, error) {
-
-	//fmt.Printf("Sending OTP to phone %s\n", phone) // Simulate sending
-
-	soapEndpoint := "https://crm.pea.co.th/Modules/SMS/WebServices/SmsGatewayService.asmx"
-	soapAction := "http://crm.pea.co.th/modules/sms/smsgatewayservice/RequestOtpBySmsService"
-
-	soapRequest := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
-<soap:Envelope xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" 
-               xmlns:xsd="http://www.w3.org/2001/XMLSchema" 
-               xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
-  <soap:Body>
-    <RequestOtpBySmsService xmlns="http://crm.pea.co.th/modules/sms/smsgatewayservice/">
-      <authenKey>545653AA-19E0-41BB-B89F-8485559CD0A7</authenKey>
-      <smsServiceId>ae9d5c1b-7ed8-444e-8bb0-707ab7e3e68a</smsServiceId>
-      <telephoneNumber>%s</telephoneNumber>
-      <messageTemplate>หมายเลข OTP ของท่านคือ **pw** (รหัสอ้างอิง %s) โปรดป้อน ภายใน %d นาที</messageTemplate>
-      <timeoutSecond>%d</timeoutSecond>
-    </RequestOtpBySmsService>
-  </soap:Body>
-</soap:Envelope>`, phone, refCode, int(expiry.Minutes()), int(expiry.Seconds()))
-
-	req, err := http.NewRequest("POST", soapEndpoint, bytes.NewBuffer([]byte(soapRequest)))
-	if err != nil {
-		return "", fmt.Errorf("error creating request: %v", err)
-	}
-
-	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
-	req.Header.Set("SOAPAction", soapAction)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error sending request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("error reading response: %v", err)
-	}
-
-	var envelope models.Envelope
-	err = xml.Unmarshal(body, &envelope)
-	if err != nil {
-		return "", fmt.Errorf("error parsing SOAP response: %v", err)
-	}
-	// Return the extracted result
-	return envelope.Body.RequestOtpBySmsServiceResponse.RequestOtpBySmsServiceResult, nil
-}
-
 // VerifyOTP godoc
 // @Summary Verify One-Time Password (OTP) for user authentication
-// @Description This endpoint allows a user to verify the One-Time Password (OTP) they received.
+// @Description This endpoint allows a user to verify the One-Time Password (OTP) they received. An otpId accepts a limited number of wrong OTPs, after which it is void and the phone number is locked out.
 // @Tags Login
 // @Accept json
 // @Produce json
@@ -491,17 +451,48 @@ func (h *LoginHandler) VerifyOTP(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "รหัส OTP หมดอายุแล้ว กรุณากด 'ขอรหัส OTP ใหม่' เพื่อกรอกอีกครั้ง"})
 		return
 	}
+	phoneKey := funcs.GetOTPPhoneKey(otpRequest.PhoneNo)
+	lockedUntil, err := funcs.CheckOTPLockout(phoneKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrTryAgain.Error()})
+		return
+	}
+	if !lockedUntil.IsZero() {
+		abortOTPLocked(c, lockedUntil)
+		return
+	}
 
 	// Check OTP
-	result, err := CheckOTP(req.OtpId, req.OTP)
+	result, err := funcs.GetOTPProvider().CheckOTP(req.OtpId, req.OTP)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrTryAgain.Error()})
 		return
 	}
 	if !result {
+		exhausted, err := funcs.CountOTPFailure(otpRequest.OTPID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrTryAgain.Error()})
+			return
+		}
+		if exhausted {
+			otpRequest.Status = "locked"
+			if err := config.DB.Save(&otpRequest).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update OTP status: %v", err), "message": messages.ErrTryAgain.Error()})
+				return
+			}
+			lockedUntil, err := funcs.LockOTPKey(c, phoneKey, "verify attempts of otpId "+otpRequest.OTPID+" used up")
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrTryAgain.Error()})
+				return
+			}
+			abortOTPLocked(c, lockedUntil)
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid OTP", "message": "กรอก OTP ไม่ถูกต้อง ตรวจสอบให้แน่ใจว่าคุณใช้รหัส OTP ที่ได้รับล่าสุด"})
 		return
 	}
+	funcs.ResetOTPLimit(funcs.GetOTPVerifyKey(otpRequest.OTPID))
+	funcs.ResetOTPLimit(phoneKey)
 
 	otpRequest.Status = "verified"
 	if err := config.DB.Save(&otpRequest).Error; err != nil {
@@ -565,78 +556,18 @@ func (h *LoginHandler) VerifyOTP(c *gin.Context) {
 	}
 
 	// Generate JWT tokens
-	accessToken, err := funcs.GenerateJWT(user, "access", time.Duration(config.AppConfig.JwtAccessTokenTime)*time.Minute)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating access token", "message": messages.ErrTryAgain.Error()})
-		return
-	}
-
-	refreshToken, err := funcs.GenerateRefreshJWT(user, "refresh", time.Duration(config.AppConfig.JwtRefreshTokenTime)*time.Hour)
+	tokens, err := funcs.CreateSession(c, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating refresh token", "message": messages.ErrTryAgain.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token", "message": messages.ErrTryAgain.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.Login_Response{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	})
-}
-
-func CheckOTP(otpId string, otp string) (bool, error) {
-	if otp == "123456" {
-		return true, nil
-	}
-	// Define the SOAP request body
-	soapRequest := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
-<soap:Envelope xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" 
-               xmlns:xsd="http://www.w3.org/2001/XMLSchema" 
-               xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
-  <soap:Body>
-    <VerifyOtp xmlns="http://crm.pea.co.th/modules/sms/smsgatewayservice/">
-       <authenKey>545653AA-19E0-41BB-B89F-8485559CD0A7</authenKey>
-      <otpId>%s</otpId>
-      <otp>%s</otp>
-    </VerifyOtp>
-  </soap:Body>
-</soap:Envelope>`, otpId, otp)
-
-	// Send the request to the SOAP API
-	url := "https://crm.pea.co.th/Modules/SMS/WebServices/SmsGatewayService.asmx" // Replace with actual endpoint
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer([]byte(soapRequest)))
-	if err != nil {
-		return false, err
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
-	req.Header.Set("SOAPAction", "http://crm.pea.co.th/modules/sms/smsgatewayservice/VerifyOtp")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, err
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, err
-	}
-	// Parse the SOAP response
-	var soapResponse models.VerifyOtpSOAPResponse
-	if err := xml.Unmarshal(body, &soapResponse); err != nil {
-		return false, err
-	}
-
-	// Extract result
-	return soapResponse.Body.VerifyOtpResponse.VerifyOtpResult == "true", nil
+	c.JSON(http.StatusOK, tokens)
 }
 
 // RefreshToken godoc
 // @Summary Refresh authentication token
-// @Description This endpoint allows a user to refresh their authentication token.
+// @Description This endpoint allows a user to refresh their authentication token. Each refresh token can be used once; presenting a used refresh token again revokes the whole session.
 // @Tags Login
 // @Accept json
 // @Produce json
@@ -650,43 +581,19 @@ func (h *LoginHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	claims := &funcs.Claims{}
-	token, err := jwt.ParseWithClaims(req.RefreshToken, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.AppConfig.JWTSecret), nil
-	})
-
-	if err != nil || !token.Valid || claims.TokenType != "refresh" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
-		return
-	}
-	user, err := userhub.GetUserInfo(claims.EmpID)
+	tokens, err := funcs.RotateSession(req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "message": "Please login again"})
 		c.Abort()
 		return
 	}
 
-	newAccessToken, err := funcs.GenerateJWT(user, "access", time.Duration(config.AppConfig.JwtAccessTokenTime)*time.Minute)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating access token", "message": messages.ErrInternalServer.Error()})
-		return
-	}
-
-	newRefreshToken, err := funcs.GenerateRefreshJWT(user, "refresh", time.Duration(config.AppConfig.JwtRefreshTokenTime)*time.Hour)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating refresh token", "message": messages.ErrInternalServer.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, models.Login_Response{
-		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
-	})
+	c.JSON(http.StatusOK, tokens)
 }
 
 // Logout godoc
 // @Summary Log out the current user
-// @Description This endpoint allows a user to log out of their session, invalidating their authentication token.
+// @Description This endpoint allows a user to log out of their session, revoking its access and refresh tokens.
 // @Tags Login
 // @Accept json
 // @Produce json
@@ -695,6 +602,15 @@ func (h *LoginHandler) RefreshToken(c *gin.Context) {
 // @Router /api/logout [get]
 func (h *LoginHandler) Logout(c *gin.Context) {
 	user := funcs.GetAuthenUser(c, "*")
+	if c.IsAborted() {
+		return
+	}
+	if user.SessionUID != "" {
+		if err := funcs.RevokeSession(user.SessionUID, funcs.SessionRevokedLogout, user.EmpID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+			return
+		}
+	}
 
 	if user.LoginBy == "keycloak" {
 		endpoint := config.AppConfig.KeyCloakEndPoint + "/logout"
@@ -706,7 +622,7 @@ func (h *LoginHandler) Logout(c *gin.Context) {
 
 // Profile godoc
 // @Summary Get user profile
-// @Description This endpoint retrieves a user profile for the authenticated user.
+// @Description This endpoint retrieves a user profile for the authenticated user. is_impersonated, impersonator_emp_id and impersonator_full_name tell when support staff are viewing as the user.
 // @Tags Login
 // @Accept json
 // @Produce json
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+	"vms_plus_be/permission"
+	"vms_plus_be/userhub"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionHandler struct {
+	Role string
+}
+
+// GetEmployeePermission godoc
+// @Summary Retrieve the effective permissions of an employee
+// @Description This endpoint resolves an employee's roles as at login and returns the permissions they grant, the scope level and the carpools the employee may see. mas_carpool_uids is null when the employee sees every carpool.
+// @Tags Permission
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param emp_id path string true "EmpID (emp_id)"
+// @Router /api/permission/employee/{emp_id} [get]
+func (h *PermissionHandler) GetEmployeePermission(c *gin.Context) {
+	funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	empUser, err := userhub.GetUserInfo(c.Param("emp_id"))
+	if err != nil || empUser.EmpID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	empUser.Roles = append(empUser.Roles, "vehicle-user")
+	funcs.CheckAllRoles(&empUser)
+
+	result := models.EmployeePermission{
+		EmpID:               empUser.EmpID,
+		FullName:            empUser.FullName,
+		DeptSAP:             empUser.DeptSAP,
+		BureauDeptSap:       empUser.BureauDeptSap,
+		BusinessArea:        empUser.BusinessArea,
+		Roles:               empUser.Roles,
+		Permissions:         permission.GetPermissions(empUser.Roles),
+		Regions:             permission.GetRegions(&empUser),
+		ScopeLevel:          permission.GetScopeLevel(&empUser),
+		MasCarpoolUIDs:      permission.GetCarpoolUIDs(&empUser),
+		AdminMasCarpoolUIDs: permission.GetAdminCarpoolUIDs(&empUser),
+	}
+	c.JSON(http.StatusOK, result)
+}
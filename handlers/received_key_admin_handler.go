@@ -692,5 +692,6 @@ func (h *ReceivedKeyAdminHandler) UpdateCanceled(c *gin.Context) {
 		request.CanceledRequestReason,
 	)
 
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
@@ -464,6 +464,7 @@ func (h *ReceivedKeyUserHandler) UpdateCanceled(c *gin.Context) {
 			request.CanceledRequestReason,
 		)
 	}
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
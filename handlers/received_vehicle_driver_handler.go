@@ -351,6 +351,7 @@ func (h *ReceivedVehicleDriverHandler) GetTravelCard(c *gin.Context) {
 	request.VehicleUserImageURL = funcs.GetEmpImage(request.VehicleUserEmpID)
 	request.VehicleUserDeptSAPShort = request.VehicleUserPosition + " " + request.VehicleUserDeptSAPShort
 	request.ApprovedRequestDeptSAPShort = request.ApprovedRequestPosition + " " + request.ApprovedRequestDeptSAPShort
+	request.Passengers = funcs.GetRequestPassengers(request.TrnRequestUID)
 
 	c.JSON(http.StatusOK, request)
 
@@ -412,3 +412,23 @@ func (h *RefHandler) ListTimelineStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, lists)
 }
+
+// ListRequestAttachmentType godoc
+// @Summary Retrieve all request attachment types
+// @Description This endpoint retrieves all types of documents that can be attached to a booking request, such as official letter, invitation, map and other.
+// @Tags REF
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Router /api/ref/request-attachment-type [get]
+func (h *RefHandler) ListRequestAttachmentType(c *gin.Context) {
+	var lists []models.VmsRefRequestAttachmentType
+	if err := config.DB.
+		Order("ref_request_attachment_type_code").
+		Find(&lists).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, lists)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RegionAdminHandler struct {
+	Role string
+}
+
+// checkBusinessArea tells whether any department belongs to the business area or region
+func (h *RegionAdminHandler) checkBusinessArea(businessArea string) bool {
+	var count int64
+	config.DB.Table("vms_mas_department").Where("business_area like ?", businessArea+"%").Count(&count)
+	return count > 0
+}
+
+// SearchRegionAdmins godoc
+// @Summary Search region administrators
+// @Description This endpoint lists who holds admin-region and for which business area or region.
+// @Tags Region-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param search query string false "Search admin_emp_no or admin_emp_name"
+// @Param business_area query string false "Filter by business area or region"
+// @Param is_active query string false "Filter by is_active"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of records per page (default: 10)"
+// @Router /api/region-admin/search [get]
+func (h *RegionAdminHandler) SearchRegionAdmins(c *gin.Context) {
+	funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := config.DB.Model(&models.VmsMasRegionAdmin{}).Where("is_deleted = ?", "0")
+	if search := c.Query("search"); search != "" {
+		query = query.Where("admin_emp_no ILIKE ? OR admin_emp_name ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if businessArea := c.Query("business_area"); businessArea != "" {
+		query = query.Where("business_area = ?", businessArea)
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		query = query.Where("is_active = ?", isActive)
+	}
+
+	// Pagination
+	page := c.DefaultQuery("page", "1")
+	limit := c.DefaultQuery("limit", "10")
+	var pageInt, pageSizeInt int
+	fmt.Sscanf(page, "%d", &pageInt)
+	fmt.Sscanf(limit, "%d", &pageSizeInt)
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 10
+	}
+	offset := (pageInt - 1) * pageSizeInt
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	var regionAdmins []models.VmsMasRegionAdmin
+	if err := query.Order("business_area, admin_emp_name").Offset(offset).Limit(pageSizeInt).Find(&regionAdmins).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pagination": gin.H{
+			"total":      total,
+			"page":       page,
+			"limit":      pageSizeInt,
+			"totalPages": (total + int64(pageSizeInt) - 1) / int64(pageSizeInt),
+		},
+		"region_admins": regionAdmins,
+	})
+}
+
+// CreateRegionAdmin godoc
+// @Summary Assign a region administrator
+// @Description This endpoint gives an employee admin-region over every carpool, vehicle and driver whose business area starts with business_area. The employee gets the role at the next login or call.
+// @Tags Region-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsMasRegionAdminRequest true "VmsMasRegionAdminRequest data"
+// @Router /api/region-admin/create [post]
+func (h *RegionAdminHandler) CreateRegionAdmin(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasRegionAdminRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	request.BusinessArea = strings.ToUpper(strings.TrimSpace(request.BusinessArea))
+	if !h.checkBusinessArea(request.BusinessArea) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No department in business area " + request.BusinessArea, "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+	empUser := funcs.GetUserEmpInfo(request.AdminEmpNo)
+	if empUser.EmpID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Employee not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	var existing models.VmsMasRegionAdmin
+	if err := config.DB.Where("admin_emp_no = ? AND business_area = ? AND is_deleted = ?", request.AdminEmpNo, request.BusinessArea, "0").First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Region admin already exists", "message": "พนักงานนี้เป็นผู้ดูแลระดับภาคของพื้นที่นี้อยู่แล้ว"})
+		return
+	}
+
+	regionAdmin := models.VmsMasRegionAdmin{
+		MasRegionAdminUID: uuid.New().String(),
+		AdminEmpNo:        empUser.EmpID,
+		AdminEmpName:      empUser.FullName,
+		AdminDeptSap:      empUser.DeptSAP,
+		AdminPosition:     empUser.Position,
+		BusinessArea:      request.BusinessArea,
+		IsActive:          request.IsActive,
+		IsDeleted:         "0",
+		CreatedAt:         time.Now(),
+		CreatedBy:         user.EmpID,
+		UpdatedAt:         time.Now(),
+		UpdatedBy:         user.EmpID,
+	}
+	if regionAdmin.IsActive != "0" {
+		regionAdmin.IsActive = "1"
+	}
+	if err := config.DB.Create(&regionAdmin).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.AuditMasterDataRow(c, user, "vms_mas_region_admin", "mas_region_admin_uid", regionAdmin.MasRegionAdminUID, nil)
+	c.JSON(http.StatusCreated, gin.H{"message": "Region admin created successfully", "result": regionAdmin})
+}
+
+// UpdateRegionAdmin godoc
+// @Summary Update a region administrator
+// @Description This endpoint changes the business area or the active status of a region administrator.
+// @Tags Region-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_region_admin_uid path string true "MasRegionAdminUID (mas_region_admin_uid)"
+// @Param data body models.VmsMasRegionAdminRequest true "VmsMasRegionAdminRequest data"
+// @Router /api/region-admin/update/{mas_region_admin_uid} [put]
+func (h *RegionAdminHandler) UpdateRegionAdmin(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasRegionAdminRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	request.BusinessArea = strings.ToUpper(strings.TrimSpace(request.BusinessArea))
+	if !h.checkBusinessArea(request.BusinessArea) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No department in business area " + request.BusinessArea, "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	var regionAdmin models.VmsMasRegionAdmin
+	if err := config.DB.Where("mas_region_admin_uid = ? AND is_deleted = ?", c.Param("mas_region_admin_uid"), "0").First(&regionAdmin).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Region admin not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	before := funcs.GetMasterDataSnapshot("vms_mas_region_admin", "mas_region_admin_uid", regionAdmin.MasRegionAdminUID)
+	regionAdmin.BusinessArea = request.BusinessArea
+	regionAdmin.IsActive = request.IsActive
+	if regionAdmin.IsActive != "0" {
+		regionAdmin.IsActive = "1"
+	}
+	regionAdmin.UpdatedAt = time.Now()
+	regionAdmin.UpdatedBy = user.EmpID
+	if err := config.DB.Save(&regionAdmin).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update: %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.AuditMasterDataRow(c, user, "vms_mas_region_admin", "mas_region_admin_uid", regionAdmin.MasRegionAdminUID, before)
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": regionAdmin})
+}
+
+// DeleteRegionAdmin godoc
+// @Summary Remove a region administrator
+// @Description This endpoint takes admin-region for the business area away from the employee.
+// @Tags Region-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_region_admin_uid path string true "MasRegionAdminUID (mas_region_admin_uid)"
+// @Router /api/region-admin/delete/{mas_region_admin_uid} [delete]
+func (h *RegionAdminHandler) DeleteRegionAdmin(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var regionAdmin models.VmsMasRegionAdmin
+	if err := config.DB.Where("mas_region_admin_uid = ? AND is_deleted = ?", c.Param("mas_region_admin_uid"), "0").First(&regionAdmin).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Region admin not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	before := funcs.GetMasterDataSnapshot("vms_mas_region_admin", "mas_region_admin_uid", regionAdmin.MasRegionAdminUID)
+	if err := config.DB.Model(&regionAdmin).UpdateColumns(map[string]interface{}{
+		"is_deleted": "1",
+		"updated_by": user.EmpID,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete region admin", "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.AuditMasterDataRow(c, user, "vms_mas_region_admin", "mas_region_admin_uid", regionAdmin.MasRegionAdminUID, before)
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
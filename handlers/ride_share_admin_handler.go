@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RideShareAdminHandler struct {
+	Role string
+}
+
+func (h *RideShareAdminHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
+	return funcs.SetQueryAdminRole(user, query)
+}
+
+func (h *RideShareAdminHandler) SetQueryRoleRideShare(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
+	return query.Where(`vms_trn_ride_share.is_deleted = '0' and exists (
+			select 1 from vms_mas_carpool_admin ca
+			where ca.mas_carpool_uid = vms_trn_ride_share.mas_carpool_uid
+			and ca.admin_emp_no = ? and ca.is_deleted = '0' and ca.is_active = '1'
+		)`, user.EmpID)
+}
+
+// CreateProposals godoc
+// @Summary Find requests that can share a vehicle and create ride-share proposals
+// @Description This endpoint matches pending or approved carpool requests in the given period that overlap in time, go to a similar work place and fit in the seats of one vehicle.
+// @Tags Ride-share-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRideShareSearch true "VmsTrnRideShareSearch data"
+// @Router /api/ride-share-admin/create-proposals [post]
+func (h *RideShareAdminHandler) CreateProposals(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnRideShareSearch
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if !request.EndDatetime.After(request.StartDatetime.Time) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_datetime must be after start_datetime", "message": messages.ErrInvalidDate.Error()})
+		return
+	}
+
+	var candidates []models.VmsTrnRideShareCandidate
+	query := h.SetQueryRole(user, config.DB)
+	query = funcs.SetQueryRideShareCandidate(query)
+	query = funcs.SetQueryNotInRideShare(query)
+	if err := query.Where("vms_trn_request.reserve_start_datetime >= ? and vms_trn_request.reserve_start_datetime < ?", request.StartDatetime, request.EndDatetime).
+		Order("vms_trn_request.reserve_start_datetime").
+		Find(&candidates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	groups := funcs.FindRideShareMatches(candidates)
+	rideShares := make([]models.VmsTrnRideShare, 0, len(groups))
+	for _, group := range groups {
+		rideShare := funcs.BuildRideShare(group, user.EmpID)
+		if err := config.DB.Create(&rideShare).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+			return
+		}
+		funcs.FillRideShareRequests(&rideShare)
+		rideShares = append(rideShares, rideShare)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Proposals created successfully", "total": len(rideShares), "result": rideShares})
+}
+
+// SearchProposals godoc
+// @Summary List ride-share proposals
+// @Description This endpoint returns the ride-share proposals of the carpools managed by the current admin.
+// @Tags Ride-share-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param ride_share_status_code query string false "Filter by status code (comma-separated, e.g., '0,1')"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of records per page (default: 10)"
+// @Router /api/ride-share-admin/search-proposals [get]
+func (h *RideShareAdminHandler) SearchProposals(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := h.SetQueryRoleRideShare(user, config.DB.Model(&models.VmsTrnRideShare{}))
+	if statusCodes := c.Query("ride_share_status_code"); statusCodes != "" {
+		query = query.Where("vms_trn_ride_share.ride_share_status_code IN (?)", strings.Split(statusCodes, ","))
+	}
+
+	// Pagination
+	page := c.DefaultQuery("page", "1")
+	limit := c.DefaultQuery("limit", "10")
+	var pageInt, pageSizeInt int
+	fmt.Sscanf(page, "%d", &pageInt)
+	fmt.Sscanf(limit, "%d", &pageSizeInt)
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 10
+	}
+	offset := (pageInt - 1) * pageSizeInt
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	var rideShares []models.VmsTrnRideShare
+	if err := query.Preload("Requests").
+		Order("vms_trn_ride_share.start_datetime desc").
+		Offset(offset).Limit(pageSizeInt).
+		Find(&rideShares).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	for i := range rideShares {
+		funcs.FillRideShareRequests(&rideShares[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pagination": gin.H{
+			"total":      total,
+			"page":       page,
+			"limit":      pageSizeInt,
+			"totalPages": (total + int64(pageSizeInt) - 1) / int64(pageSizeInt),
+		},
+		"requests": rideShares,
+	})
+}
+
+// GetProposal godoc
+// @Summary Retrieve a ride-share proposal
+// @Description This endpoint fetches a ride-share proposal with the details of its requests.
+// @Tags Ride-share-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_ride_share_uid path string true "TrnRideShareUID (trn_ride_share_uid)"
+// @Router /api/ride-share-admin/proposal/{trn_ride_share_uid} [get]
+func (h *RideShareAdminHandler) GetProposal(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var rideShare models.VmsTrnRideShare
+	query := h.SetQueryRoleRideShare(user, config.DB)
+	if err := query.Preload("Requests").First(&rideShare, "trn_ride_share_uid = ?", c.Param("trn_ride_share_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "message": messages.ErrRideShareNotFound.Error()})
+		return
+	}
+	funcs.FillRideShareRequests(&rideShare)
+
+	c.JSON(http.StatusOK, rideShare)
+}
+
+// UpdateAccepted godoc
+// @Summary Accept a ride-share proposal
+// @Description This endpoint merges the requests of a proposal into the vehicle and driver of the host request. The host window is extended to cover every request, and the vehicle, driver and seats must still fit. The other requests are linked to the host and keep their own cost and survey.
+// @Tags Ride-share-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRideShareAccepted true "VmsTrnRideShareAccepted data"
+// @Router /api/ride-share-admin/update-accepted [put]
+func (h *RideShareAdminHandler) UpdateAccepted(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnRideShareAccepted
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var rideShare models.VmsTrnRideShare
+	query := h.SetQueryRoleRideShare(user, config.DB)
+	if err := query.Preload("Requests").
+		First(&rideShare, "trn_ride_share_uid = ? and ride_share_status_code = ?", request.TrnRideShareUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "message": messages.ErrRideShareNotFound.Error()})
+		return
+	}
+
+	// Requests may have been cancelled or reassigned since the proposal was made
+	var host models.VmsTrnRideShareCandidate
+	var guests []models.VmsTrnRideShareCandidate
+	for _, rideShareRequest := range rideShare.Requests {
+		var candidate models.VmsTrnRideShareCandidate
+		if err := funcs.SetQueryRideShareCandidate(config.DB).
+			First(&candidate, "trn_request_uid = ?", rideShareRequest.TrnRequestUID).Error; err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "message": messages.ErrRideShareChanged.Error()})
+			return
+		}
+		if rideShareRequest.IsHost == "1" {
+			host = candidate
+		} else {
+			guests = append(guests, candidate)
+		}
+	}
+	requests := append([]models.VmsTrnRideShareCandidate{host}, guests...)
+	totalPassengers := 0
+	for _, candidate := range requests {
+		totalPassengers += max(candidate.NumberOfPassengers, 1)
+	}
+	seatCapacity := funcs.GetRequestSeatCapacity(host.TrnRequestUID)
+	if host.TrnRequestUID == "" || len(guests) == 0 || seatCapacity == 0 || totalPassengers > seatCapacity {
+		c.JSON(http.StatusConflict, gin.H{"error": "requests no longer fit in the host vehicle", "message": messages.ErrRideShareChanged.Error()})
+		return
+	}
+	for _, guest := range guests {
+		if !funcs.IsRideShareCompatible(host, guest) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("request %s no longer matches the host request", guest.RequestNo), "message": messages.ErrRideShareChanged.Error()})
+			return
+		}
+	}
+
+	// The host vehicle and driver carry every request, so they must be free for the union of the windows
+	startDatetime, endDatetime := funcs.GetRideShareWindow(requests)
+	available, err := funcs.IsRideShareAvailable(host, requests, startDatetime, endDatetime)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if !available {
+		c.JSON(http.StatusConflict, gin.H{"error": "host vehicle or driver is not available for the combined window", "message": messages.ErrRideShareChanged.Error()})
+		return
+	}
+
+	hostMerged := host
+	hostMerged.IsHaveSubRequest = "1"
+	hostMerged.ReserveStartDatetime = models.TimeWithZone{Time: startDatetime}
+	hostMerged.ReserveEndDatetime = models.TimeWithZone{Time: endDatetime}
+	guestsMerged := make([]models.VmsTrnRideShareCandidate, len(guests))
+	for i, guest := range guests {
+		guestMerged := guest
+		guestMerged.ParentTrnRequestUID = &host.TrnRequestUID
+		guestMerged.MasVehicleUID = host.MasVehicleUID
+		guestMerged.MasVehicleDepartmentUID = host.MasVehicleDepartmentUID
+		guestMerged.MasCarPoolDriverUID = host.MasCarPoolDriverUID
+		guestMerged.IsPEAEmployeeDriver = host.IsPEAEmployeeDriver
+		guestMerged.DriverEmpID = host.DriverEmpID
+		guestMerged.DriverEmpName = host.DriverEmpName
+		guestMerged.DriverEmpDeptSAP = host.DriverEmpDeptSAP
+		guestMerged.DriverEmpDeptNameShort = host.DriverEmpDeptNameShort
+		guestMerged.DriverEmpDeptNameFull = host.DriverEmpDeptNameFull
+		guestsMerged[i] = guestMerged
+	}
+
+	rideShare.RideShareStatusCode = "1"
+	rideShare.StartDatetime = hostMerged.ReserveStartDatetime
+	rideShare.EndDatetime = hostMerged.ReserveEndDatetime
+	rideShare.TotalPassengers = totalPassengers
+	rideShare.SeatCapacity = seatCapacity
+	rideShare.AcceptedEmpID = user.EmpID
+	rideShare.AcceptedEmpName = user.FullName
+	rideShare.AcceptedDatetime = models.TimeWithZone{Time: time.Now()}
+	rideShare.UpdatedAt = time.Now()
+	rideShare.UpdatedBy = user.EmpID
+
+	// Each write re-checks its row so a second accept of the proposal, or of another proposal sharing a request, fails
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.VmsTrnRideShare{}).
+			Where("trn_ride_share_uid = ? and ride_share_status_code = ?", rideShare.TrnRideShareUID, "0").
+			UpdateColumns(map[string]interface{}{
+				"ride_share_status_code": rideShare.RideShareStatusCode,
+				"start_datetime":         rideShare.StartDatetime,
+				"end_datetime":           rideShare.EndDatetime,
+				"total_passengers":       rideShare.TotalPassengers,
+				"seat_capacity":          rideShare.SeatCapacity,
+				"accepted_emp_id":        rideShare.AcceptedEmpID,
+				"accepted_emp_name":      rideShare.AcceptedEmpName,
+				"accepted_datetime":      rideShare.AcceptedDatetime,
+				"updated_by":             rideShare.UpdatedBy,
+				"updated_at":             rideShare.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return messages.ErrRideShareChanged
+		}
+
+		result = funcs.SetQueryRideShareCandidate(tx.Model(&models.VmsTrnRideShareCandidate{})).
+			Where("trn_request_uid = ?", host.TrnRequestUID).
+			UpdateColumns(map[string]interface{}{
+				"is_have_sub_request":    hostMerged.IsHaveSubRequest,
+				"reserve_start_datetime": hostMerged.ReserveStartDatetime,
+				"reserve_end_datetime":   hostMerged.ReserveEndDatetime,
+				"updated_by":             user.EmpID,
+				"updated_at":             time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return messages.ErrRideShareChanged
+		}
+		for _, guestMerged := range guestsMerged {
+			result := funcs.SetQueryRideShareCandidate(tx.Model(&models.VmsTrnRideShareCandidate{})).
+				Where("trn_request_uid = ?", guestMerged.TrnRequestUID).
+				UpdateColumns(map[string]interface{}{
+					"parent_trn_request_uid":     guestMerged.ParentTrnRequestUID,
+					"mas_vehicle_uid":            guestMerged.MasVehicleUID,
+					"mas_vehicle_department_uid": guestMerged.MasVehicleDepartmentUID,
+					"mas_carpool_driver_uid":     guestMerged.MasCarPoolDriverUID,
+					"is_pea_employee_driver":     guestMerged.IsPEAEmployeeDriver,
+					"driver_emp_id":              guestMerged.DriverEmpID,
+					"driver_emp_name":            guestMerged.DriverEmpName,
+					"driver_emp_dept_sap":        guestMerged.DriverEmpDeptSAP,
+					"driver_emp_dept_name_short": guestMerged.DriverEmpDeptNameShort,
+					"driver_emp_dept_name_full":  guestMerged.DriverEmpDeptNameFull,
+					"updated_by":                 user.EmpID,
+					"updated_at":                 time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return messages.ErrRideShareChanged
+			}
+		}
+		return nil
+	})
+	if err == messages.ErrRideShareChanged {
+		c.JSON(http.StatusConflict, gin.H{"error": "proposal or its requests were changed by another action", "message": messages.ErrRideShareChanged.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.CreateTrnRequestChangeLog(host.TrnRequestUID, "ride-share", &host, &hostMerged, user.EmpID, "admin-carpool")
+	funcs.CreateTrnRequestEventLog(host.TrnRequestUID, host.RefRequestStatusCode, "รวมการเดินทาง เป็นคำขอหลัก", user.EmpID, "admin-carpool", "")
+	for i, guest := range guests {
+		funcs.CreateTrnRequestChangeLog(guest.TrnRequestUID, "ride-share", &guest, &guestsMerged[i], user.EmpID, "admin-carpool")
+		funcs.CreateTrnRequestEventLog(guest.TrnRequestUID, guest.RefRequestStatusCode, "รวมการเดินทาง กับคำขอ "+host.RequestNo, user.EmpID, "admin-carpool", "")
+	}
+	funcs.FillRideShareRequests(&rideShare)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": rideShare})
+}
+
+// UpdateRejected godoc
+// @Summary Reject a ride-share proposal
+// @Description This endpoint rejects a ride-share proposal so its requests keep their own vehicles and can be matched again.
+// @Tags Ride-share-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRideShareRejected true "VmsTrnRideShareRejected data"
+// @Router /api/ride-share-admin/update-rejected [put]
+func (h *RideShareAdminHandler) UpdateRejected(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsTrnRideShareRejected
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var rideShare models.VmsTrnRideShare
+	query := h.SetQueryRoleRideShare(user, config.DB)
+	if err := query.First(&rideShare, "trn_ride_share_uid = ? and ride_share_status_code = ?", request.TrnRideShareUID, "0").Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "message": messages.ErrRideShareNotFound.Error()})
+		return
+	}
+
+	if err := config.DB.Model(&rideShare).UpdateColumns(map[string]interface{}{
+		"ride_share_status_code": "2",
+		"rejected_reason":        request.RejectedReason,
+		"updated_by":             user.EmpID,
+		"updated_at":             time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": request})
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"vms_plus_be/config"
+	"vms_plus_be/funcs"
 	"vms_plus_be/messages"
 	"vms_plus_be/models"
 	"vms_plus_be/userhub"
@@ -14,7 +15,12 @@ type ServiceHandler struct {
 	Role string
 }
 
+// checkServiceKey accepts an API client key whose scopes cover the service route group, or a ServiceKey of userhub
 func (h *ServiceHandler) checkServiceKey(c *gin.Context, serviceCode string) {
+	if c.GetHeader("X-ApiKey") != "" {
+		funcs.CheckApiClient(c)
+		return
+	}
 	serviceKey := c.GetHeader("ServiceKey")
 	isValid, err := userhub.CheckServiceKey(serviceKey, serviceCode)
 	if err != nil {
@@ -37,6 +43,7 @@ func (h *ServiceHandler) checkServiceKey(c *gin.Context, serviceCode string) {
 // @Accept json
 // @Produce json
 // @Security ServiceKey
+// @Security ApiKeyAuth
 // @Param request_no path string true "RequestNo"
 // @router /api/service/vms-to-eems/{request_no} [get]
 func (h *ServiceHandler) GetVMSToEEMS(c *gin.Context) {
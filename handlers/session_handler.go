@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	Role string
+}
+
+// getActiveSessions returns the sessions of the employee whose tokens are still accepted, latest used first
+func getActiveSessions(empID string) ([]models.VmsTrnSession, error) {
+	var sessions []models.VmsTrnSession
+	err := config.DB.Where("emp_id = ? AND is_revoked = ? AND expires_at > ?", empID, "0", time.Now()).
+		Order("last_used_at desc").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// GetMySessions godoc
+// @Summary Retrieve the active sessions of the current user
+// @Description This endpoint lists where the current user is logged in, by device, IP address and login method. is_current marks the session of this call.
+// @Tags Session
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Router /api/session/my-sessions [get]
+func (h *SessionHandler) GetMySessions(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	sessions, err := getActiveSessions(user.EmpID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	for i := range sessions {
+		sessions[i].IsCurrent = sessions[i].SessionUID == user.SessionUID
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// TerminateMySession godoc
+// @Summary Terminate a session of the current user
+// @Description This endpoint logs the current user out of one of their sessions, for example on a lost device.
+// @Tags Session
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param session_uid path string true "SessionUID (session_uid)"
+// @Router /api/session/terminate/{session_uid} [delete]
+func (h *SessionHandler) TerminateMySession(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var session models.VmsTrnSession
+	if err := config.DB.Where("session_uid = ? AND emp_id = ? AND is_revoked = ?", c.Param("session_uid"), user.EmpID, "0").First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	if err := funcs.RevokeSession(session.SessionUID, funcs.SessionRevokedByUser, user.EmpID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session terminated successfully"})
+}
+
+type SessionAdminHandler struct {
+	Role string
+}
+
+// GetEmployeeSessions godoc
+// @Summary Retrieve the active sessions of an employee
+// @Description This endpoint lists where an employee or driver is logged in, by device, IP address and login method.
+// @Tags Session-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param emp_id path string true "EmpID (emp_id)"
+// @Router /api/session-admin/employee/{emp_id} [get]
+func (h *SessionAdminHandler) GetEmployeeSessions(c *gin.Context) {
+	funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	sessions, err := getActiveSessions(c.Param("emp_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description This endpoint ends one session, its access and refresh tokens are refused from the next call.
+// @Tags Session-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param session_uid path string true "SessionUID (session_uid)"
+// @Router /api/session-admin/revoke/{session_uid} [put]
+func (h *SessionAdminHandler) RevokeSession(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var session models.VmsTrnSession
+	if err := config.DB.Where("session_uid = ? AND is_revoked = ?", c.Param("session_uid"), "0").First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	if err := funcs.RevokeSession(session.SessionUID, funcs.SessionRevokedByAdmin, user.EmpID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+// RevokeEmployeeSessions godoc
+// @Summary Revoke every session of an employee
+// @Description This endpoint logs an employee or driver out everywhere, for example when the account is compromised.
+// @Tags Session-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param emp_id path string true "EmpID (emp_id)"
+// @Router /api/session-admin/revoke-employee/{emp_id} [put]
+func (h *SessionAdminHandler) RevokeEmployeeSessions(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	revoked, err := funcs.RevokeEmployeeSessions(c.Param("emp_id"), funcs.SessionRevokedByAdmin, user.EmpID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Sessions revoked successfully", "revoked": revoked})
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/messages"
+	"vms_plus_be/models"
+	"vms_plus_be/permission"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SlaAdminHandler struct {
+	Role string
+}
+
+func (h *SlaAdminHandler) SetQueryRole(user *models.AuthenUserEmp, query *gorm.DB, carpoolColumn string) *gorm.DB {
+	return permission.SetQueryAdminCarpoolScope(user, query, carpoolColumn)
+}
+
+func (h *SlaAdminHandler) validateSla(user *models.AuthenUserEmp, request *models.VmsMasSlaRequest) error {
+	if !funcs.Contains(funcs.SlaStatusCodes, request.RefRequestStatusCode) {
+		return fmt.Errorf("ref_request_status_code must be one of %s", strings.Join(funcs.SlaStatusCodes, ","))
+	}
+	if request.SlaHours <= 0 || request.ReminderHours < 0 || request.EscalateHours < 0 {
+		return fmt.Errorf("sla_hours must be more than 0")
+	}
+	if request.MasCarpoolUID != nil && *request.MasCarpoolUID == "" {
+		request.MasCarpoolUID = nil
+	}
+	if request.MasCarpoolUID == nil && !permission.HasPermission(user, permission.ScopeAll) {
+		return fmt.Errorf("mas_carpool_uid is required")
+	}
+	if request.MasCarpoolUID != nil && !permission.HasPermission(user, permission.ScopeAll) {
+		var count int64
+		config.DB.Model(&models.VmsMasCarpoolAdmin{}).
+			Where("mas_carpool_uid = ? and admin_emp_no = ? and is_deleted = '0' and is_active = '1'", request.MasCarpoolUID, user.EmpID).
+			Count(&count)
+		if count == 0 {
+			return fmt.Errorf("carpool is not managed by user")
+		}
+	}
+	if request.IsAutoCancel != "1" {
+		request.IsAutoCancel = "0"
+	}
+	if request.IsActive != "0" {
+		request.IsActive = "1"
+	}
+	return nil
+}
+
+// SearchSlas godoc
+// @Summary List SLAs of pending approval statuses
+// @Description This endpoint returns the SLA settings per request status and carpool. An SLA without a carpool is the default for requests of carpools without their own setting.
+// @Tags SLA-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_carpool_uid query string false "Filter by carpool"
+// @Router /api/sla-admin/search-slas [get]
+func (h *SlaAdminHandler) SearchSlas(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	query := config.DB.Where("is_deleted = '0'")
+	if !permission.HasPermission(user, permission.ScopeAll) {
+		// carpool admins see the default SLAs and the SLAs of their own carpools
+		query = query.Where(`mas_carpool_uid is null or mas_carpool_uid in (
+			select ca.mas_carpool_uid from vms_mas_carpool_admin ca
+			where ca.admin_emp_no = ? and ca.is_deleted = '0' and ca.is_active = '1'
+		)`, user.EmpID)
+	}
+	if masCarpoolUID := c.Query("mas_carpool_uid"); masCarpoolUID != "" {
+		query = query.Where("mas_carpool_uid = ?", masCarpoolUID)
+	}
+
+	var slas []models.VmsMasSla
+	if err := query.Order("ref_request_status_code, mas_carpool_uid nulls first").Find(&slas).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, slas)
+}
+
+// CreateSla godoc
+// @Summary Create an SLA for a pending approval status
+// @Description This endpoint creates the reminder, escalation and breach hours of a status, counted in working days. Only admin-super can create the default SLA without a carpool.
+// @Tags SLA-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsMasSlaRequest true "VmsMasSlaRequest data"
+// @Router /api/sla-admin/create-sla [post]
+func (h *SlaAdminHandler) CreateSla(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasSlaRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+	if err := h.validateSla(user, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	sla := models.VmsMasSla{
+		MasSlaUID:            uuid.New().String(),
+		RefRequestStatusCode: request.RefRequestStatusCode,
+		MasCarpoolUID:        request.MasCarpoolUID,
+		SlaHours:             request.SlaHours,
+		ReminderHours:        request.ReminderHours,
+		EscalateHours:        request.EscalateHours,
+		IsAutoCancel:         request.IsAutoCancel,
+		IsActive:             request.IsActive,
+		CreatedAt:            time.Now(),
+		CreatedBy:            user.EmpID,
+		UpdatedAt:            time.Now(),
+		UpdatedBy:            user.EmpID,
+		IsDeleted:            "0",
+	}
+	if err := config.DB.Create(&sla).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "SLA created successfully", "result": sla})
+}
+
+// UpdateSla godoc
+// @Summary Update an SLA
+// @Description This endpoint updates the hours and auto-cancel of an SLA.
+// @Tags SLA-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsMasSlaRequest true "VmsMasSlaRequest data"
+// @Router /api/sla-admin/update-sla [put]
+func (h *SlaAdminHandler) UpdateSla(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var request models.VmsMasSlaRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var sla models.VmsMasSla
+	query := h.SetQueryRole(user, config.DB, "mas_carpool_uid")
+	if err := query.First(&sla, "mas_sla_uid = ? and is_deleted = '0'", request.MasSlaUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SLA not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+	request.RefRequestStatusCode = sla.RefRequestStatusCode
+	request.MasCarpoolUID = sla.MasCarpoolUID
+	if err := h.validateSla(user, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	if err := config.DB.Model(&sla).UpdateColumns(map[string]interface{}{
+		"sla_hours":      request.SlaHours,
+		"reminder_hours": request.ReminderHours,
+		"escalate_hours": request.EscalateHours,
+		"is_auto_cancel": request.IsAutoCancel,
+		"is_active":      request.IsActive,
+		"updated_by":     user.EmpID,
+		"updated_at":     time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": sla})
+}
+
+// DeleteSla godoc
+// @Summary Delete an SLA
+// @Description This endpoint deletes an SLA. Requests of the carpool fall back to the default SLA.
+// @Tags SLA-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param mas_sla_uid path string true "MasSlaUID (mas_sla_uid)"
+// @Router /api/sla-admin/delete-sla/{mas_sla_uid} [delete]
+func (h *SlaAdminHandler) DeleteSla(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	var sla models.VmsMasSla
+	query := h.SetQueryRole(user, config.DB, "mas_carpool_uid")
+	if err := query.First(&sla, "mas_sla_uid = ? and is_deleted = '0'", c.Param("mas_sla_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SLA not found", "message": messages.ErrNotfound.Error()})
+		return
+	}
+
+	if err := config.DB.Model(&sla).UpdateColumns(map[string]interface{}{
+		"is_deleted": "1",
+		"updated_by": user.EmpID,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// ReportSlaBreach godoc
+// @Summary Report of requests that breached the SLA
+// @Description This endpoint lists the tracked pending periods of requests in the date range with the working hours they waited, the reminder, the escalation and whether they were cancelled automatically.
+// @Tags SLA-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Param ref_request_status_code query string false "Filter by status code (comma-separated, e.g., '20,30')"
+// @Param is_breached query string false "1 for breached only (default), 0 for all"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Number of records per page (default: 10)"
+// @Router /api/sla-admin/report-breach [get]
+func (h *SlaAdminHandler) ReportSlaBreach(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format", "message": messages.ErrInvalidDate.Error()})
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format", "message": messages.ErrInvalidDate.Error()})
+		return
+	}
+
+	query := config.DB.Table("vms_trn_request_sla s").
+		Select("s.*, r.request_no, r.vehicle_user_emp_name, r.reserve_start_datetime, r.mas_carpool_uid, m.sla_hours").
+		Joins("INNER JOIN vms_trn_request r ON r.trn_request_uid = s.trn_request_uid").
+		Joins("LEFT JOIN vms_mas_sla m ON m.mas_sla_uid = s.mas_sla_uid").
+		Where("s.status_start_datetime >= ? AND s.status_start_datetime < ?", startDate, endDate.AddDate(0, 0, 1))
+	query = h.SetQueryRole(user, query, "r.mas_carpool_uid")
+	if c.DefaultQuery("is_breached", "1") == "1" {
+		query = query.Where("s.is_breached = '1'")
+	}
+	if statusCodes := c.Query("ref_request_status_code"); statusCodes != "" {
+		query = query.Where("s.ref_request_status_code IN (?)", strings.Split(statusCodes, ","))
+	}
+
+	// Pagination
+	page := c.DefaultQuery("page", "1")
+	limit := c.DefaultQuery("limit", "10")
+	var pageInt, pageSizeInt int
+	fmt.Sscanf(page, "%d", &pageInt)
+	fmt.Sscanf(limit, "%d", &pageSizeInt)
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 10
+	}
+	offset := (pageInt - 1) * pageSizeInt
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	var reports []models.VmsTrnRequestSlaReport
+	if err := query.Order("s.status_start_datetime desc").Offset(offset).Limit(pageSizeInt).Scan(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pagination": gin.H{
+			"total":      total,
+			"page":       page,
+			"limit":      pageSizeInt,
+			"totalPages": (total + int64(pageSizeInt) - 1) / int64(pageSizeInt),
+		},
+		"requests": reports,
+	})
+}
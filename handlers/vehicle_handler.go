@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"time"
 	"vms_plus_be/config"
 	"vms_plus_be/funcs"
 	"vms_plus_be/messages"
@@ -157,6 +160,8 @@ func (h *VehicleHandler) SearchBookingVehicles(c *gin.Context) {
 		empUser := funcs.GetUserEmpInfo(empID)
 		bureauDeptSap = empUser.BureauDeptSap
 		businessArea = empUser.BusinessArea
+	} else {
+		empID = user.EmpID
 	}
 
 	startDate := c.Query("start_date")
@@ -466,6 +471,226 @@ func (h *VehicleHandler) SearchBookingVehiclesCarpool(c *gin.Context) {
 	})
 }
 
+// SearchBookingSuggestions godoc
+// @Summary Suggest alternative time slots and vehicles when nothing is available
+// @Description Proposes the nearest free time slots for the same carpool or vehicle, and equivalent vehicles (same car type or seat count) in other carpools ranked by deviation from the original request
+// @Tags Vehicle
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param emp_id query string false "Employee ID (emp_id) default(700001)"
+// @Param start_date query string true "Start Date (YYYY-MM-DD HH:mm:ss)" default(2025-05-30 08:00:00)
+// @Param end_date query string true "End Date (YYYY-MM-DD HH:mm:ss)" default(2025-05-30 16:00:00)
+// @Param mas_carpool_uid query string false "Requested MasCarpoolUID"
+// @Param mas_vehicle_uid query string false "Requested MasVehicleUID"
+// @Param mas_carpool_driver_uid query string false "Requested MasCarpoolDriverUID"
+// @Param car_type query string false "Requested Car Type"
+// @Param seat query int false "Requested seat count"
+// @Param limit query int false "Maximum number of slots and vehicles to suggest (default: 5)"
+// @Router /api/vehicle/search-booking-suggestions [get]
+func (h *VehicleHandler) SearchBookingSuggestions(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+
+	empID := c.Query("emp_id")
+	bureauDeptSap := user.BureauDeptSap
+	businessArea := user.BusinessArea
+
+	if empID != "" {
+		empUser := funcs.GetUserEmpInfo(empID)
+		bureauDeptSap = empUser.BureauDeptSap
+		businessArea = empUser.BusinessArea
+	}
+
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start Date and End Date are required", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+	StartTimeWithZone, err1 := models.GetTimeWithZone(startDate)
+	EndTimeWithZone, err2 := models.GetTimeWithZone(endDate)
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get start time with zone", "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if !EndTimeWithZone.After(StartTimeWithZone.Time) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "End Date must be after Start Date", "message": messages.ErrInvalidRequest.Error()})
+		return
+	}
+
+	masCarpoolUID := c.Query("mas_carpool_uid")
+	masVehicleUID := c.Query("mas_vehicle_uid")
+	masCarpoolDriverUID := c.Query("mas_carpool_driver_uid")
+	carType := c.Query("car_type")
+	seat, _ := strconv.Atoi(c.Query("seat"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit <= 0 {
+		limit = 5
+	}
+
+	// Use the requested vehicle as the reference when type or seat is not given
+	if masVehicleUID != "" && (carType == "" || seat == 0) {
+		var refVehicle models.VmsMasVehicleList
+		if err := config.DB.Select("mas_vehicle_uid, \"CarTypeDetail\", seat").
+			Where("mas_vehicle_uid = ? AND is_deleted = '0'", masVehicleUID).
+			First(&refVehicle).Error; err == nil {
+			if carType == "" {
+				carType = refVehicle.CarType
+			}
+			if seat == 0 {
+				seat = refVehicle.Seat
+			}
+		}
+	}
+
+	// Nearest free slots for the same carpool or vehicle, shifted later or earlier by an hour at a time
+	const slotShiftInterval = time.Hour
+	const slotMaxShiftSteps = 72
+	duration := EndTimeWithZone.Sub(StartTimeWithZone.Time)
+	searchFrom := StartTimeWithZone.Add(-slotMaxShiftSteps * slotShiftInterval)
+	searchTo := EndTimeWithZone.Add(slotMaxShiftSteps * slotShiftInterval)
+
+	var candidateUIDs []string
+	queryCandidate := config.DB.Table("vms_mas_vehicle v").
+		Joins("INNER JOIN vms_mas_vehicle_department vd ON v.mas_vehicle_uid = vd.mas_vehicle_uid AND vd.ref_vehicle_status_code = '0' AND vd.is_deleted = '0' AND vd.is_active = '1'").
+		Where("v.is_deleted = '0' AND v.is_active = '1'")
+	if masVehicleUID != "" {
+		queryCandidate = queryCandidate.Where("v.mas_vehicle_uid = ?", masVehicleUID)
+	} else if masCarpoolUID != "" {
+		queryCandidate = queryCandidate.Where("v.mas_vehicle_uid IN (SELECT mas_vehicle_uid FROM vms_mas_carpool_vehicle WHERE mas_carpool_uid = ? AND is_deleted = '0' AND is_active = '1')", masCarpoolUID)
+	} else {
+		businessAreaPrefix := businessArea
+		if len(businessAreaPrefix) > 1 {
+			businessAreaPrefix = businessAreaPrefix[:1]
+		}
+		queryCandidate = queryCandidate.Where("(vd.bureau_dept_sap = ?) OR (vd.bureau_ba like ?)", bureauDeptSap, businessAreaPrefix+"%")
+		if carType != "" {
+			queryCandidate = queryCandidate.Where("v.\"CarTypeDetail\" = ?", carType)
+		}
+	}
+	if err := queryCandidate.Distinct().Pluck("v.mas_vehicle_uid", &candidateUIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch available vehicles", "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	vehicleBusy, err := funcs.GetVehicleBusyIntervals(candidateUIDs, searchFrom, searchTo, empID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch available vehicles", "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	var driverBusy []funcs.BusyInterval
+	if masCarpoolDriverUID != "" {
+		driverBusy, err = funcs.GetDriverBusyIntervals(masCarpoolDriverUID, searchFrom, searchTo)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch available drivers", "message": messages.ErrInternalServer.Error()})
+			return
+		}
+	}
+
+	now := time.Now()
+	slots := make([]models.VmsMasVehicleSuggestSlot, 0)
+	for step := 1; step <= slotMaxShiftSteps && len(slots) < limit; step++ {
+		for _, direction := range []int{1, -1} {
+			shift := time.Duration(direction*step) * slotShiftInterval
+			slotStart := StartTimeWithZone.Add(shift)
+			if slotStart.Before(now) {
+				continue
+			}
+			slotEnd := slotStart.Add(duration)
+			if !funcs.IsIntervalFree(driverBusy, slotStart, slotEnd) {
+				continue
+			}
+
+			count := 0
+			for _, candidateUID := range candidateUIDs {
+				if funcs.IsIntervalFree(vehicleBusy[candidateUID], slotStart, slotEnd) {
+					count++
+				}
+			}
+			if count > 0 {
+				slots = append(slots, models.VmsMasVehicleSuggestSlot{
+					StartDatetime:  models.TimeWithZone{Time: slotStart},
+					EndDatetime:    models.TimeWithZone{Time: slotEnd},
+					ShiftMinutes:   int(shift.Minutes()),
+					AvailableCount: count,
+				})
+				if len(slots) >= limit {
+					break
+				}
+			}
+		}
+	}
+
+	// Equivalent vehicles in other carpools for the original window
+	vehicles := make([]models.VmsMasVehicleSuggestVehicle, 0)
+	if carType != "" || seat > 0 {
+		var vehicleCanBookings []models.VmsMasVehicleCanBooking
+		err := config.DB.Raw(`SELECT * FROM fn_get_available_vehicles_view (?, ?, ?, ?)`,
+			StartTimeWithZone, EndTimeWithZone, bureauDeptSap, businessArea).Scan(&vehicleCanBookings).Error
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch available vehicles", "message": messages.ErrInternalServer.Error()})
+			return
+		}
+		masVehicleUIDs := make([]string, 0)
+		for _, vehicleCanBooking := range vehicleCanBookings {
+			if vehicleCanBooking.MasVehicleUID == masVehicleUID {
+				continue
+			}
+			if masCarpoolUID != "" && vehicleCanBooking.MasCarpoolUID == masCarpoolUID {
+				continue
+			}
+			masVehicleUIDs = append(masVehicleUIDs, vehicleCanBooking.MasVehicleUID)
+		}
+
+		query := config.DB.Table("vms_mas_vehicle v").Select("v.*,vd.vehicle_owner_dept_sap,vd.vehicle_pea_id,vd.fleet_card_no,cpv.mas_carpool_uid as carpool_uid,vi.vehicle_img_file vehicle_img,cp.carpool_name")
+		query = query.Joins("LEFT JOIN (SELECT DISTINCT ON (mas_vehicle_uid) * FROM vms_mas_vehicle_department WHERE is_deleted = '0' AND is_active = '1' ORDER BY mas_vehicle_uid, created_at DESC) vd ON v.mas_vehicle_uid = vd.mas_vehicle_uid")
+		query = query.Joins("LEFT JOIN (SELECT DISTINCT ON (mas_vehicle_uid) * FROM vms_mas_carpool_vehicle WHERE is_deleted = '0' AND is_active = '1' ORDER BY mas_vehicle_uid, created_at DESC) cpv ON cpv.mas_vehicle_uid = v.mas_vehicle_uid")
+		query = query.Joins("LEFT JOIN vms_mas_carpool cp ON cp.mas_carpool_uid = cpv.mas_carpool_uid")
+		query = query.Joins("LEFT JOIN (SELECT DISTINCT ON (mas_vehicle_uid) * FROM vms_mas_vehicle_img WHERE ref_vehicle_img_side_code = 1 ORDER BY mas_vehicle_uid, ref_vehicle_img_side_code) vi ON vi.mas_vehicle_uid = v.mas_vehicle_uid")
+		query = query.Where("v.mas_vehicle_uid IN (?) AND v.is_deleted = '0' AND v.is_active = '1'", masVehicleUIDs)
+		query = query.Where("(\"CarTypeDetail\" = ? OR v.seat = ?)", carType, seat)
+		query = query.Model(&models.VmsMasVehicleSuggestVehicle{}).Preload("RefFuelType")
+		if err := query.Find(&vehicles).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch available vehicles", "message": messages.ErrInternalServer.Error()})
+			return
+		}
+
+		// A different car type weighs more than any realistic seat difference
+		const carTypeMismatchScore = 100
+		for i := range vehicles {
+			funcs.TrimStringFields(&vehicles[i].VmsMasVehicleList)
+			vehicles[i].IsSameCarType = carType != "" && vehicles[i].CarType == carType
+			vehicles[i].IsSameSeat = seat > 0 && vehicles[i].Seat == seat
+			if !vehicles[i].IsSameCarType {
+				vehicles[i].DeviationScore += carTypeMismatchScore
+			}
+			if seat > 0 {
+				vehicles[i].DeviationScore += int(math.Abs(float64(vehicles[i].Seat - seat)))
+			}
+			if vehicles[i].CarpoolName != "" {
+				vehicles[i].VehicleOwnerDeptSAP = ""
+				vehicles[i].VehicleOwnerDeptShort = vehicles[i].CarpoolName + "(carpool)"
+			} else {
+				vehicles[i].VehicleOwnerDeptShort = funcs.GetDeptSAPShort(vehicles[i].VehicleOwnerDeptSAP)
+			}
+		}
+		sort.SliceStable(vehicles, func(i, j int) bool {
+			return vehicles[i].DeviationScore < vehicles[j].DeviationScore
+		})
+		if len(vehicles) > limit {
+			vehicles = vehicles[:limit]
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"slots":    slots,
+		"vehicles": vehicles,
+	})
+}
+
 // GetVehicle godoc
 // @Summary Retrieve details of a specific vehicle
 // @Description This endpoint allows a user to retrieve the details of a specific vehicle associated with a booking request.
@@ -849,6 +849,7 @@ func (h *VehicleInUseAdminHandler) GetTravelCard(c *gin.Context) {
 	request.VehicleUserImageURL = funcs.GetEmpImage(request.VehicleUserEmpID)
 	request.VehicleUserDeptSAPShort = request.VehicleUserPosition + " " + request.VehicleUserDeptSAPShort
 	request.ApprovedRequestDeptSAPShort = request.ApprovedRequestPosition + " " + request.ApprovedRequestDeptSAPShort
+	request.Passengers = funcs.GetRequestPassengers(request.TrnRequestUID)
 
 	c.JSON(http.StatusOK, request)
 }
@@ -935,6 +936,8 @@ func (h *VehicleInUseAdminHandler) ReturnedVehicle(c *gin.Context) {
 	}
 	funcs.UpdateVehicleMileage(request.TrnRequestUID, request.MileEnd)
 	funcs.UpdateVehicleParkingPlace(request.TrnRequestUID, request.ReturnedParkingPlace)
+	funcs.ReleaseRemainingReservation(request.TrnRequestUID, user.EmpID, "admin-department")
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -1053,3 +1056,168 @@ func (h *VehicleInUseAdminHandler) UpdateReceivedVehicleImages(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
+
+// GetRequestExtensions godoc
+// @Summary Retrieve extensions of a booking request
+// @Description This endpoint fetches the extension requests of a specific booking request.
+// @Tags Vehicle-in-use-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/vehicle-in-use-admin/extensions/{trn_request_uid} [get]
+func (h *VehicleInUseAdminHandler) GetRequestExtensions(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var reservation models.VmsTrnRequestReservation
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&reservation, "trn_request_uid = ?", c.Param("trn_request_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	var extensions []models.VmsTrnRequestExtension
+	if err := config.DB.Where("trn_request_uid = ? AND is_deleted = ?", reservation.TrnRequestUID, "0").
+		Order("created_at DESC").
+		Find(&extensions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	for i := range extensions {
+		extensions[i].ExtensionStatusName = funcs.ExtensionStatusNameMap[extensions[i].ExtensionStatusCode]
+	}
+	c.JSON(http.StatusOK, extensions)
+}
+
+// UpdateExtensionApproved godoc
+// @Summary Approve an extension of an active trip
+// @Description This endpoint allows the carpool admin to approve an extension. The vehicle and driver are checked again before the reserve end is moved.
+// @Tags Vehicle-in-use-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestExtensionApproved true "VmsTrnRequestExtensionApproved data"
+// @Router /api/vehicle-in-use-admin/update-extension-approved [put]
+func (h *VehicleInUseAdminHandler) UpdateExtensionApproved(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestExtensionApproved
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var extension models.VmsTrnRequestExtension
+	if err := config.DB.First(&extension, "trn_request_extension_uid = ? AND extension_status_code = ? AND is_deleted = ?", request.TrnRequestExtensionUID, "0", "0").Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Extension can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+	var reservation models.VmsTrnRequestReservation
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&reservation, "trn_request_uid = ? AND ref_request_status_code = ? AND is_deleted = ?", extension.TrnRequestUID, "60", "0").Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	available, err := funcs.CheckExtensionAvailable(reservation, extension.RequestedEndDatetime.Time)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if !available {
+		c.JSON(http.StatusConflict, gin.H{"error": "Vehicle or driver is not available for the extra time", "message": messages.ErrExtensionConflict.Error()})
+		return
+	}
+
+	err = funcs.ApplyRequestExtension(&extension, user.EmpID, "admin-department")
+	if err == messages.ErrExtensionChanged {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	extension.ExtensionStatusName = funcs.ExtensionStatusNameMap[extension.ExtensionStatusCode]
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": extension})
+}
+
+// UpdateExtensionRejected godoc
+// @Summary Reject an extension of an active trip
+// @Description This endpoint allows the carpool admin to reject an extension.
+// @Tags Vehicle-in-use-admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestExtensionRejected true "VmsTrnRequestExtensionRejected data"
+// @Router /api/vehicle-in-use-admin/update-extension-rejected [put]
+func (h *VehicleInUseAdminHandler) UpdateExtensionRejected(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestExtensionRejected
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var extension models.VmsTrnRequestExtension
+	if err := config.DB.First(&extension, "trn_request_extension_uid = ? AND extension_status_code = ? AND is_deleted = ?", request.TrnRequestExtensionUID, "0", "0").Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Extension can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+	var reservation models.VmsTrnRequestReservation
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&reservation, "trn_request_uid = ?", extension.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	empUser := funcs.GetUserEmpInfo(user.EmpID)
+	extension.ExtensionStatusCode = "2"
+	extension.RejectedExtensionReason = request.RejectedExtensionReason
+	extension.ApprovedEmpID = empUser.EmpID
+	extension.ApprovedEmpName = empUser.FullName
+	extension.ApprovedDatetime = models.TimeWithZone{Time: time.Now()}
+	extension.UpdatedAt = time.Now()
+	extension.UpdatedBy = user.EmpID
+	result := config.DB.Model(&models.VmsTrnRequestExtension{}).
+		Where("trn_request_extension_uid = ? AND extension_status_code = ?", extension.TrnRequestExtensionUID, "0").
+		UpdateColumns(map[string]interface{}{
+			"extension_status_code":     extension.ExtensionStatusCode,
+			"rejected_extension_reason": extension.RejectedExtensionReason,
+			"approved_emp_id":           extension.ApprovedEmpID,
+			"approved_emp_name":         extension.ApprovedEmpName,
+			"approved_datetime":         extension.ApprovedDatetime,
+			"updated_at":                extension.UpdatedAt,
+			"updated_by":                extension.UpdatedBy,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", result.Error), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": messages.ErrExtensionChanged.Error(), "message": messages.ErrExtensionChanged.Error()})
+		return
+	}
+	funcs.CreateTrnRequestEventLog(extension.TrnRequestUID,
+		reservation.RefRequestStatusCode,
+		"ไม่อนุมัติการขยายเวลาการใช้ยานพาหนะ",
+		user.EmpID,
+		"admin-department",
+		request.RejectedExtensionReason,
+	)
+	funcs.CreateRequestExtensionNotification(extension.TrnRequestExtensionUID)
+	extension.ExtensionStatusName = funcs.ExtensionStatusNameMap[extension.ExtensionStatusCode]
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": extension})
+}
@@ -789,6 +789,7 @@ func (h *VehicleInUseDriverHandler) GetTravelCard(c *gin.Context) {
 	request.VehicleUserImageURL = funcs.GetEmpImage(request.VehicleUserEmpID)
 	request.VehicleUserDeptSAPShort = request.VehicleUserPosition + " " + request.VehicleUserDeptSAPShort
 	request.ApprovedRequestDeptSAPShort = request.ApprovedRequestPosition + " " + request.ApprovedRequestDeptSAPShort
+	request.Passengers = funcs.GetRequestPassengers(request.TrnRequestUID)
 
 	c.JSON(http.StatusOK, request)
 }
@@ -874,6 +875,8 @@ func (h *VehicleInUseDriverHandler) ReturnedVehicle(c *gin.Context) {
 	}
 	funcs.UpdateVehicleMileage(request.TrnRequestUID, request.MileEnd)
 	funcs.UpdateVehicleParkingPlace(request.TrnRequestUID, request.ReturnedParkingPlace)
+	funcs.ReleaseRemainingReservation(request.TrnRequestUID, user.EmpID, "driver")
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -810,6 +810,7 @@ func (h *VehicleInUseUserHandler) GetTravelCard(c *gin.Context) {
 	request.VehicleUserImageURL = funcs.GetEmpImage(request.VehicleUserEmpID)
 	request.VehicleUserDeptSAPShort = request.VehicleUserPosition + " " + request.VehicleUserDeptSAPShort
 	request.ApprovedRequestDeptSAPShort = request.ApprovedRequestPosition + " " + request.ApprovedRequestDeptSAPShort
+	request.Passengers = funcs.GetRequestPassengers(request.TrnRequestUID)
 	c.JSON(http.StatusOK, request)
 }
 
@@ -972,5 +973,212 @@ func (h *VehicleInUseUserHandler) ReturnedVehicle(c *gin.Context) {
 	}
 	funcs.UpdateVehicleMileage(request.TrnRequestUID, request.MileEnd)
 	funcs.UpdateVehicleParkingPlace(request.TrnRequestUID, request.ReturnedParkingPlace)
+	funcs.ReleaseRemainingReservation(request.TrnRequestUID, user.EmpID, "vehicle-user")
+	funcs.PromoteWaitlist(request.TrnRequestUID)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
+
+// CreateRequestExtension godoc
+// @Summary Request more time for an active trip
+// @Description This endpoint allows to extend the reserve end of a trip in progress. The vehicle and driver must be free for the extra time; the extension is approved by the carpool admin or automatically under the carpool rule.
+// @Tags Vehicle-in-use-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestExtensionCreate true "VmsTrnRequestExtensionCreate data"
+// @Router /api/vehicle-in-use-user/create-extension [post]
+func (h *VehicleInUseUserHandler) CreateRequestExtension(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestExtensionCreate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var reservation models.VmsTrnRequestReservation
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&reservation, "trn_request_uid = ? AND ref_request_status_code = ? AND is_deleted = ?", request.TrnRequestUID, "60", "0").Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+	if !request.RequestedEndDatetime.After(reservation.ReserveEndDatetime.Time) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Requested end must be after the current reserve end", "message": messages.ErrInvalidDate.Error()})
+		return
+	}
+
+	var pendingCount int64
+	config.DB.Model(&models.VmsTrnRequestExtension{}).
+		Where("trn_request_uid = ? AND extension_status_code = ? AND is_deleted = ?", request.TrnRequestUID, "0", "0").
+		Count(&pendingCount)
+	if pendingCount > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Extension is waiting for approval", "message": messages.ErrExtensionPending.Error()})
+		return
+	}
+
+	available, err := funcs.CheckExtensionAvailable(reservation, request.RequestedEndDatetime.Time)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	if !available {
+		c.JSON(http.StatusConflict, gin.H{"error": "Vehicle or driver is not available for the extra time", "message": messages.ErrExtensionConflict.Error()})
+		return
+	}
+
+	empUser := funcs.GetUserEmpInfo(user.EmpID)
+	extension := models.VmsTrnRequestExtension{
+		TrnRequestExtensionUID: uuid.New().String(),
+		TrnRequestUID:          reservation.TrnRequestUID,
+		OriginalEndDatetime:    reservation.ReserveEndDatetime,
+		RequestedEndDatetime:   request.RequestedEndDatetime,
+		ExtensionReason:        request.ExtensionReason,
+		ExtensionStatusCode:    "0",
+		IsAutoApproved:         "0",
+		RequestedEmpID:         empUser.EmpID,
+		RequestedEmpName:       empUser.FullName,
+		CreatedAt:              time.Now(),
+		CreatedBy:              user.EmpID,
+		UpdatedAt:              time.Now(),
+		UpdatedBy:              user.EmpID,
+		IsDeleted:              "0",
+	}
+	if err := config.DB.Create(&extension).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	if funcs.IsExtensionAutoApprove(reservation, request.RequestedEndDatetime.Time) {
+		extension.IsAutoApproved = "1"
+		if err := funcs.ApplyRequestExtension(&extension, user.EmpID, "vehicle-user"); err == messages.ErrExtensionChanged {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "message": err.Error()})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+			return
+		}
+	} else {
+		funcs.CreateTrnRequestEventLog(extension.TrnRequestUID,
+			"60",
+			"ขอขยายเวลาการใช้ยานพาหนะ",
+			user.EmpID,
+			"vehicle-user",
+			funcs.GetDateTimeBuddhistYear(extension.OriginalEndDatetime.Time)+" - "+funcs.GetDateTimeBuddhistYear(extension.RequestedEndDatetime.Time)+" "+extension.ExtensionReason,
+		)
+		funcs.CreateRequestExtensionNotification(extension.TrnRequestExtensionUID)
+	}
+	extension.ExtensionStatusName = funcs.ExtensionStatusNameMap[extension.ExtensionStatusCode]
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Extension created successfully", "result": extension})
+}
+
+// GetRequestExtensions godoc
+// @Summary Retrieve extensions of a booking request
+// @Description This endpoint fetches the extension requests of a specific booking request.
+// @Tags Vehicle-in-use-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/vehicle-in-use-user/extensions/{trn_request_uid} [get]
+func (h *VehicleInUseUserHandler) GetRequestExtensions(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var reservation models.VmsTrnRequestReservation
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&reservation, "trn_request_uid = ?", c.Param("trn_request_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	var extensions []models.VmsTrnRequestExtension
+	if err := config.DB.Where("trn_request_uid = ? AND is_deleted = ?", reservation.TrnRequestUID, "0").
+		Order("created_at DESC").
+		Find(&extensions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+	for i := range extensions {
+		extensions[i].ExtensionStatusName = funcs.ExtensionStatusNameMap[extensions[i].ExtensionStatusCode]
+	}
+	c.JSON(http.StatusOK, extensions)
+}
+
+// GetPassengers godoc
+// @Summary Retrieve the passengers of a booking request
+// @Description This endpoint fetches the passenger list of a booking request.
+// @Tags Vehicle-in-use-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param trn_request_uid path string true "TrnRequestUID (trn_request_uid)"
+// @Router /api/vehicle-in-use-user/passengers/{trn_request_uid} [get]
+func (h *VehicleInUseUserHandler) GetPassengers(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestPassengerSeat
+	query := h.SetQueryRole(user, config.DB)
+	if err := query.First(&request, "trn_request_uid = ?", c.Param("trn_request_uid")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found", "message": messages.ErrBookingNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"passengers":    funcs.GetRequestPassengers(request.TrnRequestUID),
+		"seat_capacity": funcs.GetRequestSeatCapacity(request.TrnRequestUID),
+	})
+}
+
+// UpdatePassengers godoc
+// @Summary Update the passengers of a booking request
+// @Description This endpoint replaces the passenger list of a booking request until the trip starts. PEA employees are looked up by emp_id; guests need passenger_name and passenger_mobile_phone. The list must fit the seats of the vehicle.
+// @Tags Vehicle-in-use-user
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security AuthorizationAuth
+// @Param data body models.VmsTrnRequestPassengerUpdate true "VmsTrnRequestPassengerUpdate data"
+// @Router /api/vehicle-in-use-user/update-passengers [put]
+func (h *VehicleInUseUserHandler) UpdatePassengers(c *gin.Context) {
+	user := funcs.GetAuthenUser(c, h.Role)
+	if c.IsAborted() {
+		return
+	}
+	var request models.VmsTrnRequestPassengerUpdate
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": messages.ErrInvalidJSONInput.Error()})
+		return
+	}
+
+	var trnRequest models.VmsTrnRequestPassengerSeat
+	query := h.SetQueryRole(user, config.DB)
+	query = funcs.SetQueryPassengerCanUpdate(query)
+	if err := query.First(&trnRequest, "trn_request_uid = ?", request.TrnRequestUID).Error; err != nil {
+		c.JSON(http.StatusMethodNotAllowed, gin.H{"error": "Booking can not update", "message": messages.ErrBookingCannotUpdate.Error()})
+		return
+	}
+
+	beforePassengers := funcs.GetPassengerChangeValues(funcs.GetRequestPassengers(trnRequest.TrnRequestUID))
+	passengers, err := funcs.SaveRequestPassengers(trnRequest.TrnRequestUID, request.Passengers, user.EmpID)
+	if err == messages.ErrPassengerOverSeat || err == messages.ErrPassengerInvalid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update : %v", err), "message": messages.ErrInternalServer.Error()})
+		return
+	}
+
+	funcs.CreateTrnRequestChangeLog(trnRequest.TrnRequestUID, "passenger", beforePassengers, funcs.GetPassengerChangeValues(passengers), user.EmpID, "vehicle-user")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": passengers})
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,6 +10,7 @@ import (
 	"vms_plus_be/funcs"
 	"vms_plus_be/messages"
 	"vms_plus_be/models"
+	"vms_plus_be/permission"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -27,16 +27,7 @@ func (h *VehicleManagementHandler) SetQueryRole(user *models.AuthenUserEmp, quer
 }
 
 func (h *VehicleManagementHandler) SetQueryRoleDept(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
-	if slices.Contains(user.Roles, "admin-super") {
-		return query
-	}
-	if slices.Contains(user.Roles, "admin-region") {
-		return query.Where("d.bureau_ba like ?", user.BusinessArea[:1]+"%")
-	}
-	if slices.Contains(user.Roles, "admin-department") {
-		return query.Where("d.bureau_dept_sap = ?", user.BureauDeptSap)
-	}
-	return nil
+	return permission.SetQueryDepartmentScope(user, query, "d")
 }
 
 // SearchVehicles godoc
@@ -207,6 +198,7 @@ func (h *VehicleManagementHandler) UpdateVehicleIsActive(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Vehicle not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	before := funcs.GetMasterDataSnapshot("vms_mas_vehicle", "mas_vehicle_uid", vehicle.MasVehicleUID)
 	request.UpdatedAt = time.Now()
 	request.UpdatedBy = user.EmpID
 
@@ -219,6 +211,7 @@ func (h *VehicleManagementHandler) UpdateVehicleIsActive(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Vehicle not found", "message": messages.ErrNotfound.Error()})
 		return
 	}
+	funcs.AuditMasterDataRow(c, user, "vms_mas_vehicle", "mas_vehicle_uid", vehicle.MasVehicleUID, before)
 	c.JSON(http.StatusOK, gin.H{"message": "Updated successfully", "result": result})
 }
 
@@ -9,6 +9,7 @@ import (
 	_ "vms_plus_be/docs"
 	"vms_plus_be/funcs"
 	"vms_plus_be/handlers"
+	"vms_plus_be/permission"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -34,8 +35,13 @@ import (
 func main() {
 	config.InitConfig()
 	config.InitDB()
+	if err := funcs.InitJWTKeys(); err != nil {
+		log.Fatal("Failed to load JWT keys: ", err)
+	}
 	handlers.InitMinIO(config.AppConfig.MinIoEndPoint, config.AppConfig.MinIoAccessKey, config.AppConfig.MinIoSecretKey, true)
 	funcs.InitCronJob()
+	funcs.InitOTPProvider()
+	funcs.InitOTPLimiter()
 
 	router := gin.Default()
 	router.SetTrustedProxies([]string{"192.168.1.1", "192.168.1.2"})
@@ -49,6 +55,10 @@ func main() {
 	}))
 
 	// Initialize handler
+	//JWKSHandler
+	jwksHandler := handlers.JWKSHandler{}
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	//LoginHandler
 	loginHandler := handlers.LoginHandler{}
 	router.POST("/api/login/request-otp", funcs.ApiKeyMiddleware(), loginHandler.RequestOTP)
@@ -61,305 +71,444 @@ func main() {
 	router.GET("/api/login/profile", funcs.ApiKeyAuthenMiddleware(), loginHandler.Profile)
 	router.GET("/api/logout", funcs.ApiKeyAuthenMiddleware(), loginHandler.Logout)
 
+	//ActionLinkHandler
+	actionLinkHandler := handlers.ActionLinkHandler{}
+	router.GET("/api/action-link/verify", funcs.ApiKeyMiddleware(), actionLinkHandler.VerifyActionLink)
+	router.POST("/api/action-link/apply", funcs.ApiKeyMiddleware(), actionLinkHandler.ApplyActionLink)
+
 	//VehicleHandler
-	vehicleHandler := handlers.VehicleHandler{Role: "*"}
-	router.GET("/api/vehicle/search", funcs.ApiKeyAuthenMiddleware(), vehicleHandler.SearchVehicles)
-	router.GET("/api/vehicle/search-booking", funcs.ApiKeyAuthenMiddleware(), vehicleHandler.SearchBookingVehicles)
-	router.GET("/api/vehicle/search-booking-carpool", funcs.ApiKeyAuthenMiddleware(), vehicleHandler.SearchBookingVehiclesCarpool)
-	router.GET("/api/vehicle/types", funcs.ApiKeyAuthenMiddleware(), vehicleHandler.GetTypes)
-	router.GET("/api/vehicle/departments", funcs.ApiKeyAuthenMiddleware(), vehicleHandler.GetDepartments)
-	router.GET("/api/vehicle/:mas_vehicle_uid", funcs.ApiKeyAuthenMiddleware(), vehicleHandler.GetVehicle)
-	router.GET("/api/vehicle-info", funcs.ApiKeyAuthenMiddleware(), vehicleHandler.GetVehicleInfo)
-	router.GET("/api/vehicle/car-types-by-detail", funcs.ApiKeyAuthenMiddleware(), vehicleHandler.GetCarTypeDetails)
+	vehicleHandler := handlers.VehicleHandler{Role: permission.GetRoles(permission.Authenticated)}
+	router.GET("/api/vehicle/search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.SearchVehicles)
+	router.GET("/api/vehicle/search-booking", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.SearchBookingVehicles)
+	router.GET("/api/vehicle/search-booking-carpool", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.SearchBookingVehiclesCarpool)
+	router.GET("/api/vehicle/search-booking-suggestions", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.SearchBookingSuggestions)
+	router.GET("/api/vehicle/types", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.GetTypes)
+	router.GET("/api/vehicle/departments", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.GetDepartments)
+	router.GET("/api/vehicle/:mas_vehicle_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.GetVehicle)
+	router.GET("/api/vehicle-info", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.GetVehicleInfo)
+	router.GET("/api/vehicle/car-types-by-detail", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), vehicleHandler.GetCarTypeDetails)
 
 	//DriverHandler
-	driverHandler := handlers.DriverHandler{Role: "*"}
-	router.GET("/api/driver/search", funcs.ApiKeyAuthenMiddleware(), driverHandler.GetDrivers)
-	router.GET("/api/driver/search-booking", funcs.ApiKeyAuthenMiddleware(), driverHandler.GetBookingDrivers)
-	router.GET("/api/driver/:mas_driver_uid", funcs.ApiKeyAuthenMiddleware(), driverHandler.GetDriver)
-	router.GET("/api/driver/search-other-dept", funcs.ApiKeyAuthenMiddleware(), driverHandler.GetDriversOtherDept)
-	router.GET("/api/driver/work-type", funcs.ApiKeyAuthenMiddleware(), driverHandler.GetWorkType)
+	driverHandler := handlers.DriverHandler{Role: permission.GetRoles(permission.Authenticated)}
+	router.GET("/api/driver/search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), driverHandler.GetDrivers)
+	router.GET("/api/driver/search-booking", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), driverHandler.GetBookingDrivers)
+	router.GET("/api/driver/:mas_driver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), driverHandler.GetDriver)
+	router.GET("/api/driver/search-other-dept", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), driverHandler.GetDriversOtherDept)
+	router.GET("/api/driver/work-type", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), driverHandler.GetWorkType)
 
 	//BookingUserHandler
-	bookingUserHandler := handlers.BookingUserHandler{Role: "vehicle-user"}
-	router.GET("/api/booking-user/menu-requests", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.MenuRequests)
-	router.POST("/api/booking-user/create-request", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.CreateRequest)
-	router.GET("/api/booking-user/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.GetRequest)
-	router.PUT("/api/booking-user/update-vehicle-user", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdateVehicleUser)
-	router.PUT("/api/booking-user/update-trip", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdateTrip)
-	router.PUT("/api/booking-user/update-pickup", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdatePickup)
-	router.PUT("/api/booking-user/update-document", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdateDocument)
-	router.PUT("/api/booking-user/update-cost", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdateCost)
-	router.PUT("/api/booking-user/update-vehicle-type", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdateVehicleType)
-	router.PUT("/api/booking-user/update-confirmer", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdateConfirmer)
-	router.GET("/api/booking-user/search-requests", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.SearchRequests)
-	router.PUT("/api/booking-user/update-canceled", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdateCanceled)
-	router.PUT("/api/booking-user/update-resend", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.UpdateResend)
-	router.GET("/api/booking-user/export-requests", funcs.ApiKeyAuthenMiddleware(), bookingUserHandler.ExportRequests)
+	bookingUserHandler := handlers.BookingUserHandler{Role: permission.GetRoles(permission.BookingRequest)}
+	router.GET("/api/booking-user/menu-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.MenuRequests)
+	router.POST("/api/booking-user/create-request", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.CreateRequest)
+	router.POST("/api/booking-user/create-draft", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.CreateDraft)
+	router.PUT("/api/booking-user/update-draft", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateDraft)
+	router.GET("/api/booking-user/validate-draft/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.ValidateDraft)
+	router.PUT("/api/booking-user/update-submit-draft", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateSubmitDraft)
+	router.DELETE("/api/booking-user/delete-draft/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.DeleteDraft)
+	router.POST("/api/booking-user/create-duplicate-request", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.CreateDuplicateRequest)
+	router.POST("/api/booking-user/create-draft-from-template", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.CreateDraftFromTemplate)
+	router.GET("/api/booking-user/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.GetRequest)
+	router.PUT("/api/booking-user/update-vehicle-user", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateVehicleUser)
+	router.PUT("/api/booking-user/update-trip", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateTrip)
+	router.PUT("/api/booking-user/update-pickup", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdatePickup)
+	router.PUT("/api/booking-user/update-document", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateDocument)
+	router.PUT("/api/booking-user/update-cost", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateCost)
+	router.PUT("/api/booking-user/update-vehicle-type", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateVehicleType)
+	router.PUT("/api/booking-user/update-confirmer", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateConfirmer)
+	router.GET("/api/booking-user/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.SearchRequests)
+	router.PUT("/api/booking-user/update-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateCanceled)
+	router.PUT("/api/booking-user/update-resend", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.UpdateResend)
+	router.GET("/api/booking-user/export-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.ExportRequests)
+	router.GET("/api/booking-user/attachments/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.GetAttachments)
+	router.POST("/api/booking-user/add-attachment", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.AddAttachment)
+	router.DELETE("/api/booking-user/delete-attachment/:trn_request_attachment_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingUserHandler.DeleteAttachment)
+
+	//BookingWaitlistHandler
+	bookingWaitlistHandler := handlers.BookingWaitlistHandler{Role: permission.GetRoles(permission.BookingRequest)}
+	router.POST("/api/booking-waitlist/create-waitlist", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingWaitlistHandler.CreateWaitlist)
+	router.GET("/api/booking-waitlist/search-waitlists", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingWaitlistHandler.SearchWaitlists)
+	router.GET("/api/booking-waitlist/waitlist/:trn_waitlist_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingWaitlistHandler.GetWaitlist)
+	router.PUT("/api/booking-waitlist/update-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingWaitlistHandler.UpdateCanceled)
+
+	//BookingTemplateHandler
+	bookingTemplateHandler := handlers.BookingTemplateHandler{Role: permission.GetRoles(permission.BookingRequest)}
+	router.POST("/api/booking-template/create-template", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingTemplateHandler.CreateTemplate)
+	router.GET("/api/booking-template/search-templates", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingTemplateHandler.SearchTemplates)
+	router.GET("/api/booking-template/template/:trn_request_template_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingTemplateHandler.GetTemplate)
+	router.PUT("/api/booking-template/update-template", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingTemplateHandler.UpdateTemplate)
+	router.DELETE("/api/booking-template/delete-template/:trn_request_template_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), bookingTemplateHandler.DeleteTemplate)
+
+	//DelegationHandler
+	delegationHandler := handlers.DelegationHandler{Role: permission.GetRoles(permission.DelegationManage)}
+	router.POST("/api/delegation/create-delegation", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DelegationManage), delegationHandler.CreateDelegation)
+	router.GET("/api/delegation/search-delegations", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DelegationManage), delegationHandler.SearchDelegations)
+	router.DELETE("/api/delegation/delete-delegation/:trn_delegation_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DelegationManage), delegationHandler.DeleteDelegation)
+
+	//CommentHandler
+	commentHandler := handlers.CommentHandler{Role: permission.GetRoles(permission.Authenticated)}
+	router.GET("/api/comment/comments/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), commentHandler.ListComments)
+	router.GET("/api/comment/participants/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), commentHandler.ListCommentParticipants)
+	router.POST("/api/comment/create-comment", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), commentHandler.CreateComment)
+	router.DELETE("/api/comment/delete-comment/:trn_request_comment_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), commentHandler.DeleteComment)
+
+	//AuditLogHandler
+	auditLogHandler := handlers.AuditLogHandler{Role: permission.GetRoles(permission.AuditLogRead)}
+	router.GET("/api/audit-log/search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AuditLogRead), auditLogHandler.SearchAuditLogs)
+	router.GET("/api/audit-log/export", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AuditLogRead), auditLogHandler.ExportAuditLogs)
+
+	//PermissionHandler
+	permissionHandler := handlers.PermissionHandler{Role: permission.GetRoles(permission.PermissionRead)}
+	router.GET("/api/permission/employee/:emp_id", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.PermissionRead), permissionHandler.GetEmployeePermission)
+
+	//RegionAdminHandler
+	regionAdminHandler := handlers.RegionAdminHandler{Role: permission.GetRoles(permission.RegionAdminManage)}
+	router.GET("/api/region-admin/search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RegionAdminManage), regionAdminHandler.SearchRegionAdmins)
+	router.POST("/api/region-admin/create", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RegionAdminManage), regionAdminHandler.CreateRegionAdmin)
+	router.PUT("/api/region-admin/update/:mas_region_admin_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RegionAdminManage), regionAdminHandler.UpdateRegionAdmin)
+	router.DELETE("/api/region-admin/delete/:mas_region_admin_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RegionAdminManage), regionAdminHandler.DeleteRegionAdmin)
+
+	//SessionHandler
+	sessionHandler := handlers.SessionHandler{Role: permission.GetRoles(permission.Authenticated)}
+	router.GET("/api/session/my-sessions", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), sessionHandler.GetMySessions)
+	router.DELETE("/api/session/terminate/:session_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), sessionHandler.TerminateMySession)
+
+	//SessionAdminHandler
+	sessionAdminHandler := handlers.SessionAdminHandler{Role: permission.GetRoles(permission.SessionManage)}
+	router.GET("/api/session-admin/employee/:emp_id", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.SessionManage), sessionAdminHandler.GetEmployeeSessions)
+	router.PUT("/api/session-admin/revoke/:session_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.SessionManage), sessionAdminHandler.RevokeSession)
+	router.PUT("/api/session-admin/revoke-employee/:emp_id", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.SessionManage), sessionAdminHandler.RevokeEmployeeSessions)
+
+	//ApiClientHandler
+	apiClientHandler := handlers.ApiClientHandler{Role: permission.GetRoles(permission.ApiClientManage)}
+	router.GET("/api/api-client/search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.ApiClientManage), apiClientHandler.SearchApiClients)
+	router.POST("/api/api-client/create", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.ApiClientManage), apiClientHandler.CreateApiClient)
+	router.PUT("/api/api-client/update/:mas_api_client_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.ApiClientManage), apiClientHandler.UpdateApiClient)
+	router.DELETE("/api/api-client/delete/:mas_api_client_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.ApiClientManage), apiClientHandler.DeleteApiClient)
+	router.POST("/api/api-client/create-key/:mas_api_client_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.ApiClientManage), apiClientHandler.CreateApiClientKey)
+	router.POST("/api/api-client/rotate-key/:mas_api_client_key_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.ApiClientManage), apiClientHandler.RotateApiClientKey)
+	router.DELETE("/api/api-client/revoke-key/:mas_api_client_key_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.ApiClientManage), apiClientHandler.RevokeApiClientKey)
+	router.GET("/api/api-client/usage", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.ApiClientManage), apiClientHandler.GetApiClientUsage)
+
+	//ImpersonationHandler
+	impersonationHandler := handlers.ImpersonationHandler{Role: permission.GetRoles(permission.Impersonate)}
+	router.POST("/api/impersonation/start", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Impersonate), impersonationHandler.StartImpersonation)
+	impersonationStopHandler := handlers.ImpersonationHandler{Role: permission.GetRoles(permission.Authenticated)}
+	router.POST("/api/impersonation/stop", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.Authenticated), impersonationStopHandler.StopImpersonation)
+
+	//AutoApprovalAdminHandler
+	autoApprovalAdminHandler := handlers.AutoApprovalAdminHandler{Role: permission.GetRoles(permission.AutoApprovalManage)}
+	router.GET("/api/auto-approval-admin/search-rules", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AutoApprovalManage), autoApprovalAdminHandler.SearchAutoApprovalRules)
+	router.POST("/api/auto-approval-admin/create-rule", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AutoApprovalManage), autoApprovalAdminHandler.CreateAutoApprovalRule)
+	router.PUT("/api/auto-approval-admin/update-rule", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AutoApprovalManage), autoApprovalAdminHandler.UpdateAutoApprovalRule)
+	router.DELETE("/api/auto-approval-admin/delete-rule/:mas_auto_approval_rule_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AutoApprovalManage), autoApprovalAdminHandler.DeleteAutoApprovalRule)
+	router.POST("/api/auto-approval-admin/dry-run", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AutoApprovalManage), autoApprovalAdminHandler.DryRunAutoApprovalRule)
+	router.GET("/api/auto-approval-admin/search-auto-approvals", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AutoApprovalManage), autoApprovalAdminHandler.SearchAutoApprovals)
+	router.PUT("/api/auto-approval-admin/revert/:trn_request_auto_approval_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.AutoApprovalManage), autoApprovalAdminHandler.RevertAutoApproval)
+
+	//SlaAdminHandler
+	slaAdminHandler := handlers.SlaAdminHandler{Role: permission.GetRoles(permission.SlaManage)}
+	router.GET("/api/sla-admin/search-slas", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.SlaManage), slaAdminHandler.SearchSlas)
+	router.POST("/api/sla-admin/create-sla", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.SlaManage), slaAdminHandler.CreateSla)
+	router.PUT("/api/sla-admin/update-sla", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.SlaManage), slaAdminHandler.UpdateSla)
+	router.DELETE("/api/sla-admin/delete-sla/:mas_sla_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.SlaManage), slaAdminHandler.DeleteSla)
+	router.GET("/api/sla-admin/report-breach", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.SlaManage), slaAdminHandler.ReportSlaBreach)
+
+	//RideShareAdminHandler
+	rideShareAdminHandler := handlers.RideShareAdminHandler{Role: permission.GetRoles(permission.RideShareManage)}
+	router.POST("/api/ride-share-admin/create-proposals", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RideShareManage), rideShareAdminHandler.CreateProposals)
+	router.GET("/api/ride-share-admin/search-proposals", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RideShareManage), rideShareAdminHandler.SearchProposals)
+	router.GET("/api/ride-share-admin/proposal/:trn_ride_share_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RideShareManage), rideShareAdminHandler.GetProposal)
+	router.PUT("/api/ride-share-admin/update-accepted", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RideShareManage), rideShareAdminHandler.UpdateAccepted)
+	router.PUT("/api/ride-share-admin/update-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.RideShareManage), rideShareAdminHandler.UpdateRejected)
 
 	//BookingConfirmerHandler
-	bookingConfirmerHandler := handlers.BookingConfirmerHandler{Role: "level1-approval"}
-	router.GET("/api/booking-confirmer/menu-requests", funcs.ApiKeyAuthenMiddleware(), bookingConfirmerHandler.MenuRequests)
-	router.GET("/api/booking-confirmer/mmenu-requests", funcs.ApiKeyAuthenMiddleware(), bookingConfirmerHandler.MenuRequests)
-	router.GET("/api/booking-confirmer/search-requests", funcs.ApiKeyAuthenMiddleware(), bookingConfirmerHandler.SearchRequests)
-	router.GET("/api/booking-confirmer/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), bookingConfirmerHandler.GetRequest)
-	router.PUT("/api/booking-confirmer/update-rejected", funcs.ApiKeyAuthenMiddleware(), bookingConfirmerHandler.UpdateRejected)
-	router.PUT("/api/booking-confirmer/update-approved", funcs.ApiKeyAuthenMiddleware(), bookingConfirmerHandler.UpdateApproved)
-	router.PUT("/api/booking-confirmer/update-canceled", funcs.ApiKeyAuthenMiddleware(), bookingConfirmerHandler.UpdateCanceled)
-	router.GET("/api/booking-confirmer/export-requests", funcs.ApiKeyAuthenMiddleware(), bookingConfirmerHandler.ExportRequests)
+	bookingConfirmerHandler := handlers.BookingConfirmerHandler{Role: permission.GetRoles(permission.BookingConfirm)}
+	router.GET("/api/booking-confirmer/menu-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.MenuRequests)
+	router.GET("/api/booking-confirmer/mmenu-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.MenuRequests)
+	router.GET("/api/booking-confirmer/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.SearchRequests)
+	router.GET("/api/booking-confirmer/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.GetRequest)
+	router.PUT("/api/booking-confirmer/update-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.UpdateRejected)
+	router.PUT("/api/booking-confirmer/update-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.UpdateApproved)
+	router.PUT("/api/booking-confirmer/update-bulk-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.UpdateBulkApproved)
+	router.PUT("/api/booking-confirmer/update-bulk-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.UpdateBulkRejected)
+	router.PUT("/api/booking-confirmer/update-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.UpdateCanceled)
+	router.GET("/api/booking-confirmer/export-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingConfirm), bookingConfirmerHandler.ExportRequests)
 
 	//BookingAdminHandler
-	bookinAdminHandler := handlers.BookingAdminHandler{Role: "admin-department,admin-carpool,admin-department-main"}
-	router.GET("/api/booking-admin/menu-requests", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.MenuRequests)
-	router.GET("/api/booking-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.SearchRequests)
-	router.GET("/api/booking-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.GetRequest)
-	router.PUT("/api/booking-admin/update-sended-back", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateRejected)
-	router.PUT("/api/booking-admin/update-approved", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateApproved)
-	router.PUT("/api/booking-admin/update-canceled", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateCanceled)
-	router.PUT("/api/booking-admin/update-rejected", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateRejected)
-	router.PUT("/api/booking-admin/update-vehicle-user", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateVehicleUser)
-	router.PUT("/api/booking-admin/update-trip", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateTrip)
-	router.PUT("/api/booking-admin/update-pickup", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdatePickup)
-	router.PUT("/api/booking-admin/update-document", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateDocument)
-	router.PUT("/api/booking-admin/update-cost", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateCost)
-	router.PUT("/api/booking-admin/update-vehicle", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateVehicle)
-	router.PUT("/api/booking-admin/update-driver", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.UpdateDriver)
-	router.GET("/api/booking-admin/export-requests", funcs.ApiKeyAuthenMiddleware(), bookinAdminHandler.ExportRequests)
+	bookinAdminHandler := handlers.BookingAdminHandler{Role: permission.GetRoles(permission.BookingAdmin)}
+	router.GET("/api/booking-admin/menu-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.MenuRequests)
+	router.GET("/api/booking-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.SearchRequests)
+	router.GET("/api/booking-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.GetRequest)
+	router.PUT("/api/booking-admin/update-sended-back", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateRejected)
+	router.PUT("/api/booking-admin/update-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateApproved)
+	router.PUT("/api/booking-admin/update-bulk-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateBulkApproved)
+	router.PUT("/api/booking-admin/update-bulk-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateBulkRejected)
+	router.PUT("/api/booking-admin/update-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateCanceled)
+	router.PUT("/api/booking-admin/update-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateRejected)
+	router.PUT("/api/booking-admin/update-vehicle-user", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateVehicleUser)
+	router.PUT("/api/booking-admin/update-trip", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateTrip)
+	router.PUT("/api/booking-admin/update-pickup", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdatePickup)
+	router.PUT("/api/booking-admin/update-document", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateDocument)
+	router.PUT("/api/booking-admin/update-cost", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateCost)
+	router.PUT("/api/booking-admin/update-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateVehicle)
+	router.PUT("/api/booking-admin/update-driver", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdateDriver)
+	router.GET("/api/booking-admin/export-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.ExportRequests)
+	router.GET("/api/booking-admin/passengers/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.GetPassengers)
+	router.PUT("/api/booking-admin/update-passengers", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.UpdatePassengers)
+	router.GET("/api/booking-admin/attachments/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.GetAttachments)
+	router.POST("/api/booking-admin/add-attachment", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.AddAttachment)
+	router.DELETE("/api/booking-admin/delete-attachment/:trn_request_attachment_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), bookinAdminHandler.DeleteAttachment)
 
 	//BookingFinalHandler
-	bookingFinalHandler := handlers.BookingFinalHandler{Role: "approval-department,approval-carpool"}
-	router.GET("/api/booking-final/menu-requests", funcs.ApiKeyAuthenMiddleware(), bookingFinalHandler.MenuRequests)
-	router.GET("/api/booking-final/search-requests", funcs.ApiKeyAuthenMiddleware(), bookingFinalHandler.SearchRequests)
-	router.GET("/api/booking-final/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), bookingFinalHandler.GetRequest)
-	router.PUT("/api/booking-final/update-rejected", funcs.ApiKeyAuthenMiddleware(), bookingFinalHandler.UpdateRejected)
-	router.PUT("/api/booking-final/update-approved", funcs.ApiKeyAuthenMiddleware(), bookingFinalHandler.UpdateApproved)
-	router.PUT("/api/booking-final/update-canceled", funcs.ApiKeyAuthenMiddleware(), bookingFinalHandler.UpdateCanceled)
-	router.GET("/api/booking-final/export-requests", funcs.ApiKeyAuthenMiddleware(), bookingFinalHandler.ExportRequests)
+	bookingFinalHandler := handlers.BookingFinalHandler{Role: permission.GetRoles(permission.BookingApprove)}
+	router.GET("/api/booking-final/menu-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.MenuRequests)
+	router.GET("/api/booking-final/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.SearchRequests)
+	router.GET("/api/booking-final/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.GetRequest)
+	router.PUT("/api/booking-final/update-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.UpdateRejected)
+	router.PUT("/api/booking-final/update-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.UpdateApproved)
+	router.PUT("/api/booking-final/update-bulk-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.UpdateBulkApproved)
+	router.PUT("/api/booking-final/update-bulk-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.UpdateBulkRejected)
+	router.PUT("/api/booking-final/update-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.UpdateCanceled)
+	router.GET("/api/booking-final/export-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingApprove), bookingFinalHandler.ExportRequests)
 
 	//ReceivedKeyUserHandler
-	receivedKeyUserHandler := handlers.ReceivedKeyUserHandler{Role: "vehicle-user"}
-	router.GET("/api/received-key-user/search-requests", funcs.ApiKeyAuthenMiddleware(), receivedKeyUserHandler.SearchRequests)
-	router.GET("/api/received-key-user/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedKeyUserHandler.GetRequest)
-	router.PUT("/api/received-key-user/update-key-pickup-pea", funcs.ApiKeyAuthenMiddleware(), receivedKeyUserHandler.UpdateKeyPickupPEA)
-	router.PUT("/api/received-key-user/update-key-pickup-outsider", funcs.ApiKeyAuthenMiddleware(), receivedKeyUserHandler.UpdateKeyPickupOutSider)
-	router.PUT("/api/received-key-user/update-key-pickup-driver", funcs.ApiKeyAuthenMiddleware(), receivedKeyUserHandler.UpdateKeyPickupDriver)
-	router.PUT("/api/received-key-user/update-canceled", funcs.ApiKeyAuthenMiddleware(), receivedKeyUserHandler.UpdateCanceled)
-	router.PUT("/api/received-key-user/update-recieived-key-confirmed", funcs.ApiKeyAuthenMiddleware(), receivedKeyUserHandler.UpdateRecieivedKeyConfirmed)
+	receivedKeyUserHandler := handlers.ReceivedKeyUserHandler{Role: permission.GetRoles(permission.BookingRequest)}
+	router.GET("/api/received-key-user/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedKeyUserHandler.SearchRequests)
+	router.GET("/api/received-key-user/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedKeyUserHandler.GetRequest)
+	router.PUT("/api/received-key-user/update-key-pickup-pea", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedKeyUserHandler.UpdateKeyPickupPEA)
+	router.PUT("/api/received-key-user/update-key-pickup-outsider", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedKeyUserHandler.UpdateKeyPickupOutSider)
+	router.PUT("/api/received-key-user/update-key-pickup-driver", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedKeyUserHandler.UpdateKeyPickupDriver)
+	router.PUT("/api/received-key-user/update-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedKeyUserHandler.UpdateCanceled)
+	router.PUT("/api/received-key-user/update-recieived-key-confirmed", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedKeyUserHandler.UpdateRecieivedKeyConfirmed)
 
 	//ReceivedKeyAdminHandler
-	receivedKeyAdminHandler := handlers.ReceivedKeyAdminHandler{Role: "admin-department,admin-carpool,admin-department-main"}
-	router.GET("/api/received-key-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), receivedKeyAdminHandler.SearchRequests)
-	router.GET("/api/received-key-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedKeyAdminHandler.GetRequest)
-	router.PUT("/api/received-key-admin/update-recieived-key", funcs.ApiKeyAuthenMiddleware(), receivedKeyAdminHandler.UpdateRecieivedKey)
-	router.PUT("/api/received-key-admin/update-key-pickup-pea", funcs.ApiKeyAuthenMiddleware(), receivedKeyAdminHandler.UpdateKeyPickupPEA)
-	router.PUT("/api/received-key-admin/update-key-pickup-outsider", funcs.ApiKeyAuthenMiddleware(), receivedKeyAdminHandler.UpdateKeyPickupOutSider)
-	router.PUT("/api/received-key-admin/update-key-pickup-driver", funcs.ApiKeyAuthenMiddleware(), receivedKeyAdminHandler.UpdateKeyPickupDriver)
-	router.PUT("/api/received-key-admin/update-canceled", funcs.ApiKeyAuthenMiddleware(), receivedKeyAdminHandler.UpdateCanceled)
-	router.PUT("/api/received-key-admin/update-recieived-key-detail", funcs.ApiKeyAuthenMiddleware(), receivedKeyAdminHandler.UpdateRecieivedKeyDetail)
+	receivedKeyAdminHandler := handlers.ReceivedKeyAdminHandler{Role: permission.GetRoles(permission.BookingAdmin)}
+	router.GET("/api/received-key-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedKeyAdminHandler.SearchRequests)
+	router.GET("/api/received-key-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedKeyAdminHandler.GetRequest)
+	router.PUT("/api/received-key-admin/update-recieived-key", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedKeyAdminHandler.UpdateRecieivedKey)
+	router.PUT("/api/received-key-admin/update-key-pickup-pea", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedKeyAdminHandler.UpdateKeyPickupPEA)
+	router.PUT("/api/received-key-admin/update-key-pickup-outsider", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedKeyAdminHandler.UpdateKeyPickupOutSider)
+	router.PUT("/api/received-key-admin/update-key-pickup-driver", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedKeyAdminHandler.UpdateKeyPickupDriver)
+	router.PUT("/api/received-key-admin/update-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedKeyAdminHandler.UpdateCanceled)
+	router.PUT("/api/received-key-admin/update-recieived-key-detail", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedKeyAdminHandler.UpdateRecieivedKeyDetail)
 
 	//ReceivedKeyDriverHandler
-	receivedKeyDriverHandler := handlers.ReceivedKeyDriverHandler{Role: "driver"}
-	router.GET("/api/received-key-driver/search-requests", funcs.ApiKeyAuthenMiddleware(), receivedKeyDriverHandler.SearchRequests)
-	router.GET("/api/received-key-driver/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedKeyDriverHandler.GetRequest)
-	router.PUT("/api/received-key-driver/update-recieived-key-confirmed", funcs.ApiKeyAuthenMiddleware(), receivedKeyDriverHandler.UpdateRecieivedKeyConfirmed)
-	router.GET("/api/booking-driver/menu-requests", funcs.ApiKeyAuthenMiddleware(), receivedKeyDriverHandler.MenuRequests)
+	receivedKeyDriverHandler := handlers.ReceivedKeyDriverHandler{Role: permission.GetRoles(permission.DriverJob)}
+	router.GET("/api/received-key-driver/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DriverJob), receivedKeyDriverHandler.SearchRequests)
+	router.GET("/api/received-key-driver/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DriverJob), receivedKeyDriverHandler.GetRequest)
+	router.PUT("/api/received-key-driver/update-recieived-key-confirmed", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DriverJob), receivedKeyDriverHandler.UpdateRecieivedKeyConfirmed)
+	router.GET("/api/booking-driver/menu-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DriverJob), receivedKeyDriverHandler.MenuRequests)
 
 	//ReceivedVehicleUserHandler
-	receivedVehicleUserHandler := handlers.ReceivedVehicleUserHandler{Role: "vehicle-user"}
-	router.GET("/api/received-vehicle-user/search-requests", funcs.ApiKeyAuthenMiddleware(), receivedVehicleUserHandler.SearchRequests)
-	router.GET("/api/received-vehicle-user/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedVehicleUserHandler.GetRequest)
-	router.PUT("/api/received-vehicle-user/received-vehicle", funcs.ApiKeyAuthenMiddleware(), receivedVehicleUserHandler.ReceivedVehicle)
-	router.GET("/api/received-vehicle-user/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedVehicleUserHandler.GetTravelCard)
+	receivedVehicleUserHandler := handlers.ReceivedVehicleUserHandler{Role: permission.GetRoles(permission.BookingRequest)}
+	router.GET("/api/received-vehicle-user/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedVehicleUserHandler.SearchRequests)
+	router.GET("/api/received-vehicle-user/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedVehicleUserHandler.GetRequest)
+	router.PUT("/api/received-vehicle-user/received-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedVehicleUserHandler.ReceivedVehicle)
+	router.GET("/api/received-vehicle-user/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), receivedVehicleUserHandler.GetTravelCard)
 
 	//ReceivedVehicleAdminHandler
-	receivedVehicleAdminHandler := handlers.ReceivedVehicleAdminHandler{Role: "admin-department,admin-carpool,admin-department-main"}
-	router.GET("/api/received-vehicle-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), receivedVehicleAdminHandler.SearchRequests)
-	router.GET("/api/received-vehicle-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedVehicleAdminHandler.GetRequest)
-	router.PUT("/api/received-vehicle-admin/received-vehicle", funcs.ApiKeyAuthenMiddleware(), receivedVehicleAdminHandler.ReceivedVehicle)
-	router.GET("/api/received-vehicle-admin/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedVehicleAdminHandler.GetTravelCard)
+	receivedVehicleAdminHandler := handlers.ReceivedVehicleAdminHandler{Role: permission.GetRoles(permission.BookingAdmin)}
+	router.GET("/api/received-vehicle-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedVehicleAdminHandler.SearchRequests)
+	router.GET("/api/received-vehicle-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedVehicleAdminHandler.GetRequest)
+	router.PUT("/api/received-vehicle-admin/received-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedVehicleAdminHandler.ReceivedVehicle)
+	router.GET("/api/received-vehicle-admin/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), receivedVehicleAdminHandler.GetTravelCard)
 
 	//ReceivedVehicleDriverHandler
-	receivedVehicleDriverHandler := handlers.ReceivedVehicleDriverHandler{Role: "driver"}
-	router.GET("/api/received-vehicle-driver/search-requests", funcs.ApiKeyAuthenMiddleware(), receivedVehicleDriverHandler.SearchRequests)
-	router.GET("/api/received-vehicle-driver/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedVehicleDriverHandler.GetRequest)
-	router.PUT("/api/received-vehicle-driver/received-vehicle", funcs.ApiKeyAuthenMiddleware(), receivedVehicleDriverHandler.ReceivedVehicle)
-	router.GET("/api/received-vehicle-driver/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), receivedVehicleDriverHandler.GetTravelCard)
+	receivedVehicleDriverHandler := handlers.ReceivedVehicleDriverHandler{Role: permission.GetRoles(permission.DriverJob)}
+	router.GET("/api/received-vehicle-driver/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DriverJob), receivedVehicleDriverHandler.SearchRequests)
+	router.GET("/api/received-vehicle-driver/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DriverJob), receivedVehicleDriverHandler.GetRequest)
+	router.PUT("/api/received-vehicle-driver/received-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DriverJob), receivedVehicleDriverHandler.ReceivedVehicle)
+	router.GET("/api/received-vehicle-driver/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.DriverJob), receivedVehicleDriverHandler.GetTravelCard)
 
 	//VehicleInUseUserHandler
-	vehicleInUseUserHandler := handlers.VehicleInUseUserHandler{Role: "vehicle-user"}
-	router.GET("/api/vehicle-in-use-user/search-requests", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.SearchRequests)
-	router.GET("/api/vehicle-in-use-user/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.GetRequest)
-	router.GET("/api/vehicle-in-use-user/travel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.GetVehicleTripDetails)
-	router.GET("/api/vehicle-in-use-user/travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.GetVehicleTripDetail)
-	router.POST("/api/vehicle-in-use-user/create-travel-detail", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.CreateVehicleTripDetail)
-	router.PUT("/api/vehicle-in-use-user/update-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.UpdateVehicleTripDetail)
-	router.DELETE("/api/vehicle-in-use-user/delete-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.DeleteVehicleTripDetail)
-	router.GET("/api/vehicle-in-use-user/add-fuel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.GetVehicleAddFuelDetails)
-	router.GET("/api/vehicle-in-use-user/add-fuel-detail/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.GetVehicleAddFuelDetail)
-	router.POST("/api/vehicle-in-use-user/create-add-fuel", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.CreateVehicleAddFuel)
-	router.PUT("/api/vehicle-in-use-user/update-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.UpdateVehicleAddFuel)
-	router.DELETE("/api/vehicle-in-use-user/delete-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.DeleteVehicleAddFuel)
-	router.GET("/api/vehicle-in-use-user/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.GetTravelCard)
-	router.PUT("/api/vehicle-in-use-user/returned-vehicle", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.ReturnedVehicle)
-	router.PUT("/api/vehicle-in-use-user/update-satisfaction-survey/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseUserHandler.UpdateSatisfactionSurvey)
+	vehicleInUseUserHandler := handlers.VehicleInUseUserHandler{Role: permission.GetRoles(permission.BookingRequest)}
+	router.GET("/api/vehicle-in-use-user/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.SearchRequests)
+	router.GET("/api/vehicle-in-use-user/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.GetRequest)
+	router.GET("/api/vehicle-in-use-user/travel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.GetVehicleTripDetails)
+	router.GET("/api/vehicle-in-use-user/travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.GetVehicleTripDetail)
+	router.POST("/api/vehicle-in-use-user/create-travel-detail", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.CreateVehicleTripDetail)
+	router.PUT("/api/vehicle-in-use-user/update-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.UpdateVehicleTripDetail)
+	router.DELETE("/api/vehicle-in-use-user/delete-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.DeleteVehicleTripDetail)
+	router.GET("/api/vehicle-in-use-user/add-fuel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.GetVehicleAddFuelDetails)
+	router.GET("/api/vehicle-in-use-user/add-fuel-detail/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.GetVehicleAddFuelDetail)
+	router.POST("/api/vehicle-in-use-user/create-add-fuel", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.CreateVehicleAddFuel)
+	router.PUT("/api/vehicle-in-use-user/update-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.UpdateVehicleAddFuel)
+	router.DELETE("/api/vehicle-in-use-user/delete-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.DeleteVehicleAddFuel)
+	router.GET("/api/vehicle-in-use-user/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.GetTravelCard)
+	router.PUT("/api/vehicle-in-use-user/returned-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.ReturnedVehicle)
+	router.PUT("/api/vehicle-in-use-user/update-satisfaction-survey/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.UpdateSatisfactionSurvey)
+	router.POST("/api/vehicle-in-use-user/create-extension", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.CreateRequestExtension)
+	router.GET("/api/vehicle-in-use-user/extensions/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.GetRequestExtensions)
+	router.GET("/api/vehicle-in-use-user/passengers/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.GetPassengers)
+	router.PUT("/api/vehicle-in-use-user/update-passengers", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingRequest), vehicleInUseUserHandler.UpdatePassengers)
 
 	//VehicleInUseAdminHandler
-	vehicleInUseAdminHandler := handlers.VehicleInUseAdminHandler{Role: "admin-department,admin-carpool,admin-department-main"}
-	router.GET("/api/vehicle-in-use-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.SearchRequests)
-	router.GET("/api/vehicle-in-use-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.GetRequest)
-	router.GET("/api/vehicle-in-use-admin/travel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.GetVehicleTripDetails)
-	router.GET("/api/vehicle-in-use-admin/travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.GetVehicleTripDetail)
-	router.POST("/api/vehicle-in-use-admin/create-travel-detail", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.CreateVehicleTripDetail)
-	router.PUT("/api/vehicle-in-use-admin/update-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.UpdateVehicleTripDetail)
-	router.DELETE("/api/vehicle-in-use-admin/delete-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.DeleteVehicleTripDetail)
-	router.GET("/api/vehicle-in-use-admin/add-fuel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.GetVehicleAddFuelDetails)
-	router.GET("/api/vehicle-in-use-admin/add-fuel-detail/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.GetVehicleAddFuelDetail)
-	router.POST("/api/vehicle-in-use-admin/create-add-fuel", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.CreateVehicleAddFuel)
-	router.PUT("/api/vehicle-in-use-admin/update-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.UpdateVehicleAddFuel)
-	router.DELETE("/api/vehicle-in-use-admin/delete-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.DeleteVehicleAddFuel)
-	router.GET("/api/vehicle-in-use-admin/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.GetTravelCard)
-	router.PUT("/api/vehicle-in-use-admin/returned-vehicle", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.ReturnedVehicle)
-	router.PUT("/api/vehicle-in-use-admin/update-received-vehicle", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.UpdateReceivedVehicle)
-	router.PUT("/api/vehicle-in-use-admin/update-received-vehicle-images", funcs.ApiKeyAuthenMiddleware(), vehicleInUseAdminHandler.UpdateReceivedVehicleImages)
+	vehicleInUseAdminHandler := handlers.VehicleInUseAdminHandler{Role: permission.GetRoles(permission.BookingAdmin)}
+	router.GET("/api/vehicle-in-use-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.SearchRequests)
+	router.GET("/api/vehicle-in-use-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.GetRequest)
+	router.GET("/api/vehicle-in-use-admin/travel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.GetVehicleTripDetails)
+	router.GET("/api/vehicle-in-use-admin/travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.GetVehicleTripDetail)
+	router.POST("/api/vehicle-in-use-admin/create-travel-detail", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.CreateVehicleTripDetail)
+	router.PUT("/api/vehicle-in-use-admin/update-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.UpdateVehicleTripDetail)
+	router.DELETE("/api/vehicle-in-use-admin/delete-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.DeleteVehicleTripDetail)
+	router.GET("/api/vehicle-in-use-admin/add-fuel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.GetVehicleAddFuelDetails)
+	router.GET("/api/vehicle-in-use-admin/add-fuel-detail/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.GetVehicleAddFuelDetail)
+	router.POST("/api/vehicle-in-use-admin/create-add-fuel", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.CreateVehicleAddFuel)
+	router.PUT("/api/vehicle-in-use-admin/update-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.UpdateVehicleAddFuel)
+	router.DELETE("/api/vehicle-in-use-admin/delete-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.DeleteVehicleAddFuel)
+	router.GET("/api/vehicle-in-use-admin/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.GetTravelCard)
+	router.PUT("/api/vehicle-in-use-admin/returned-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.ReturnedVehicle)
+	router.PUT("/api/vehicle-in-use-admin/update-received-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.UpdateReceivedVehicle)
+	router.PUT("/api/vehicle-in-use-admin/update-received-vehicle-images", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.UpdateReceivedVehicleImages)
+	router.GET("/api/vehicle-in-use-admin/extensions/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.GetRequestExtensions)
+	router.PUT("/api/vehicle-in-use-admin/update-extension-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.UpdateExtensionApproved)
+	router.PUT("/api/vehicle-in-use-admin/update-extension-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInUseAdminHandler.UpdateExtensionRejected)
 
 	//VehicleInUseDriverHandler
-	vehicleInUseDriverHandler := handlers.VehicleInUseDriverHandler{Role: "driver,vehicle-user,admin-department,admin-carpool,admin-department-main"}
-	router.GET("/api/vehicle-in-use-driver/search-requests", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.SearchRequests)
-	router.GET("/api/vehicle-in-use-driver/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.GetRequest)
-	router.GET("/api/vehicle-in-use-driver/travel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.GetVehicleTripDetails)
-	router.GET("/api/vehicle-in-use-driver/travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.GetVehicleTripDetail)
-	router.POST("/api/vehicle-in-use-driver/create-travel-detail", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.CreateVehicleTripDetail)
-	router.PUT("/api/vehicle-in-use-driver/update-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.UpdateVehicleTripDetail)
-	router.DELETE("/api/vehicle-in-use-driver/delete-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.DeleteVehicleTripDetail)
-	router.GET("/api/vehicle-in-use-driver/add-fuel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.GetVehicleAddFuelDetails)
-	router.GET("/api/vehicle-in-use-driver/add-fuel-detail/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.GetVehicleAddFuelDetail)
-	router.POST("/api/vehicle-in-use-driver/create-add-fuel", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.CreateVehicleAddFuel)
-	router.PUT("/api/vehicle-in-use-driver/update-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.UpdateVehicleAddFuel)
-	router.DELETE("/api/vehicle-in-use-driver/delete-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.DeleteVehicleAddFuel)
-	router.GET("/api/vehicle-in-use-driver/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.GetTravelCard)
-	router.PUT("/api/vehicle-in-use-driver/returned-vehicle", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.ReturnedVehicle)
-	router.PUT("/api/vehicle-in-use-driver/update-received-vehicle", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.UpdateReceivedVehicle)
-	router.PUT("/api/vehicle-in-use-driver/update-received-vehicle-images", funcs.ApiKeyAuthenMiddleware(), vehicleInUseDriverHandler.UpdateReceivedVehicleImages)
+	vehicleInUseDriverHandler := handlers.VehicleInUseDriverHandler{Role: permission.GetRoles(permission.VehicleInUseJob)}
+	router.GET("/api/vehicle-in-use-driver/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.SearchRequests)
+	router.GET("/api/vehicle-in-use-driver/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.GetRequest)
+	router.GET("/api/vehicle-in-use-driver/travel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.GetVehicleTripDetails)
+	router.GET("/api/vehicle-in-use-driver/travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.GetVehicleTripDetail)
+	router.POST("/api/vehicle-in-use-driver/create-travel-detail", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.CreateVehicleTripDetail)
+	router.PUT("/api/vehicle-in-use-driver/update-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.UpdateVehicleTripDetail)
+	router.DELETE("/api/vehicle-in-use-driver/delete-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.DeleteVehicleTripDetail)
+	router.GET("/api/vehicle-in-use-driver/add-fuel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.GetVehicleAddFuelDetails)
+	router.GET("/api/vehicle-in-use-driver/add-fuel-detail/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.GetVehicleAddFuelDetail)
+	router.POST("/api/vehicle-in-use-driver/create-add-fuel", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.CreateVehicleAddFuel)
+	router.PUT("/api/vehicle-in-use-driver/update-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.UpdateVehicleAddFuel)
+	router.DELETE("/api/vehicle-in-use-driver/delete-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.DeleteVehicleAddFuel)
+	router.GET("/api/vehicle-in-use-driver/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.GetTravelCard)
+	router.PUT("/api/vehicle-in-use-driver/returned-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.ReturnedVehicle)
+	router.PUT("/api/vehicle-in-use-driver/update-received-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.UpdateReceivedVehicle)
+	router.PUT("/api/vehicle-in-use-driver/update-received-vehicle-images", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.VehicleInUseJob), vehicleInUseDriverHandler.UpdateReceivedVehicleImages)
 
 	//VehicleInspectionAdminHandler
-	vehicleInspectionAdminHandler := handlers.VehicleInspectionAdminHandler{Role: "admin-department,admin-carpool,admin-department-main"}
-	router.GET("/api/vehicle-inspection-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.SearchRequests)
-	router.GET("/api/vehicle-inspection-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.GetRequest)
-	router.GET("/api/vehicle-inspection-admin/travel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.GetVehicleTripDetails)
-	router.GET("/api/vehicle-inspection-admin/travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.GetVehicleTripDetail)
-	router.POST("/api/vehicle-inspection-admin/create-travel-detail", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.CreateVehicleTripDetail)
-	router.PUT("/api/vehicle-inspection-admin/update-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.UpdateVehicleTripDetail)
-	router.DELETE("/api/vehicle-inspection-admin/delete-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.DeleteVehicleTripDetail)
-	router.GET("/api/vehicle-inspection-admin/add-fuel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.GetVehicleAddFuelDetails)
-	router.GET("/api/vehicle-inspection-admin/add-fuel-detail/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.GetVehicleAddFuelDetail)
-	router.POST("/api/vehicle-inspection-admin/create-add-fuel", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.CreateVehicleAddFuel)
-	router.PUT("/api/vehicle-inspection-admin/update-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.UpdateVehicleAddFuel)
-	router.DELETE("/api/vehicle-inspection-admin/delete-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.DeleteVehicleAddFuel)
-	router.GET("/api/vehicle-inspection-admin/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.GetTravelCard)
-	router.PUT("/api/vehicle-inspection-admin/update-returned-vehicle", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.UpdateReturnedVehicle)
-	router.PUT("/api/vehicle-inspection-admin/update-returned-vehicle-images", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.UpdateReturnedVehicleImages)
-	router.GET("/api/vehicle-inspection-admin/satisfaction-survey/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.GetSatisfactionSurvey)
-	router.PUT("/api/vehicle-inspection-admin/update-rejected", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.UpdateRejected)
-	router.PUT("/api/vehicle-inspection-admin/update-accepted", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.UpdateAccepted)
-	router.PUT("/api/vehicle-inspection-admin/update-inspect-vehicle-images", funcs.ApiKeyAuthenMiddleware(), vehicleInspectionAdminHandler.UpdateInspectVehicleImages)
+	vehicleInspectionAdminHandler := handlers.VehicleInspectionAdminHandler{Role: permission.GetRoles(permission.BookingAdmin)}
+	router.GET("/api/vehicle-inspection-admin/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.SearchRequests)
+	router.GET("/api/vehicle-inspection-admin/request/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.GetRequest)
+	router.GET("/api/vehicle-inspection-admin/travel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.GetVehicleTripDetails)
+	router.GET("/api/vehicle-inspection-admin/travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.GetVehicleTripDetail)
+	router.POST("/api/vehicle-inspection-admin/create-travel-detail", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.CreateVehicleTripDetail)
+	router.PUT("/api/vehicle-inspection-admin/update-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.UpdateVehicleTripDetail)
+	router.DELETE("/api/vehicle-inspection-admin/delete-travel-detail/:trn_trip_detail_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.DeleteVehicleTripDetail)
+	router.GET("/api/vehicle-inspection-admin/add-fuel-details/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.GetVehicleAddFuelDetails)
+	router.GET("/api/vehicle-inspection-admin/add-fuel-detail/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.GetVehicleAddFuelDetail)
+	router.POST("/api/vehicle-inspection-admin/create-add-fuel", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.CreateVehicleAddFuel)
+	router.PUT("/api/vehicle-inspection-admin/update-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.UpdateVehicleAddFuel)
+	router.DELETE("/api/vehicle-inspection-admin/delete-add-fuel/:trn_add_fuel_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.DeleteVehicleAddFuel)
+	router.GET("/api/vehicle-inspection-admin/travel-card/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.GetTravelCard)
+	router.PUT("/api/vehicle-inspection-admin/update-returned-vehicle", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.UpdateReturnedVehicle)
+	router.PUT("/api/vehicle-inspection-admin/update-returned-vehicle-images", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.UpdateReturnedVehicleImages)
+	router.GET("/api/vehicle-inspection-admin/satisfaction-survey/:trn_request_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.GetSatisfactionSurvey)
+	router.PUT("/api/vehicle-inspection-admin/update-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.UpdateRejected)
+	router.PUT("/api/vehicle-inspection-admin/update-accepted", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.UpdateAccepted)
+	router.PUT("/api/vehicle-inspection-admin/update-inspect-vehicle-images", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.BookingAdmin), vehicleInspectionAdminHandler.UpdateInspectVehicleImages)
 
 	//VehicleManagementHandler
-	vehicleManagementHandler := handlers.VehicleManagementHandler{Role: "admin-super,admin-region,admin-department,admin-department-main"}
-	router.GET("/api/vehicle-management/search", funcs.ApiKeyAuthenMiddleware(), vehicleManagementHandler.SearchVehicles)
-	router.PUT("/api/vehicle-management/update-vehicle-is-active", funcs.ApiKeyAuthenMiddleware(), vehicleManagementHandler.UpdateVehicleIsActive)
-	router.GET("/api/vehicle-management/timeline", funcs.ApiKeyAuthenMiddleware(), vehicleManagementHandler.GetVehicleTimeLine)
-	router.POST("/api/vehicle-management/report-trip-detail", funcs.ApiKeyAuthenMiddleware(), vehicleManagementHandler.ReportTripDetail)
-	router.POST("/api/vehicle-management/report-add-fuel", funcs.ApiKeyAuthenMiddleware(), vehicleManagementHandler.ReportAddFuel)
+	vehicleManagementHandler := handlers.VehicleManagementHandler{Role: permission.GetRoles(permission.MasterDataVehicle)}
+	router.GET("/api/vehicle-management/search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataVehicle), vehicleManagementHandler.SearchVehicles)
+	router.PUT("/api/vehicle-management/update-vehicle-is-active", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataVehicle), vehicleManagementHandler.UpdateVehicleIsActive)
+	router.GET("/api/vehicle-management/timeline", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataVehicle), vehicleManagementHandler.GetVehicleTimeLine)
+	router.POST("/api/vehicle-management/report-trip-detail", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataVehicle), vehicleManagementHandler.ReportTripDetail)
+	router.POST("/api/vehicle-management/report-add-fuel", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataVehicle), vehicleManagementHandler.ReportAddFuel)
 
 	//DriverManagementHandler
-	driverManagementHandler := handlers.DriverManagementHandler{Role: "admin-super,admin-region,admin-department,admin-department-main"}
-	router.GET("/api/driver-management/search", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.SearchDrivers)
-	router.POST("/api/driver-management/create-driver", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.CreateDriver)
-	router.GET("/api/driver-management/driver/:mas_driver_uid", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.GetDriver)
-	router.PUT("/api/driver-management/update-driver-detail", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.UpdateDriverDetail)
-	router.PUT("/api/driver-management/update-driver-contract", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.UpdateDriverContract)
-	router.PUT("/api/driver-management/update-driver-license", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.UpdateDriverLicense)
-	router.PUT("/api/driver-management/update-driver-documents", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.UpdateDriverDocuments)
-	router.PUT("/api/driver-management/update-driver-leave-status", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.UpdateDriverLeaveStatus)
-	router.PUT("/api/driver-management/update-driver-is-active", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.UpdateDriverIsActive)
-	router.DELETE("/api/driver-management/delete-driver", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.DeleteDriver)
-	router.PUT("/api/driver-management/update-driver-layoff-status", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.UpdateDriverLayoffStatus)
-	router.PUT("/api/driver-management/update-driver-resign-status", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.UpdateDriverResignStatus)
-	router.GET("/api/driver-management/replacement-drivers", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.GetReplacementDrivers)
-	router.GET("/api/driver-management/timeline", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.GetDriverTimeLine)
-	router.POST("/api/driver-management/import-driver", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.ImportDriver)
-	router.POST("/api/driver-management/work-report", funcs.ApiKeyAuthenMiddleware(), driverManagementHandler.GetDriverWorkReport)
+	driverManagementHandler := handlers.DriverManagementHandler{Role: permission.GetRoles(permission.MasterDataDriver)}
+	router.GET("/api/driver-management/search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.SearchDrivers)
+	router.POST("/api/driver-management/create-driver", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.CreateDriver)
+	router.GET("/api/driver-management/driver/:mas_driver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.GetDriver)
+	router.PUT("/api/driver-management/update-driver-detail", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.UpdateDriverDetail)
+	router.PUT("/api/driver-management/update-driver-contract", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.UpdateDriverContract)
+	router.PUT("/api/driver-management/update-driver-license", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.UpdateDriverLicense)
+	router.PUT("/api/driver-management/update-driver-documents", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.UpdateDriverDocuments)
+	router.PUT("/api/driver-management/update-driver-leave-status", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.UpdateDriverLeaveStatus)
+	router.PUT("/api/driver-management/update-driver-is-active", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.UpdateDriverIsActive)
+	router.DELETE("/api/driver-management/delete-driver", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.DeleteDriver)
+	router.PUT("/api/driver-management/update-driver-layoff-status", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.UpdateDriverLayoffStatus)
+	router.PUT("/api/driver-management/update-driver-resign-status", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.UpdateDriverResignStatus)
+	router.GET("/api/driver-management/replacement-drivers", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.GetReplacementDrivers)
+	router.GET("/api/driver-management/timeline", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.GetDriverTimeLine)
+	router.POST("/api/driver-management/import-driver", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.ImportDriver)
+	router.POST("/api/driver-management/work-report", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataDriver), driverManagementHandler.GetDriverWorkReport)
 
 	//DriverLicenseUserHandler
-	driverLicenseUserHandler := handlers.DriverLicenseUserHandler{Role: "vehicle-user"}
-	router.GET("/api/driver-license-user/card", funcs.ApiKeyAuthenMiddleware(), driverLicenseUserHandler.GetLicenseCard)
-	router.POST("/api/driver-license-user/create-license-annual", funcs.ApiKeyAuthenMiddleware(), driverLicenseUserHandler.CreateDriverLicenseAnnual)
-	router.GET("/api/driver-license-user/license-annual/:trn_request_annual_driver_uid", funcs.ApiKeyAuthenMiddleware(), driverLicenseUserHandler.GetDriverLicenseAnnual)
-	router.PUT("/api/driver-license-user/update-license-annual-canceled", funcs.ApiKeyAuthenMiddleware(), driverLicenseUserHandler.UpdateDriverLicenseAnnualCanceled)
-	router.PUT("/api/driver-license-user/resend-license-annual/:trn_request_annual_driver_uid", funcs.ApiKeyAuthenMiddleware(), driverLicenseUserHandler.ResendDriverLicenseAnnual)
+	driverLicenseUserHandler := handlers.DriverLicenseUserHandler{Role: permission.GetRoles(permission.LicenseRequest)}
+	router.GET("/api/driver-license-user/card", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseRequest), driverLicenseUserHandler.GetLicenseCard)
+	router.POST("/api/driver-license-user/create-license-annual", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseRequest), driverLicenseUserHandler.CreateDriverLicenseAnnual)
+	router.GET("/api/driver-license-user/license-annual/:trn_request_annual_driver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseRequest), driverLicenseUserHandler.GetDriverLicenseAnnual)
+	router.PUT("/api/driver-license-user/update-license-annual-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseRequest), driverLicenseUserHandler.UpdateDriverLicenseAnnualCanceled)
+	router.PUT("/api/driver-license-user/resend-license-annual/:trn_request_annual_driver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseRequest), driverLicenseUserHandler.ResendDriverLicenseAnnual)
 
 	//DriverLicenseConfirmerHandler
-	driverLicenseConfirmerHandler := handlers.DriverLicenseConfirmerHandler{Role: "level1-approval"}
-	router.GET("/api/driver-license-confirmer/search-requests", funcs.ApiKeyAuthenMiddleware(), driverLicenseConfirmerHandler.SearchRequests)
-	router.GET("/api/driver-license-confirmer/license-annual/:trn_request_annual_driver_uid", funcs.ApiKeyAuthenMiddleware(), driverLicenseConfirmerHandler.GetDriverLicenseAnnual)
-	router.PUT("/api/driver-license-confirmer/update-license-annual-canceled", funcs.ApiKeyAuthenMiddleware(), driverLicenseConfirmerHandler.UpdateDriverLicenseAnnualCanceled)
-	router.PUT("/api/driver-license-confirmer/update-license-annual-confirmed", funcs.ApiKeyAuthenMiddleware(), driverLicenseConfirmerHandler.UpdateDriverLicenseAnnualConfirmed)
-	router.PUT("/api/driver-license-confirmer/update-license-annual-rejected", funcs.ApiKeyAuthenMiddleware(), driverLicenseConfirmerHandler.UpdateDriverLicenseAnnualRejected)
-	router.PUT("/api/driver-license-confirmer/update-license-annual-approver", funcs.ApiKeyAuthenMiddleware(), driverLicenseConfirmerHandler.UpdateDriverLicenseAnnualApprover)
+	driverLicenseConfirmerHandler := handlers.DriverLicenseConfirmerHandler{Role: permission.GetRoles(permission.LicenseConfirm)}
+	router.GET("/api/driver-license-confirmer/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseConfirm), driverLicenseConfirmerHandler.SearchRequests)
+	router.GET("/api/driver-license-confirmer/license-annual/:trn_request_annual_driver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseConfirm), driverLicenseConfirmerHandler.GetDriverLicenseAnnual)
+	router.PUT("/api/driver-license-confirmer/update-license-annual-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseConfirm), driverLicenseConfirmerHandler.UpdateDriverLicenseAnnualCanceled)
+	router.PUT("/api/driver-license-confirmer/update-license-annual-confirmed", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseConfirm), driverLicenseConfirmerHandler.UpdateDriverLicenseAnnualConfirmed)
+	router.PUT("/api/driver-license-confirmer/update-license-annual-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseConfirm), driverLicenseConfirmerHandler.UpdateDriverLicenseAnnualRejected)
+	router.PUT("/api/driver-license-confirmer/update-license-annual-approver", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseConfirm), driverLicenseConfirmerHandler.UpdateDriverLicenseAnnualApprover)
+	router.PUT("/api/driver-license-confirmer/update-bulk-license-annual-confirmed", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseConfirm), driverLicenseConfirmerHandler.UpdateBulkDriverLicenseAnnualConfirmed)
+	router.PUT("/api/driver-license-confirmer/update-bulk-license-annual-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseConfirm), driverLicenseConfirmerHandler.UpdateBulkDriverLicenseAnnualRejected)
 
 	//DriverLicenseApproverHandler
-	driverLicenseApproverHandler := handlers.DriverLicenseApproverHandler{Role: "license-approval"}
-	router.GET("/api/driver-license-approver/menu-requests", funcs.ApiKeyAuthenMiddleware(), driverLicenseApproverHandler.MenuRequests)
-	router.GET("/api/driver-license-approver/search-requests", funcs.ApiKeyAuthenMiddleware(), driverLicenseApproverHandler.SearchRequests)
-	router.GET("/api/driver-license-approver/license-annual/:trn_request_annual_driver_uid", funcs.ApiKeyAuthenMiddleware(), driverLicenseApproverHandler.GetDriverLicenseAnnual)
-	router.PUT("/api/driver-license-approver/update-license-annual-canceled", funcs.ApiKeyAuthenMiddleware(), driverLicenseApproverHandler.UpdateDriverLicenseAnnualCanceled)
-	router.PUT("/api/driver-license-approver/update-license-annual-approved", funcs.ApiKeyAuthenMiddleware(), driverLicenseApproverHandler.UpdateDriverLicenseAnnualApproved)
-	router.PUT("/api/driver-license-approver/update-license-annual-rejected", funcs.ApiKeyAuthenMiddleware(), driverLicenseApproverHandler.UpdateDriverLicenseAnnualRejected)
+	driverLicenseApproverHandler := handlers.DriverLicenseApproverHandler{Role: permission.GetRoles(permission.LicenseApprove)}
+	router.GET("/api/driver-license-approver/menu-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseApprove), driverLicenseApproverHandler.MenuRequests)
+	router.GET("/api/driver-license-approver/search-requests", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseApprove), driverLicenseApproverHandler.SearchRequests)
+	router.GET("/api/driver-license-approver/license-annual/:trn_request_annual_driver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseApprove), driverLicenseApproverHandler.GetDriverLicenseAnnual)
+	router.PUT("/api/driver-license-approver/update-license-annual-canceled", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseApprove), driverLicenseApproverHandler.UpdateDriverLicenseAnnualCanceled)
+	router.PUT("/api/driver-license-approver/update-license-annual-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseApprove), driverLicenseApproverHandler.UpdateDriverLicenseAnnualApproved)
+	router.PUT("/api/driver-license-approver/update-license-annual-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseApprove), driverLicenseApproverHandler.UpdateDriverLicenseAnnualRejected)
+	router.PUT("/api/driver-license-approver/update-bulk-license-annual-approved", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseApprove), driverLicenseApproverHandler.UpdateBulkDriverLicenseAnnualApproved)
+	router.PUT("/api/driver-license-approver/update-bulk-license-annual-rejected", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.LicenseApprove), driverLicenseApproverHandler.UpdateBulkDriverLicenseAnnualRejected)
 
 	//CarpoolManagementHandler
-	carpoolManagementHandler := handlers.CarpoolManagementHandler{Role: "admin-super,admin-region,admin-department,admin-department-main"}
-	router.GET("/api/carpool-management/search", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchCarpools)
-	router.GET("/api/carpool-management/export", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.ExportCarpools)
-	router.GET("/api/carpool-management/check-carpool-name-is-exist", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.CheckCarpoolNameIsExist)
-	router.POST("/api/carpool-management/create", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.CreateCarpool)
-	router.GET("/api/carpool-management/carpool/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetCarpool)
-	router.PUT("/api/carpool-management/update/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.UpdateCarpool)
-	router.DELETE("/api/carpool-management/delete", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.DeleteCarpool)
-	router.GET("/api/carpool-management/mas-department", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetMasDepartment)
-	router.GET("/api/carpool-management/mas-department/:carpool_type", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetMasDepartment)
-
-	router.GET("/api/carpool-management/admin-mas-search", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchMasAdminUser)
-	router.GET("/api/carpool-management/admin-search/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchCarpoolAdmin)
-	router.GET("/api/carpool-management/admin-detail/:mas_carpool_admin_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetCarpoolAdmin)
-	router.POST("/api/carpool-management/admin-create", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.CreateCarpoolAdmin)
-	router.PUT("/api/carpool-management/admin-update/:mas_carpool_admin_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.UpdateCarpoolAdmin)
-	router.DELETE("/api/carpool-management/admin-delete/:mas_carpool_admin_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.DeleteCarpoolAdmin)
-	router.PUT("/api/carpool-management/set-active", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SetActiveCarpool)
-	router.PUT("/api/carpool-management/admin-update-main-admin/:mas_carpool_admin_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.UpdateCarpoolMainAdmin)
-
-	router.GET("/api/carpool-management/approver-mas-search", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchMasApprovalUser)
-	router.GET("/api/carpool-management/approver-search/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchCarpoolApprover)
-	router.GET("/api/carpool-management/approver-detail/:mas_carpool_approver_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetCarpoolApprover)
-	router.POST("/api/carpool-management/approver-create", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.CreateCarpoolApprover)
-	router.PUT("/api/carpool-management/approver-update/:mas_carpool_approver_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.UpdateCarpoolApprover)
-	router.DELETE("/api/carpool-management/approver-delete/:mas_carpool_approver_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.DeleteCarpoolApprover)
-	router.PUT("/api/carpool-management/approver-update-main-approver/:mas_carpool_approver_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.UpdateCarpoolMainApprover)
-
-	router.GET("/api/carpool-management/vehicle-search/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchCarpoolVehicle)
-	router.POST("/api/carpool-management/vehicle-create", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.CreateCarpoolVehicle)
-	router.PUT("/api/carpool-management/vehicle-set-active", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SetActiveCarpoolVehicle)
-	router.DELETE("/api/carpool-management/vehicle-delete/:mas_carpool_vehicle_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.DeleteCarpoolVehicle)
-	router.GET("/api/carpool-management/vehicle-mas-search", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchMasVehicles)
-	router.POST("/api/carpool-management/vehicle-mas-details", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetMasVehicleDetail)
-	router.GET("/api/carpool-management/vehicle-timeline/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetCarpoolVehicleTimeLine)
-
-	router.GET("/api/carpool-management/driver-search/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchCarpoolDriver)
-	router.POST("/api/carpool-management/driver-create", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.CreateCarpoolDriver)
-	router.PUT("/api/carpool-management/driver-set-active", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SetActiveCarpoolDriver)
-	router.DELETE("/api/carpool-management/driver-delete/:mas_carpool_driver_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.DeleteCarpoolDriver)
-	router.GET("/api/carpool-management/driver-mas-search", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.SearchMasDrivers)
-	router.POST("/api/carpool-management/driver-mas-details", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetMasDriverDetails)
-	router.GET("/api/carpool-management/driver-timeline/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), carpoolManagementHandler.GetCarpoolDriverTimeLine)
+	carpoolManagementHandler := handlers.CarpoolManagementHandler{Role: permission.GetRoles(permission.MasterDataCarpool)}
+	router.GET("/api/carpool-management/search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchCarpools)
+	router.GET("/api/carpool-management/export", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.ExportCarpools)
+	router.GET("/api/carpool-management/check-carpool-name-is-exist", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.CheckCarpoolNameIsExist)
+	router.POST("/api/carpool-management/create", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.CreateCarpool)
+	router.GET("/api/carpool-management/carpool/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetCarpool)
+	router.PUT("/api/carpool-management/update/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.UpdateCarpool)
+	router.DELETE("/api/carpool-management/delete", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.DeleteCarpool)
+	router.GET("/api/carpool-management/mas-department", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetMasDepartment)
+	router.GET("/api/carpool-management/mas-department/:carpool_type", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetMasDepartment)
+
+	router.GET("/api/carpool-management/admin-mas-search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchMasAdminUser)
+	router.GET("/api/carpool-management/admin-search/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchCarpoolAdmin)
+	router.GET("/api/carpool-management/admin-detail/:mas_carpool_admin_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetCarpoolAdmin)
+	router.POST("/api/carpool-management/admin-create", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.CreateCarpoolAdmin)
+	router.PUT("/api/carpool-management/admin-update/:mas_carpool_admin_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.UpdateCarpoolAdmin)
+	router.DELETE("/api/carpool-management/admin-delete/:mas_carpool_admin_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.DeleteCarpoolAdmin)
+	router.PUT("/api/carpool-management/set-active", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SetActiveCarpool)
+	router.PUT("/api/carpool-management/admin-update-main-admin/:mas_carpool_admin_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.UpdateCarpoolMainAdmin)
+
+	router.GET("/api/carpool-management/approver-mas-search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchMasApprovalUser)
+	router.GET("/api/carpool-management/approver-search/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchCarpoolApprover)
+	router.GET("/api/carpool-management/approver-detail/:mas_carpool_approver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetCarpoolApprover)
+	router.POST("/api/carpool-management/approver-create", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.CreateCarpoolApprover)
+	router.PUT("/api/carpool-management/approver-update/:mas_carpool_approver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.UpdateCarpoolApprover)
+	router.DELETE("/api/carpool-management/approver-delete/:mas_carpool_approver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.DeleteCarpoolApprover)
+	router.PUT("/api/carpool-management/approver-update-main-approver/:mas_carpool_approver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.UpdateCarpoolMainApprover)
+
+	router.GET("/api/carpool-management/vehicle-search/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchCarpoolVehicle)
+	router.POST("/api/carpool-management/vehicle-create", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.CreateCarpoolVehicle)
+	router.PUT("/api/carpool-management/vehicle-set-active", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SetActiveCarpoolVehicle)
+	router.DELETE("/api/carpool-management/vehicle-delete/:mas_carpool_vehicle_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.DeleteCarpoolVehicle)
+	router.GET("/api/carpool-management/vehicle-mas-search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchMasVehicles)
+	router.POST("/api/carpool-management/vehicle-mas-details", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetMasVehicleDetail)
+	router.GET("/api/carpool-management/vehicle-timeline/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetCarpoolVehicleTimeLine)
+
+	router.GET("/api/carpool-management/driver-search/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchCarpoolDriver)
+	router.POST("/api/carpool-management/driver-create", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.CreateCarpoolDriver)
+	router.PUT("/api/carpool-management/driver-set-active", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SetActiveCarpoolDriver)
+	router.DELETE("/api/carpool-management/driver-delete/:mas_carpool_driver_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.DeleteCarpoolDriver)
+	router.GET("/api/carpool-management/driver-mas-search", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.SearchMasDrivers)
+	router.POST("/api/carpool-management/driver-mas-details", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetMasDriverDetails)
+	router.GET("/api/carpool-management/driver-timeline/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetCarpoolDriverTimeLine)
+
+	router.GET("/api/carpool-management/approval-chain/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.GetCarpoolApprovalChain)
+	router.PUT("/api/carpool-management/approval-chain-update/:mas_carpool_uid", funcs.ApiKeyAuthenMiddleware(), permission.Require(permission.MasterDataCarpool), carpoolManagementHandler.UpdateCarpoolApprovalChain)
 
 	//MasHandler
 	masHandler := handlers.MasHandler{}
@@ -398,6 +547,7 @@ func main() {
 	router.GET("/api/ref/cost-center", funcs.ApiKeyAuthenMiddleware(), refHandler.ListCostCenter)
 	router.GET("/api/ref/vehicle-status", funcs.ApiKeyAuthenMiddleware(), refHandler.ListVehicleStatus)
 	router.GET("/api/ref/timeline-status", funcs.ApiKeyAuthenMiddleware(), refHandler.ListTimelineStatus)
+	router.GET("/api/ref/request-attachment-type", funcs.ApiKeyAuthenMiddleware(), refHandler.ListRequestAttachmentType)
 
 	//NotificationHandler
 	notificationHandler := handlers.NotificationHandler{}
@@ -421,6 +571,14 @@ func main() {
 		funcs.JobDriversCheckActive()
 		c.JSON(http.StatusOK, gin.H{"message": "Job drivers checked successfully"})
 	})
+	router.GET("/api/job/waitlist-expire-holds", func(c *gin.Context) {
+		funcs.JobWaitlistExpireHolds()
+		c.JSON(http.StatusOK, gin.H{"message": "Job waitlist holds expired successfully"})
+	})
+	router.GET("/api/job/purge-drafts", func(c *gin.Context) {
+		funcs.JobPurgeDrafts()
+		c.JSON(http.StatusOK, gin.H{"message": "Job drafts purged successfully"})
+	})
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.PersistAuthorization(true)))
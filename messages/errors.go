@@ -19,4 +19,27 @@ var (
 	ErrUnauthorized        = errors.New("ไม่มีสิทธิ์เข้าถึง")
 	ErrInvalidRequest      = errors.New("คำขอไม่ถูกต้อง")
 	ErrAlreadyExist        = errors.New("ข้อมูลนี้มีอยู่ในระบบแล้ว")
+	ErrWaitlistNotFound    = errors.New("ไม่พบข้อมูลรายการรอคิว")
+	ErrWaitlistHoldExpired = errors.New("หมดเวลายืนยันการจองจากรายการรอคิว")
+	ErrVehicleOnHold       = errors.New("ยานพาหนะนี้ถูกสำรองไว้ให้ผู้ที่รอคิว")
+	ErrExtensionPending    = errors.New("มีคำขอขยายเวลาที่รออนุมัติอยู่แล้ว")
+	ErrExtensionConflict   = errors.New("ยานพาหนะหรือพนักงานขับรถไม่ว่างในช่วงเวลาที่ขอขยาย")
+	ErrExtensionChanged    = errors.New("คำขอขยายเวลานี้ถูกดำเนินการแล้ว")
+	ErrDraftNotComplete    = errors.New("กรอกข้อมูลคำขอไม่ครบถ้วน")
+	ErrTemplateNotFound    = errors.New("ไม่พบแม่แบบคำขอ")
+	ErrPassengerOverSeat   = errors.New("จำนวนผู้โดยสารเกินจำนวนที่นั่งของยานพาหนะ")
+	ErrPassengerInvalid    = errors.New("ข้อมูลผู้โดยสารไม่ถูกต้อง")
+	ErrRideShareNotFound   = errors.New("ไม่พบข้อเสนอการเดินทางร่วม")
+	ErrRideShareChanged    = errors.New("คำขอในข้อเสนอการเดินทางร่วมมีการเปลี่ยนแปลง")
+	ErrActionLinkInvalid   = errors.New("ลิงก์ไม่ถูกต้องหรือหมดอายุแล้ว")
+	ErrActionLinkUsed      = errors.New("ลิงก์นี้ถูกใช้งานแล้ว หรือสถานะคำขอมีการเปลี่ยนแปลง")
+	ErrDelegationInvalid   = errors.New("ข้อมูลการมอบหมายให้ปฏิบัติแทนไม่ถูกต้อง")
+	ErrDelegationNotFound  = errors.New("ไม่พบข้อมูลการมอบหมายให้ปฏิบัติแทน")
+	ErrCommentNotFound     = errors.New("ไม่พบความคิดเห็น")
+	ErrMentionInvalid      = errors.New("ผู้ที่ถูกกล่าวถึงไม่ได้เป็นผู้เกี่ยวข้องกับคำขอ")
+	ErrAttachmentNotFound  = errors.New("ไม่พบเอกสารแนบ")
+	ErrAttachmentInvalid   = errors.New("ประเภทเอกสารแนบไม่ถูกต้อง")
+	ErrOTPLocked           = errors.New("ทำรายการ OTP เกินจำนวนครั้งที่กำหนด กรุณาลองใหม่ภายหลัง")
+	ErrImpersonateBlocked  = errors.New("ไม่สามารถทำรายการนี้ขณะเข้าใช้งานแทนผู้ใช้")
+	ErrImpersonateTarget   = errors.New("ไม่สามารถเข้าใช้งานแทนผู้ดูแลระบบหรือเจ้าหน้าที่สนับสนุน")
 )
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// VmsTrnActionToken
+type VmsTrnActionToken struct {
+	TrnActionTokenUID    string       `gorm:"column:trn_action_token_uid;primaryKey" json:"-"`
+	RecordUID            string       `gorm:"column:record_uid" json:"record_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	ActionStep           string       `gorm:"column:action_step" json:"action_step" example:"booking-confirmer"`
+	ActionCode           string       `gorm:"column:action_code" json:"action_code" example:"approve"`
+	EmpID                string       `gorm:"column:emp_id" json:"emp_id" example:"700001"`
+	RefRequestStatusCode string       `gorm:"column:ref_request_status_code" json:"ref_request_status_code" example:"20"`
+	ExpireDatetime       TimeWithZone `gorm:"column:expire_datetime" json:"expire_datetime" swaggertype:"string" example:"2025-01-04T08:00:00Z"`
+	IsUsed               string       `gorm:"column:is_used" json:"-"`
+	UsedDatetime         TimeWithZone `gorm:"column:used_datetime" json:"-"`
+	UsedIP               string       `gorm:"column:used_ip" json:"-"`
+	UsedUserAgent        string       `gorm:"column:used_user_agent" json:"-"`
+	CreatedAt            time.Time    `gorm:"column:created_at" json:"-"`
+}
+
+func (VmsTrnActionToken) TableName() string {
+	return "vms_trn_action_token"
+}
+
+// VmsTrnActionTokenVerify
+type VmsTrnActionTokenVerify struct {
+	VmsTrnActionToken
+	RequestNo string `json:"request_no" example:"VA67RA000001"`
+}
+
+// VmsTrnActionTokenApply
+type VmsTrnActionTokenApply struct {
+	Token          string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RejectedReason string `json:"rejected_request_reason" example:"Please revise the trip details"`
+}
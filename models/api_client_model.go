@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// VmsMasApiClient is an application allowed to call the API, such as the web frontend, the mobile app, EEMS or reporting
+type VmsMasApiClient struct {
+	MasApiClientUID string               `gorm:"column:mas_api_client_uid;primaryKey" json:"mas_api_client_uid"`
+	ClientCode      string               `gorm:"column:client_code" json:"client_code" example:"mobile-app"`
+	ClientName      string               `gorm:"column:client_name" json:"client_name" example:"VMS Plus mobile app"`
+	Scopes          string               `gorm:"column:scopes" json:"scopes" example:"login,booking-user,vehicle-in-use-driver"`
+	IsActive        string               `gorm:"column:is_active" json:"is_active" example:"1"`
+	IsDeleted       string               `gorm:"column:is_deleted" json:"-"`
+	CreatedAt       time.Time            `gorm:"column:created_at" json:"created_at"`
+	CreatedBy       string               `gorm:"column:created_by" json:"created_by"`
+	UpdatedAt       time.Time            `gorm:"column:updated_at" json:"updated_at"`
+	UpdatedBy       string               `gorm:"column:updated_by" json:"updated_by"`
+	Keys            []VmsMasApiClientKey `gorm:"foreignKey:MasApiClientUID;references:MasApiClientUID" json:"keys"`
+}
+
+func (VmsMasApiClient) TableName() string {
+	return "vms_mas_api_client"
+}
+
+// VmsMasApiClientRequest creates or updates an API client, scopes are route groups such as booking-user, "booking-*" or "*"
+type VmsMasApiClientRequest struct {
+	ClientCode string   `json:"client_code" binding:"required" example:"mobile-app"`
+	ClientName string   `json:"client_name" binding:"required" example:"VMS Plus mobile app"`
+	Scopes     []string `json:"scopes" binding:"required" example:"login,booking-user,vehicle-in-use-driver"`
+	IsActive   string   `json:"is_active" example:"1"`
+}
+
+// VmsMasApiClientKey is a key of an API client, only its hash is kept
+type VmsMasApiClientKey struct {
+	MasApiClientKeyUID string     `gorm:"column:mas_api_client_key_uid;primaryKey" json:"mas_api_client_key_uid"`
+	MasApiClientUID    string     `gorm:"column:mas_api_client_uid" json:"mas_api_client_uid"`
+	KeyPrefix          string     `gorm:"column:key_prefix" json:"key_prefix" example:"vms_3f9a2c"`
+	KeyHash            string     `gorm:"column:key_hash" json:"-"`
+	ExpiresAt          *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	IsActive           string     `gorm:"column:is_active" json:"is_active" example:"1"`
+	LastUsedAt         *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	CreatedAt          time.Time  `gorm:"column:created_at" json:"created_at"`
+	CreatedBy          string     `gorm:"column:created_by" json:"created_by"`
+	UpdatedAt          time.Time  `gorm:"column:updated_at" json:"updated_at"`
+	UpdatedBy          string     `gorm:"column:updated_by" json:"updated_by"`
+}
+
+func (VmsMasApiClientKey) TableName() string {
+	return "vms_mas_api_client_key"
+}
+
+// VmsMasApiClientKeyRequest issues a key, expires_at is empty for a key that does not expire.
+// On rotation the replaced key keeps working for overlap_hours.
+type VmsMasApiClientKeyRequest struct {
+	ExpiresAt    *time.Time `json:"expires_at" example:"2026-12-31T23:59:59+07:00"`
+	OverlapHours int        `json:"overlap_hours" example:"72"`
+}
+
+// VmsTrnApiClientUsage counts the calls of an API client to a route group per day
+type VmsTrnApiClientUsage struct {
+	MasApiClientUID string    `gorm:"column:mas_api_client_uid;primaryKey" json:"mas_api_client_uid"`
+	UsageDate       string    `gorm:"column:usage_date;primaryKey" json:"usage_date" example:"2025-06-16"`
+	RouteGroup      string    `gorm:"column:route_group;primaryKey" json:"route_group" example:"booking-user"`
+	RequestCount    int64     `gorm:"column:request_count" json:"request_count"`
+	LastUsedAt      time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+}
+
+func (VmsTrnApiClientUsage) TableName() string {
+	return "vms_trn_api_client_usage"
+}
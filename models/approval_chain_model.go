@@ -0,0 +1,126 @@
+package models
+
+import "time"
+
+// VmsMasApprovalChain
+type VmsMasApprovalChain struct {
+	MasApprovalChainUID string                    `gorm:"column:mas_approval_chain_uid;primaryKey" json:"mas_approval_chain_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	MasCarpoolUID       string                    `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	ChainName           string                    `gorm:"column:chain_name" json:"chain_name" example:"Carpool approval"`
+	IsActive            string                    `gorm:"column:is_active" json:"is_active" example:"1"`
+	Steps               []VmsMasApprovalChainStep `gorm:"foreignKey:MasApprovalChainUID;references:MasApprovalChainUID" json:"steps"`
+	CreatedAt           time.Time                 `gorm:"column:created_at" json:"-"`
+	CreatedBy           string                    `gorm:"column:created_by" json:"-"`
+	UpdatedAt           time.Time                 `gorm:"column:updated_at" json:"-"`
+	UpdatedBy           string                    `gorm:"column:updated_by" json:"-"`
+	IsDeleted           string                    `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsMasApprovalChain) TableName() string {
+	return "vms_mas_approval_chain"
+}
+
+// VmsMasApprovalChainStep
+type VmsMasApprovalChainStep struct {
+	MasApprovalChainStepUID   string                        `gorm:"column:mas_approval_chain_step_uid;primaryKey" json:"mas_approval_chain_step_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	MasApprovalChainUID       string                        `gorm:"column:mas_approval_chain_uid" json:"-"`
+	StepNo                    int                           `gorm:"column:step_no" json:"step_no" example:"1"`
+	StepName                  string                        `gorm:"column:step_name" json:"step_name" example:"Division manager"`
+	RefRequestStatusCode      string                        `gorm:"column:ref_request_status_code" json:"ref_request_status_code" example:"40"`
+	ApprovalMode              string                        `gorm:"column:approval_mode" json:"approval_mode" example:"any"`
+	LevelCode                 string                        `gorm:"column:level_code" json:"level_code" example:"M5"`
+	ConditionRefTripTypeCode  *int                          `gorm:"column:condition_ref_trip_type_code" json:"condition_ref_trip_type_code" example:"1"`
+	ConditionMinDurationHours int                           `gorm:"column:condition_min_duration_hours" json:"condition_min_duration_hours" example:"24"`
+	ConditionIsOutOfProvince  string                        `gorm:"column:condition_is_out_of_province" json:"condition_is_out_of_province" example:"1"`
+	ConditionRefCostTypeCode  *int                          `gorm:"column:condition_ref_cost_type_code" json:"condition_ref_cost_type_code" example:"2"`
+	Approvers                 []VmsMasApprovalChainApprover `gorm:"foreignKey:MasApprovalChainStepUID;references:MasApprovalChainStepUID" json:"approvers"`
+}
+
+func (VmsMasApprovalChainStep) TableName() string {
+	return "vms_mas_approval_chain_step"
+}
+
+// VmsMasApprovalChainApprover
+type VmsMasApprovalChainApprover struct {
+	MasApprovalChainApproverUID string `gorm:"column:mas_approval_chain_approver_uid;primaryKey" json:"-"`
+	MasApprovalChainStepUID     string `gorm:"column:mas_approval_chain_step_uid" json:"-"`
+	ApproverEmpID               string `gorm:"column:approver_emp_id" json:"approver_emp_id" example:"700001"`
+	ApproverEmpName             string `gorm:"column:approver_emp_name" json:"approver_emp_name" example:"John Doe"`
+	ApproverPosition            string `gorm:"column:approver_position" json:"approver_position" example:"Manager"`
+	ApproverDeptSAP             string `gorm:"column:approver_dept_sap" json:"approver_dept_sap" example:"DPT001"`
+}
+
+func (VmsMasApprovalChainApprover) TableName() string {
+	return "vms_mas_approval_chain_approver"
+}
+
+// VmsMasApprovalChainRequest
+type VmsMasApprovalChainRequest struct {
+	ChainName string                           `json:"chain_name" example:"Carpool approval"`
+	IsActive  string                           `json:"is_active" example:"1"`
+	Steps     []VmsMasApprovalChainStepRequest `json:"steps"`
+}
+
+// VmsMasApprovalChainStepRequest
+type VmsMasApprovalChainStepRequest struct {
+	StepName                  string   `json:"step_name" example:"Division manager"`
+	RefRequestStatusCode      string   `json:"ref_request_status_code" example:"40"`
+	ApprovalMode              string   `json:"approval_mode" example:"any"`
+	LevelCode                 string   `json:"level_code" example:"M5"`
+	ConditionRefTripTypeCode  *int     `json:"condition_ref_trip_type_code" example:"1"`
+	ConditionMinDurationHours int      `json:"condition_min_duration_hours" example:"24"`
+	ConditionIsOutOfProvince  string   `json:"condition_is_out_of_province" example:"1"`
+	ConditionRefCostTypeCode  *int     `json:"condition_ref_cost_type_code" example:"2"`
+	ApproverEmpIDs            []string `json:"approver_emp_ids" example:"700001,700002"`
+}
+
+// VmsTrnRequestApprovalStep is a step of the carpool chain that applies to a request
+type VmsTrnRequestApprovalStep struct {
+	TrnRequestApprovalStepUID string                              `gorm:"column:trn_request_approval_step_uid;primaryKey" json:"trn_request_approval_step_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	TrnRequestUID             string                              `gorm:"column:trn_request_uid" json:"-"`
+	MasApprovalChainStepUID   string                              `gorm:"column:mas_approval_chain_step_uid" json:"-"`
+	StepNo                    int                                 `gorm:"column:step_no" json:"step_no" example:"1"`
+	StepName                  string                              `gorm:"column:step_name" json:"step_name" example:"Division manager"`
+	RefRequestStatusCode      string                              `gorm:"column:ref_request_status_code" json:"ref_request_status_code" example:"40"`
+	ApprovalMode              string                              `gorm:"column:approval_mode" json:"approval_mode" example:"any"`
+	StepStatusCode            string                              `gorm:"column:step_status_code" json:"step_status_code" example:"0"`
+	ApprovedDatetime          TimeWithZone                        `gorm:"column:approved_datetime" json:"approved_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	Approvers                 []VmsTrnRequestApprovalStepApprover `gorm:"foreignKey:TrnRequestApprovalStepUID;references:TrnRequestApprovalStepUID" json:"approvers"`
+	CreatedAt                 time.Time                           `gorm:"column:created_at" json:"-"`
+}
+
+func (VmsTrnRequestApprovalStep) TableName() string {
+	return "vms_trn_request_approval_step"
+}
+
+// VmsTrnRequestApprovalStepApprover
+type VmsTrnRequestApprovalStepApprover struct {
+	TrnRequestApprovalStepApproverUID string       `gorm:"column:trn_request_approval_step_approver_uid;primaryKey" json:"-"`
+	TrnRequestApprovalStepUID         string       `gorm:"column:trn_request_approval_step_uid" json:"-"`
+	ApproverEmpID                     string       `gorm:"column:approver_emp_id" json:"approver_emp_id" example:"700001"`
+	ApproverEmpName                   string       `gorm:"column:approver_emp_name" json:"approver_emp_name" example:"John Doe"`
+	ApproverPosition                  string       `gorm:"column:approver_position" json:"approver_position" example:"Manager"`
+	IsApproved                        string       `gorm:"column:is_approved" json:"is_approved" example:"0"`
+	ApprovedDatetime                  TimeWithZone `gorm:"column:approved_datetime" json:"approved_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+}
+
+func (VmsTrnRequestApprovalStepApprover) TableName() string {
+	return "vms_trn_request_approval_step_approver"
+}
+
+// VmsTrnRequestApprovalChainCheck holds the request fields the step conditions are checked against
+type VmsTrnRequestApprovalChainCheck struct {
+	TrnRequestUID        string       `gorm:"column:trn_request_uid"`
+	RefRequestStatusCode string       `gorm:"column:ref_request_status_code"`
+	MasCarpoolUID        *string      `gorm:"column:mas_carpool_uid"`
+	VehicleUserDeptSAP   string       `gorm:"column:vehicle_user_dept_sap"`
+	RefTripTypeCode      *int         `gorm:"column:ref_trip_type_code"`
+	RefCostTypeCode      *int         `gorm:"column:ref_cost_type_code"`
+	IsOutOfProvince      string       `gorm:"column:is_out_of_province"`
+	ReserveStartDatetime TimeWithZone `gorm:"column:reserve_start_datetime"`
+	ReserveEndDatetime   TimeWithZone `gorm:"column:reserve_end_datetime"`
+}
+
+func (VmsTrnRequestApprovalChainCheck) TableName() string {
+	return "vms_trn_request"
+}
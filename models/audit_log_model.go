@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrAuditLogReadOnly is returned when an audit entry is about to be changed or removed
+var ErrAuditLogReadOnly = errors.New("audit log entries are append-only")
+
+// VmsLogMasterDataAudit is one change of master data, entries are only ever inserted
+type VmsLogMasterDataAudit struct {
+	LogMasterDataAuditUID string                       `gorm:"primaryKey;column:log_master_data_audit_uid" json:"log_master_data_audit_uid"`
+	EntityType            string                       `gorm:"column:entity_type" json:"entity_type" example:"vms_mas_carpool"`
+	EntityUID             string                       `gorm:"column:entity_uid" json:"entity_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	Operation             string                       `gorm:"column:operation" json:"operation" example:"update"`
+	ActionDatetime        TimeWithZone                 `gorm:"column:action_datetime" json:"action_datetime"`
+	ActionByPersonalID    string                       `gorm:"column:action_by_personal_id" json:"action_by_personal_id"`
+	ActionByFullname      string                       `gorm:"column:action_by_fullname" json:"action_by_fullname"`
+	ActionByRole          string                       `gorm:"column:action_by_role" json:"action_by_role"`
+	IPAddress             string                       `gorm:"column:ip_address" json:"ip_address" example:"10.0.0.1"`
+	RequestID             string                       `gorm:"column:request_id" json:"request_id" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	Fields                []VmsLogMasterDataAuditField `gorm:"foreignKey:LogMasterDataAuditUID;references:LogMasterDataAuditUID" json:"fields"`
+}
+
+func (VmsLogMasterDataAudit) TableName() string {
+	return "vms_log_master_data_audit"
+}
+
+func (VmsLogMasterDataAudit) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogReadOnly
+}
+
+func (VmsLogMasterDataAudit) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogReadOnly
+}
+
+// VmsLogMasterDataAuditField is the value of a column before and after the change
+type VmsLogMasterDataAuditField struct {
+	LogMasterDataAuditFieldUID string `gorm:"primaryKey;column:log_master_data_audit_field_uid" json:"-"`
+	LogMasterDataAuditUID      string `gorm:"column:log_master_data_audit_uid" json:"-"`
+	FieldName                  string `gorm:"column:field_name" json:"field_name" example:"carpool_name"`
+	OldValue                   string `gorm:"column:old_value" json:"old_value" example:"Carpool A"`
+	NewValue                   string `gorm:"column:new_value" json:"new_value" example:"Carpool B"`
+}
+
+func (VmsLogMasterDataAuditField) TableName() string {
+	return "vms_log_master_data_audit_field"
+}
+
+func (VmsLogMasterDataAuditField) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogReadOnly
+}
+
+func (VmsLogMasterDataAuditField) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogReadOnly
+}
@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// VmsMasAutoApprovalRule
+type VmsMasAutoApprovalRule struct {
+	MasAutoApprovalRuleUID string    `gorm:"column:mas_auto_approval_rule_uid;primaryKey" json:"mas_auto_approval_rule_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	MasCarpoolUID          string    `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	RuleName               string    `gorm:"column:rule_name" json:"rule_name" example:"Short in-town trip"`
+	MaxDurationHours       int       `gorm:"column:max_duration_hours" json:"max_duration_hours" example:"4"`
+	IsSameBusinessArea     string    `gorm:"column:is_same_business_area" json:"is_same_business_area" example:"1"`
+	IsNoDriver             string    `gorm:"column:is_no_driver" json:"is_no_driver" example:"1"`
+	MinLevelCode           string    `gorm:"column:min_level_code" json:"min_level_code" example:"S7"`
+	RefTripTypeCode        *int      `gorm:"column:ref_trip_type_code" json:"ref_trip_type_code" example:"0"`
+	IsAutoApprove30        string    `gorm:"column:is_auto_approve_30" json:"is_auto_approve_30" example:"1"`
+	IsAutoApprove40        string    `gorm:"column:is_auto_approve_40" json:"is_auto_approve_40" example:"1"`
+	IsActive               string    `gorm:"column:is_active" json:"is_active" example:"1"`
+	CreatedAt              time.Time `gorm:"column:created_at" json:"-"`
+	CreatedBy              string    `gorm:"column:created_by" json:"-"`
+	UpdatedAt              time.Time `gorm:"column:updated_at" json:"-"`
+	UpdatedBy              string    `gorm:"column:updated_by" json:"-"`
+	IsDeleted              string    `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsMasAutoApprovalRule) TableName() string {
+	return "vms_mas_auto_approval_rule"
+}
+
+// VmsMasAutoApprovalRuleRequest
+type VmsMasAutoApprovalRuleRequest struct {
+	MasAutoApprovalRuleUID string `json:"mas_auto_approval_rule_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	MasCarpoolUID          string `json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	RuleName               string `json:"rule_name" example:"Short in-town trip"`
+	MaxDurationHours       int    `json:"max_duration_hours" example:"4"`
+	IsSameBusinessArea     string `json:"is_same_business_area" example:"1"`
+	IsNoDriver             string `json:"is_no_driver" example:"1"`
+	MinLevelCode           string `json:"min_level_code" example:"S7"`
+	RefTripTypeCode        *int   `json:"ref_trip_type_code" example:"0"`
+	IsAutoApprove30        string `json:"is_auto_approve_30" example:"1"`
+	IsAutoApprove40        string `json:"is_auto_approve_40" example:"1"`
+	IsActive               string `json:"is_active" example:"1"`
+}
+
+// VmsMasAutoApprovalRuleDryRunRequest tests a saved rule, or an unsaved one, against a request
+type VmsMasAutoApprovalRuleDryRunRequest struct {
+	TrnRequestUID          string                         `json:"trn_request_uid" binding:"required" example:"8bd09808-61fa-42fd-8a03-bf961b5678cd"`
+	MasAutoApprovalRuleUID string                         `json:"mas_auto_approval_rule_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	Rule                   *VmsMasAutoApprovalRuleRequest `json:"rule"`
+}
+
+// AutoApprovalConditionResult is one condition of a rule checked against a request
+type AutoApprovalConditionResult struct {
+	Condition string `json:"condition" example:"max_duration_hours"`
+	Expected  string `json:"expected" example:"4"`
+	Actual    string `json:"actual" example:"3.5"`
+	IsMatched bool   `json:"is_matched" example:"true"`
+}
+
+// VmsTrnRequestAutoApprovalCheck holds the request fields the rule conditions are checked against
+type VmsTrnRequestAutoApprovalCheck struct {
+	TrnRequestUID           string       `gorm:"column:trn_request_uid"`
+	RefRequestStatusCode    string       `gorm:"column:ref_request_status_code"`
+	MasCarpoolUID           *string      `gorm:"column:mas_carpool_uid"`
+	CarpoolMainBusinessArea string       `gorm:"column:carpool_main_business_area"`
+	VehicleUserEmpID        string       `gorm:"column:vehicle_user_emp_id"`
+	VehicleUserDeptSAP      string       `gorm:"column:vehicle_user_dept_sap"`
+	VehicleUserLevelCode    string       `gorm:"-"`
+	IsPEAEmployeeDriver     string       `gorm:"column:is_pea_employee_driver"`
+	RefTripTypeCode         *int         `gorm:"column:ref_trip_type_code"`
+	ReserveStartDatetime    TimeWithZone `gorm:"column:reserve_start_datetime"`
+	ReserveEndDatetime      TimeWithZone `gorm:"column:reserve_end_datetime"`
+}
+
+// VmsTrnRequestAutoApproval records a status a rule approved for a request
+type VmsTrnRequestAutoApproval struct {
+	TrnRequestAutoApprovalUID string       `gorm:"column:trn_request_auto_approval_uid;primaryKey" json:"trn_request_auto_approval_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	TrnRequestUID             string       `gorm:"column:trn_request_uid" json:"trn_request_uid" example:"8bd09808-61fa-42fd-8a03-bf961b5678cd"`
+	MasAutoApprovalRuleUID    string       `gorm:"column:mas_auto_approval_rule_uid" json:"mas_auto_approval_rule_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	RuleName                  string       `gorm:"column:rule_name" json:"rule_name" example:"Short in-town trip"`
+	FromRequestStatusCode     string       `gorm:"column:from_request_status_code" json:"from_request_status_code" example:"30"`
+	ToRequestStatusCode       string       `gorm:"column:to_request_status_code" json:"to_request_status_code" example:"40"`
+	ApprovedDatetime          TimeWithZone `gorm:"column:approved_datetime" json:"approved_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	IsReverted                string       `gorm:"column:is_reverted" json:"is_reverted" example:"0"`
+	RevertedEmpID             string       `gorm:"column:reverted_emp_id" json:"reverted_emp_id" example:"700001"`
+	RevertedDatetime          TimeWithZone `gorm:"column:reverted_datetime" json:"reverted_datetime" swaggertype:"string" example:"2025-01-01T09:00:00Z"`
+}
+
+func (VmsTrnRequestAutoApproval) TableName() string {
+	return "vms_trn_request_auto_approval"
+}
+
+// VmsTrnRequestAutoApprovalList
+type VmsTrnRequestAutoApprovalList struct {
+	VmsTrnRequestAutoApproval
+	RequestNo            string       `gorm:"column:request_no" json:"request_no" example:"VA67RA000001"`
+	VehicleUserEmpName   string       `gorm:"column:vehicle_user_emp_name" json:"vehicle_user_emp_name" example:"John Doe"`
+	RefRequestStatusCode string       `gorm:"column:ref_request_status_code" json:"ref_request_status_code" example:"40"`
+	ReserveStartDatetime TimeWithZone `gorm:"column:reserve_start_datetime" json:"start_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// VmsTrnRequestBulk
+type VmsTrnRequestBulk struct {
+	TrnRequestUIDs []string `json:"trn_request_uids" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9,1b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	Remark         string   `json:"remark" example:"Approved in batch"`
+}
+
+// VmsTrnRequestBulkAdminApproved
+type VmsTrnRequestBulkAdminApproved struct {
+	TrnRequestUIDs           []string     `json:"trn_request_uids" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9,1b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	Remark                   string       `json:"remark" example:"Approved in batch"`
+	ReceivedKeyPlace         string       `json:"received_key_place" example:"Main Office"`
+	ReceivedKeyStartDatetime TimeWithZone `json:"received_key_start_datetime" swaggertype:"string" example:"2025-02-16T08:00:00Z"`
+	ReceivedKeyEndDatetime   TimeWithZone `json:"received_key_end_datetime" swaggertype:"string" example:"2025-02-16T09:30:00Z"`
+	ApprovedRequestEmpID     string       `json:"approved_request_emp_id" example:"700001"`
+}
+
+// VmsDriverLicenseAnnualBulk
+type VmsDriverLicenseAnnualBulk struct {
+	TrnRequestAnnualDriverUIDs []string `json:"trn_request_annual_driver_uids" example:"095fbfbf-378e-4507-b15f-e53ac60370e7,195fbfbf-378e-4507-b15f-e53ac60370e7"`
+	Remark                     string   `json:"remark" example:"Approved in batch"`
+}
+
+// VmsTrnBulkResult
+type VmsTrnBulkResult struct {
+	UID     string `json:"uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	Success bool   `json:"success" example:"true"`
+	Error   string `json:"error,omitempty" example:"Booking can not update"`
+	Message string `json:"message,omitempty" example:"ไม่สามารถอัปเดตคำขอได้"`
+}
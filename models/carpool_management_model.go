@@ -47,6 +47,7 @@ type VmsMasCarpoolRequest struct {
 	IsMustPassStatus30       string                        `gorm:"column:is_must_pass_status_30" json:"is_must_pass_status_30" example:"0"`
 	IsMustPassStatus40       string                        `gorm:"column:is_must_pass_status_40" json:"is_must_pass_status_40" example:"0"`
 	IsMustPassStatus50       string                        `gorm:"column:is_must_pass_status_50" json:"is_must_pass_status_50" example:"0"`
+	ExtensionAutoApproveMins int                           `gorm:"column:extension_auto_approve_minutes" json:"extension_auto_approve_minutes" example:"30"`
 	IsActive                 string                        `gorm:"column:is_active" json:"is_active"`
 	CreatedAt                time.Time                     `gorm:"column:created_at;autoCreateTime" json:"-"`
 	UpdatedAt                time.Time                     `gorm:"column:updated_at;autoUpdateTime" json:"-"`
@@ -80,6 +81,7 @@ type VmsMasCarpoolUpdate struct {
 	IsMustPassStatus30       string                        `gorm:"column:is_must_pass_status_30" json:"is_must_pass_status_30" example:"0"`
 	IsMustPassStatus40       string                        `gorm:"column:is_must_pass_status_40" json:"is_must_pass_status_40" example:"0"`
 	IsMustPassStatus50       string                        `gorm:"column:is_must_pass_status_50" json:"is_must_pass_status_50" example:"0"`
+	ExtensionAutoApproveMins int                           `gorm:"column:extension_auto_approve_minutes" json:"extension_auto_approve_minutes" example:"30"`
 	CreatedAt                time.Time                     `gorm:"column:created_at;autoCreateTime" json:"-"`
 	UpdatedAt                time.Time                     `gorm:"column:updated_at;autoUpdateTime" json:"-"`
 	CreatedBy                string                        `gorm:"column:created_by" json:"-"`
@@ -119,6 +121,7 @@ type VmsMasCarpoolResponse struct {
 	IsMustPassStatus30       string                                `gorm:"column:is_must_pass_status_30" json:"is_must_pass_status_30" example:"0"`
 	IsMustPassStatus40       string                                `gorm:"column:is_must_pass_status_40" json:"is_must_pass_status_40" example:"0"`
 	IsMustPassStatus50       string                                `gorm:"column:is_must_pass_status_50" json:"is_must_pass_status_50" example:"0"`
+	ExtensionAutoApproveMins int                                   `gorm:"column:extension_auto_approve_minutes" json:"extension_auto_approve_minutes" example:"30"`
 	IsActive                 string                                `gorm:"column:is_active" json:"is_active"`
 	CarpoolChooseDriver      VmsRefCarpoolChooseDriver             `gorm:"foreignKey:RefCarpoolChooseDriverID;references:RefCarpoolChooseDriverID" json:"carpool_choose_driver"`
 	CarpoolChooseCar         VmsRefCarpoolChooseCar                `gorm:"foreignKey:RefCarpoolChooseCarID;references:RefCarpoolChooseCarID" json:"carpool_choose_car"`
@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// VmsTrnRequestComment
+type VmsTrnRequestComment struct {
+	TrnRequestCommentUID  string                           `gorm:"column:trn_request_comment_uid;primaryKey" json:"trn_request_comment_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	TrnRequestUID         string                           `gorm:"column:trn_request_uid" json:"trn_request_uid" example:"8bd09808-61fa-42fd-8a03-bf961b5678cd"`
+	ParentCommentUID      *string                          `gorm:"column:parent_comment_uid" json:"parent_comment_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	CommentText           string                           `gorm:"column:comment_text" json:"comment_text" example:"Please confirm the pickup point"`
+	CommentByEmpID        string                           `gorm:"column:comment_by_emp_id" json:"comment_by_emp_id" example:"700001"`
+	CommentByFullName     string                           `gorm:"column:comment_by_full_name" json:"comment_by_full_name" example:"John Doe"`
+	CommentByPosition     string                           `gorm:"column:comment_by_position" json:"comment_by_position" example:"Engineer"`
+	CommentByDeptSAPShort string                           `gorm:"column:comment_by_dept_sap_short" json:"comment_by_dept_sap_short" example:"กยจ."`
+	CommentByRole         string                           `gorm:"column:comment_by_role" json:"comment_by_role" example:"vehicle-user"`
+	RoleOfCreater         string                           `gorm:"-" json:"role_of_creater"`
+	Mentions              []VmsTrnRequestCommentMention    `gorm:"foreignKey:TrnRequestCommentUID;references:TrnRequestCommentUID" json:"mentions"`
+	Attachments           []VmsTrnRequestCommentAttachment `gorm:"foreignKey:TrnRequestCommentUID;references:TrnRequestCommentUID" json:"attachments"`
+	Replies               []VmsTrnRequestComment           `gorm:"-" json:"replies"`
+	CreatedAt             time.Time                        `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt             time.Time                        `gorm:"column:updated_at" json:"-"`
+	IsDeleted             string                           `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsTrnRequestComment) TableName() string {
+	return "vms_trn_request_comment"
+}
+
+// VmsTrnRequestCommentMention
+type VmsTrnRequestCommentMention struct {
+	TrnRequestCommentMentionUID string `gorm:"column:trn_request_comment_mention_uid;primaryKey" json:"-"`
+	TrnRequestCommentUID        string `gorm:"column:trn_request_comment_uid" json:"-"`
+	EmpID                       string `gorm:"column:emp_id" json:"emp_id" example:"700002"`
+	EmpName                     string `gorm:"column:emp_name" json:"emp_name" example:"Jane Doe"`
+}
+
+func (VmsTrnRequestCommentMention) TableName() string {
+	return "vms_trn_request_comment_mention"
+}
+
+// VmsTrnRequestCommentAttachment is a file uploaded through /api/upload
+type VmsTrnRequestCommentAttachment struct {
+	TrnRequestCommentAttachmentUID string `gorm:"column:trn_request_comment_attachment_uid;primaryKey" json:"-"`
+	TrnRequestCommentUID           string `gorm:"column:trn_request_comment_uid" json:"-"`
+	FileName                       string `gorm:"column:file_name" json:"file_name" example:"map.pdf"`
+	FileURL                        string `gorm:"column:file_url" json:"file_url" example:"https://vms-plus.pea.co.th/files/map.pdf"`
+}
+
+func (VmsTrnRequestCommentAttachment) TableName() string {
+	return "vms_trn_request_comment_attachment"
+}
+
+// VmsTrnRequestCommentRequest
+type VmsTrnRequestCommentRequest struct {
+	TrnRequestUID    string                                  `json:"trn_request_uid" binding:"required" example:"8bd09808-61fa-42fd-8a03-bf961b5678cd"`
+	ParentCommentUID *string                                 `json:"parent_comment_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	CommentText      string                                  `json:"comment_text" binding:"required" example:"Please confirm the pickup point"`
+	MentionEmpIDs    []string                                `json:"mention_emp_ids" example:"700002"`
+	Attachments      []VmsTrnRequestCommentAttachmentRequest `json:"attachments"`
+}
+
+// VmsTrnRequestCommentAttachmentRequest
+type VmsTrnRequestCommentAttachmentRequest struct {
+	FileName string `json:"file_name" example:"map.pdf"`
+	FileURL  string `json:"file_url" binding:"required" example:"https://vms-plus.pea.co.th/files/map.pdf"`
+}
+
+// VmsTrnRequestCommentParticipant is a person taking part in a request who can be mentioned and is notified of new comments
+type VmsTrnRequestCommentParticipant struct {
+	EmpID    string `json:"emp_id" example:"700001"`
+	FullName string `json:"full_name" example:"John Doe"`
+	Role     string `json:"role" example:"vehicle-user"`
+}
+
+// VmsTrnRequestCommentParticipantCheck holds the people of a request
+type VmsTrnRequestCommentParticipantCheck struct {
+	TrnRequestUID           string  `gorm:"column:trn_request_uid"`
+	RequestNo               string  `gorm:"column:request_no"`
+	RefRequestStatusCode    string  `gorm:"column:ref_request_status_code"`
+	MasCarpoolUID           *string `gorm:"column:mas_carpool_uid"`
+	CreatedRequestEmpID     string  `gorm:"column:created_request_emp_id"`
+	CreatedRequestEmpName   string  `gorm:"column:created_request_emp_name"`
+	VehicleUserEmpID        string  `gorm:"column:vehicle_user_emp_id"`
+	VehicleUserEmpName      string  `gorm:"column:vehicle_user_emp_name"`
+	DriverEmpID             string  `gorm:"column:driver_emp_id"`
+	DriverEmpName           string  `gorm:"column:driver_emp_name"`
+	ConfirmedRequestEmpID   string  `gorm:"column:confirmed_request_emp_id"`
+	ConfirmedRequestEmpName string  `gorm:"column:confirmed_request_emp_name"`
+	ApprovedRequestEmpID    string  `gorm:"column:approved_request_emp_id"`
+	ApprovedRequestEmpName  string  `gorm:"column:approved_request_emp_name"`
+}
+
+func (VmsTrnRequestCommentParticipantCheck) TableName() string {
+	return "vms_trn_request"
+}
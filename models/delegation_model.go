@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// VmsTrnDelegation
+type VmsTrnDelegation struct {
+	TrnDelegationUID       string       `gorm:"column:trn_delegation_uid;primaryKey" json:"trn_delegation_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	DelegatorEmpID         string       `gorm:"column:delegator_emp_id" json:"delegator_emp_id" example:"700001"`
+	DelegatorEmpName       string       `gorm:"column:delegator_emp_name" json:"delegator_emp_name" example:"John Doe"`
+	DelegatorDeptNameShort string       `gorm:"column:delegator_dept_name_short" json:"delegator_dept_name_short" example:"กบห."`
+	DelegateEmpID          string       `gorm:"column:delegate_emp_id" json:"delegate_emp_id" example:"700002"`
+	DelegateEmpName        string       `gorm:"column:delegate_emp_name" json:"delegate_emp_name" example:"Jane Doe"`
+	DelegateDeptNameShort  string       `gorm:"column:delegate_dept_name_short" json:"delegate_dept_name_short" example:"กบห."`
+	DelegatedRoles         string       `gorm:"column:delegated_roles" json:"delegated_roles" example:"level1-approval,approval-carpool"`
+	StartDatetime          TimeWithZone `gorm:"column:start_datetime" json:"start_datetime" swaggertype:"string" example:"2025-01-01T00:00:00Z"`
+	EndDatetime            TimeWithZone `gorm:"column:end_datetime" json:"end_datetime" swaggertype:"string" example:"2025-01-08T00:00:00Z"`
+	Remark                 string       `gorm:"column:remark" json:"remark" example:"Annual leave"`
+	CreatedAt              time.Time    `gorm:"column:created_at" json:"created_at"`
+	CreatedBy              string       `gorm:"column:created_by" json:"-"`
+	UpdatedAt              time.Time    `gorm:"column:updated_at" json:"-"`
+	UpdatedBy              string       `gorm:"column:updated_by" json:"-"`
+	IsDeleted              string       `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsTrnDelegation) TableName() string {
+	return "vms_trn_delegation"
+}
+
+// VmsTrnDelegationCreate
+type VmsTrnDelegationCreate struct {
+	DelegateEmpID  string       `json:"delegate_emp_id" example:"700002"`
+	DelegatedRoles []string     `json:"delegated_roles" example:"level1-approval,approval-carpool"`
+	StartDatetime  TimeWithZone `json:"start_datetime" swaggertype:"string" example:"2025-01-01T00:00:00Z"`
+	EndDatetime    TimeWithZone `json:"end_datetime" swaggertype:"string" example:"2025-01-08T00:00:00Z"`
+	Remark         string       `json:"remark" example:"Annual leave"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ImpersonationRequest starts a read-only impersonation of the employee
+type ImpersonationRequest struct {
+	EmpID           string `json:"emp_id" binding:"required" example:"700001"`
+	Reason          string `json:"reason" binding:"required" example:"Ticket 1234: the user cannot see the booking"`
+	DurationMinutes int    `json:"duration_minutes" example:"30"`
+}
+
+// ImpersonationResponse is the token to call the API as the impersonated user with, there is no refresh token
+type ImpersonationResponse struct {
+	AccessToken string    `json:"accessToken"`
+	SessionUID  string    `json:"session_uid"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
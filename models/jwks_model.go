@@ -0,0 +1,19 @@
+package models
+
+// JWK is the public part of a key VMS signs tokens with, see RFC 7517
+type JWK struct {
+	Kty string `json:"kty" example:"RSA"`
+	Kid string `json:"kid" example:"vms-2025-06"`
+	Use string `json:"use" example:"sig"`
+	Alg string `json:"alg" example:"RS256"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty" example:"P-256"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is the key set other services verify VMS tokens with
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
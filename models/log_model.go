@@ -58,3 +58,37 @@ type VmsLogRequest struct {
 func (VmsLogRequest) TableName() string {
 	return "vms_log_request_action"
 }
+
+// VmsLogRequestChange is one edit of a request with the fields it changed
+type VmsLogRequestChange struct {
+	LogRequestChangeUID  string                     `gorm:"primaryKey;column:log_request_change_uid" json:"log_request_change_uid"`
+	TrnRequestUID        string                     `gorm:"column:trn_request_uid;not null" json:"trn_request_uid"`
+	RefRequestStatusCode string                     `gorm:"column:ref_request_status_code" json:"ref_request_status_code"`
+	ChangeSection        string                     `gorm:"column:change_section" json:"change_section" example:"trip"`
+	ChangeDatetime       TimeWithZone               `gorm:"column:change_datetime" json:"change_datetime"`
+	ActionByPersonalID   string                     `gorm:"column:action_by_personal_id" json:"action_by_personal_id"`
+	ActionByFullname     string                     `gorm:"column:action_by_fullname" json:"action_by_fullname"`
+	ActionByRole         string                     `gorm:"column:action_by_role" json:"action_by_role"`
+	ActionByPosition     string                     `gorm:"column:action_by_position" json:"action_by_position"`
+	ActionByDepartment   string                     `gorm:"column:action_by_department" json:"action_by_department"`
+	RoleOfCreater        string                     `gorm:"-" json:"role_of_creater"`
+	Fields               []VmsLogRequestChangeField `gorm:"foreignKey:LogRequestChangeUID;references:LogRequestChangeUID" json:"fields"`
+	IsDeleted            string                     `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsLogRequestChange) TableName() string {
+	return "vms_log_request_change"
+}
+
+// VmsLogRequestChangeField is the value of a request column before and after an edit
+type VmsLogRequestChangeField struct {
+	LogRequestChangeFieldUID string `gorm:"primaryKey;column:log_request_change_field_uid" json:"-"`
+	LogRequestChangeUID      string `gorm:"column:log_request_change_uid" json:"-"`
+	FieldName                string `gorm:"column:field_name" json:"field_name" example:"work_place"`
+	OldValue                 string `gorm:"column:old_value" json:"old_value" example:"Head Office"`
+	NewValue                 string `gorm:"column:new_value" json:"new_value" example:"Branch Office"`
+}
+
+func (VmsLogRequestChangeField) TableName() string {
+	return "vms_log_request_change_field"
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// VmsTrnOtpCode is a code generated by the local OTP provider, only its hash is kept
+type VmsTrnOtpCode struct {
+	OtpID     string    `gorm:"column:otp_id;primaryKey" json:"otp_id"`
+	PhoneNo   string    `gorm:"column:phone_no" json:"phone_no"`
+	CodeHash  string    `gorm:"column:code_hash" json:"-"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	ExpiresAt time.Time `gorm:"column:expires_at" json:"expires_at"`
+}
+
+func (VmsTrnOtpCode) TableName() string {
+	return "vms_trn_otp_code"
+}
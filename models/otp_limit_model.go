@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// VmsTrnOtpLimitHit is one OTP request or failed verification counted against a phone number, an IP address or an otpId
+type VmsTrnOtpLimitHit struct {
+	OtpLimitHitID uint      `gorm:"column:otp_limit_hit_id;primaryKey;autoIncrement" json:"-"`
+	LimitKey      string    `gorm:"column:limit_key" json:"limit_key" example:"otp:phone:0818088770"`
+	HitAt         time.Time `gorm:"column:hit_at" json:"hit_at"`
+}
+
+func (VmsTrnOtpLimitHit) TableName() string {
+	return "vms_trn_otp_limit_hit"
+}
+
+// VmsTrnOtpLockout is the lockout of a phone number or an IP address, strikes double the cooldown of the next lockout
+type VmsTrnOtpLockout struct {
+	LimitKey    string    `gorm:"column:limit_key;primaryKey" json:"limit_key" example:"otp:phone:0818088770"`
+	LockedUntil time.Time `gorm:"column:locked_until" json:"locked_until"`
+	Strikes     int       `gorm:"column:strikes" json:"strikes"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (VmsTrnOtpLockout) TableName() string {
+	return "vms_trn_otp_lockout"
+}
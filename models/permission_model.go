@@ -0,0 +1,16 @@
+package models
+
+// EmployeePermission is what an employee may do and see, resolved the same way as at login
+type EmployeePermission struct {
+	EmpID               string   `json:"emp_id" example:"700001"`
+	FullName            string   `json:"full_name"`
+	DeptSAP             string   `json:"dept_sap"`
+	BureauDeptSap       string   `json:"bureau_dept_sap"`
+	BusinessArea        string   `json:"business_area"`
+	Roles               []string `json:"roles"`
+	Permissions         []string `json:"permissions"`
+	Regions             []string `json:"regions"`
+	ScopeLevel          string   `json:"scope_level" example:"department"`
+	MasCarpoolUIDs      []string `json:"mas_carpool_uids"`
+	AdminMasCarpoolUIDs []string `json:"admin_mas_carpool_uids"`
+}
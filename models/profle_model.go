@@ -28,10 +28,16 @@ type AuthenUserEmp struct {
 	TrnRequestAnnualDriverUID string   `gorm:"-" json:"trn_request_annual_driver_uid"`
 	AnnualYYYY                int      `gorm:"-" json:"annual_yyyy" example:"2568"`
 	Roles                     []string `gorm:"-" json:"roles"`
+	DelegatedRoles            []string `gorm:"-" json:"-"`
+	Regions                   []string `gorm:"-" json:"regions"`
 	LoginBy                   string   `gorm:"-" json:"login_by"`
 	IsEmployee                bool     `gorm:"-" json:"is_employee"`
 	LevelCode                 string   `gorm:"-" json:"level_code"`
 	IsLevelM5                 string   `gorm:"-" json:"is_level_m5"`
+	SessionUID                string   `gorm:"-" json:"-"`
+	IsImpersonated            bool     `gorm:"-" json:"is_impersonated"`
+	ImpersonatorEmpID         string   `gorm:"-" json:"impersonator_emp_id,omitempty"`
+	ImpersonatorFullName      string   `gorm:"-" json:"impersonator_full_name,omitempty"`
 }
 
 func (AuthenUserEmp) TableName() string {
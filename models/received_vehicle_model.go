@@ -67,6 +67,8 @@ type VmsTrnTravelCard struct {
 	ApprovedRequestDeptSAP      string `gorm:"column:approved_request_dept_sap" json:"approved_request_dept_sap" example:"Finance"`
 	ApprovedRequestDeptSAPShort string `gorm:"column:approved_request_dept_name_short" json:"approved_request_dept_sap_short" example:"Finance"`
 	ApprovedRequestDeptSAPFull  string `gorm:"column:approved_request_dept_name_full" json:"approved_request_dept_sap_full" example:"Finance"`
+
+	Passengers []VmsTrnRequestPassenger `gorm:"-" json:"passengers"`
 }
 
 func (VmsTrnTravelCard) TableName() string {
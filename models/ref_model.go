@@ -181,3 +181,13 @@ type VmsRefTimelineStatus struct {
 	RefTimelineStatusID   string `gorm:"primaryKey;column:ref_timeline_status_id" json:"ref_timeline_status_id"`
 	RefTimelineStatusName string `gorm:"column:ref_timeline_status_name" json:"ref_timeline_status_name"`
 }
+
+// VmsRefRequestAttachmentType
+type VmsRefRequestAttachmentType struct {
+	RefRequestAttachmentTypeCode int    `gorm:"column:ref_request_attachment_type_code;primaryKey" json:"ref_request_attachment_type_code" example:"1"`
+	RefRequestAttachmentTypeName string `gorm:"column:ref_request_attachment_type_name" json:"ref_request_attachment_type_name" example:"หนังสืออ้างอิง"`
+}
+
+func (VmsRefRequestAttachmentType) TableName() string {
+	return "vms_ref_request_attachment_type"
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// VmsMasRegionAdmin grants admin-region over every business area that starts with BusinessArea,
+// a region letter such as "C" or a single business area such as "C011"
+type VmsMasRegionAdmin struct {
+	MasRegionAdminUID string    `gorm:"column:mas_region_admin_uid;primaryKey" json:"mas_region_admin_uid"`
+	AdminEmpNo        string    `gorm:"column:admin_emp_no" json:"admin_emp_no" example:"990003"`
+	AdminEmpName      string    `gorm:"column:admin_emp_name" json:"admin_emp_name"`
+	AdminDeptSap      string    `gorm:"column:admin_dept_sap" json:"admin_dept_sap"`
+	AdminPosition     string    `gorm:"column:admin_position" json:"admin_position"`
+	BusinessArea      string    `gorm:"column:business_area" json:"business_area" example:"C"`
+	IsActive          string    `gorm:"column:is_active" json:"is_active" example:"1"`
+	IsDeleted         string    `gorm:"column:is_deleted" json:"-"`
+	CreatedAt         time.Time `gorm:"column:created_at;autoCreateTime" json:"-"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;autoUpdateTime" json:"-"`
+	CreatedBy         string    `gorm:"column:created_by" json:"-"`
+	UpdatedBy         string    `gorm:"column:updated_by" json:"-"`
+}
+
+func (VmsMasRegionAdmin) TableName() string {
+	return "vms_mas_region_admin"
+}
+
+// VmsMasRegionAdminRequest is the body to assign a region administrator
+type VmsMasRegionAdminRequest struct {
+	AdminEmpNo   string `json:"admin_emp_no" binding:"required" example:"990003"`
+	BusinessArea string `json:"business_area" binding:"required" example:"C"`
+	IsActive     string `json:"is_active" example:"1"`
+}
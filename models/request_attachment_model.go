@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// VmsTrnRequestAttachment is a document of a booking request uploaded through /api/upload
+type VmsTrnRequestAttachment struct {
+	TrnRequestAttachmentUID      string                      `gorm:"column:trn_request_attachment_uid;primaryKey" json:"trn_request_attachment_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	TrnRequestUID                string                      `gorm:"column:trn_request_uid" json:"trn_request_uid" example:"8bd09808-61fa-42fd-8a03-bf961b5678cd"`
+	RefRequestAttachmentTypeCode int                         `gorm:"column:ref_request_attachment_type_code" json:"ref_request_attachment_type_code" example:"1"`
+	RefRequestAttachmentType     VmsRefRequestAttachmentType `gorm:"foreignKey:RefRequestAttachmentTypeCode;references:RefRequestAttachmentTypeCode" json:"ref_request_attachment_type"`
+	DocNo                        string                      `gorm:"column:doc_no" json:"doc_no" example:"REF123456"`
+	FileName                     string                      `gorm:"column:file_name" json:"file_name" example:"document.pdf"`
+	FileURL                      string                      `gorm:"column:file_url" json:"file_url" example:"https://vms-plus.pea.co.th/files/document.pdf"`
+	IsLegacyDocument             bool                        `gorm:"-" json:"is_legacy_document"`
+	CreatedAt                    time.Time                   `gorm:"column:created_at" json:"created_at"`
+	CreatedBy                    string                      `gorm:"column:created_by" json:"created_by"`
+	UpdatedAt                    time.Time                   `gorm:"column:updated_at" json:"-"`
+	UpdatedBy                    string                      `gorm:"column:updated_by" json:"-"`
+	IsDeleted                    string                      `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsTrnRequestAttachment) TableName() string {
+	return "vms_trn_request_attachment"
+}
+
+// VmsTrnRequestAttachmentRequest
+type VmsTrnRequestAttachmentRequest struct {
+	TrnRequestUID                string `json:"trn_request_uid" binding:"required" example:"8bd09808-61fa-42fd-8a03-bf961b5678cd"`
+	RefRequestAttachmentTypeCode int    `json:"ref_request_attachment_type_code" binding:"required" example:"1"`
+	DocNo                        string `json:"doc_no" example:"REF123456"`
+	FileName                     string `json:"file_name" example:"document.pdf"`
+	FileURL                      string `json:"file_url" binding:"required" example:"https://vms-plus.pea.co.th/files/document.pdf"`
+}
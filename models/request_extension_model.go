@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// VmsTrnRequestExtension
+type VmsTrnRequestExtension struct {
+	TrnRequestExtensionUID  string       `gorm:"column:trn_request_extension_uid;primaryKey" json:"trn_request_extension_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	TrnRequestUID           string       `gorm:"column:trn_request_uid" json:"trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	OriginalEndDatetime     TimeWithZone `gorm:"column:original_end_datetime" json:"original_end_datetime" swaggertype:"string" example:"2025-04-16T16:00:00Z"`
+	RequestedEndDatetime    TimeWithZone `gorm:"column:requested_end_datetime" json:"requested_end_datetime" swaggertype:"string" example:"2025-04-16T18:00:00Z"`
+	ExtensionReason         string       `gorm:"column:extension_reason" json:"extension_reason" example:"Meeting runs late"`
+	ExtensionStatusCode     string       `gorm:"column:extension_status_code" json:"extension_status_code" example:"0"`
+	ExtensionStatusName     string       `gorm:"-" json:"extension_status_name" example:"รออนุมัติ"`
+	IsAutoApproved          string       `gorm:"column:is_auto_approved" json:"is_auto_approved" example:"0"`
+	RequestedEmpID          string       `gorm:"column:requested_emp_id" json:"requested_emp_id" example:"700001"`
+	RequestedEmpName        string       `gorm:"column:requested_emp_name" json:"requested_emp_name" example:"John Doe"`
+	ApprovedEmpID           string       `gorm:"column:approved_emp_id" json:"approved_emp_id" example:"700002"`
+	ApprovedEmpName         string       `gorm:"column:approved_emp_name" json:"approved_emp_name" example:"Jane Doe"`
+	ApprovedDatetime        TimeWithZone `gorm:"column:approved_datetime" json:"approved_datetime" swaggertype:"string" example:"2025-04-16T15:00:00Z"`
+	RejectedExtensionReason string       `gorm:"column:rejected_extension_reason" json:"rejected_extension_reason" example:"Vehicle is needed"`
+	CreatedAt               time.Time    `gorm:"column:created_at" json:"created_at"`
+	CreatedBy               string       `gorm:"column:created_by" json:"-"`
+	UpdatedAt               time.Time    `gorm:"column:updated_at" json:"-"`
+	UpdatedBy               string       `gorm:"column:updated_by" json:"-"`
+	IsDeleted               string       `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsTrnRequestExtension) TableName() string {
+	return "vms_trn_request_extension"
+}
+
+// VmsTrnRequestExtensionCreate
+type VmsTrnRequestExtensionCreate struct {
+	TrnRequestUID        string       `json:"trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	RequestedEndDatetime TimeWithZone `json:"requested_end_datetime" swaggertype:"string" example:"2025-04-16T18:00:00Z"`
+	ExtensionReason      string       `json:"extension_reason" example:"Meeting runs late"`
+}
+
+// VmsTrnRequestExtensionApproved
+type VmsTrnRequestExtensionApproved struct {
+	TrnRequestExtensionUID string `json:"trn_request_extension_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+}
+
+// VmsTrnRequestExtensionRejected
+type VmsTrnRequestExtensionRejected struct {
+	TrnRequestExtensionUID  string `json:"trn_request_extension_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	RejectedExtensionReason string `json:"rejected_extension_reason" example:"Vehicle is needed"`
+}
+
+// VmsTrnRequestReservation
+type VmsTrnRequestReservation struct {
+	TrnRequestUID              string       `gorm:"column:trn_request_uid;primaryKey" json:"trn_request_uid"`
+	RefRequestStatusCode       string       `gorm:"column:ref_request_status_code" json:"ref_request_status_code"`
+	CreatedRequestEmpID        string       `gorm:"column:created_request_emp_id" json:"-"`
+	VehicleUserEmpID           string       `gorm:"column:vehicle_user_emp_id" json:"-"`
+	MasVehicleUID              *string      `gorm:"column:mas_vehicle_uid" json:"mas_vehicle_uid"`
+	MasCarpoolUID              *string      `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid"`
+	MasCarPoolDriverUID        *string      `gorm:"column:mas_carpool_driver_uid" json:"mas_carpool_driver_uid"`
+	RefTripTypeCode            int          `gorm:"column:ref_trip_type_code" json:"trip_type"`
+	ReserveStartDatetime       TimeWithZone `gorm:"column:reserve_start_datetime" json:"start_datetime"`
+	ReserveEndDatetime         TimeWithZone `gorm:"column:reserve_end_datetime" json:"end_datetime"`
+	OriginalReserveEndDatetime TimeWithZone `gorm:"column:original_reserve_end_datetime" json:"original_end_datetime"`
+	UpdatedAt                  time.Time    `gorm:"column:updated_at" json:"-"`
+	UpdatedBy                  string       `gorm:"column:updated_by" json:"-"`
+}
+
+func (VmsTrnRequestReservation) TableName() string {
+	return "public.vms_trn_request"
+}
@@ -58,6 +58,7 @@ type VmsTrnRequestList struct {
 	RefRequestStatusCode             string                 `gorm:"column:ref_request_status_code" json:"ref_request_status_code"`
 	RefRequestStatusName             string                 `json:"ref_request_status_name"`
 	IsHaveSubRequest                 string                 `gorm:"column:is_have_sub_request" json:"is_have_sub_request" example:"0"`
+	ParentTrnRequestUID              *string                `gorm:"column:parent_trn_request_uid" json:"parent_trn_request_uid"`
 	ReceivedKeyPlace                 string                 `gorm:"column:appointment_key_handover_place" json:"received_key_place" example:"Main Office"`
 	ReceivedKeyStartDatetime         TimeWithZone           `gorm:"column:appointment_key_handover_start_datetime" json:"received_key_start_datetime" swaggertype:"string" example:"2025-02-16T08:00:00Z"`
 	ReceivedKeyEndDatetime           TimeWithZone           `gorm:"column:appointment_key_handover_end_datetime" json:"received_key_end_datetime" swaggertype:"string" example:"2025-02-16T09:30:00Z"`
@@ -74,6 +75,7 @@ type VmsTrnRequestList struct {
 	CarpoolName                      string                 `gorm:"column:vehicle_carpool_name" json:"vehicle_carpool_name"`
 	WorkDescription                  string                 `gorm:"column:work_description" json:"work_description"`
 	ActionDetail                     string                 `gorm:"column:action_detail" json:"action_detail"`
+	PassengerNames                   string                 `gorm:"column:passenger_names" json:"-"`
 	CanPickupButton                  bool                   `gorm:"-" json:"can_pickup_button"`
 	CanScoreButton                   bool                   `gorm:"-" json:"can_score_button"`
 	CanTravelCardButton              bool                   `gorm:"-" json:"can_travel_card_button"`
@@ -126,6 +128,7 @@ type VmsTrnRequestRequest struct {
 	RefTripTypeCode      int          `gorm:"ref_trip_type_code" json:"trip_type" example:"1"`
 
 	WorkPlace          string `gorm:"column:work_place" json:"work_place" example:"Head Office"`
+	IsOutOfProvince    string `gorm:"column:is_out_of_province" json:"is_out_of_province" example:"0"`
 	WorkDescription    string `gorm:"column:work_description" json:"work_description" example:"Business Meeting"`
 	NumberOfPassengers int    `gorm:"column:number_of_passengers" json:"number_of_passengers" example:"3"`
 	Remark             string `gorm:"column:remark" json:"remark" example:"Urgent request"`
@@ -178,6 +181,8 @@ type VmsTrnRequestRequest struct {
 	ConfirmedRequestDeptSAP       string `gorm:"column:confirmed_request_dept_sap" json:"-"`
 	ConfirmedRequestDeptNameShort string `gorm:"column:confirmed_request_dept_name_short" json:"-"`
 	ConfirmedRequestDeptNameFull  string `gorm:"column:confirmed_request_dept_name_full" json:"-"`
+
+	TrnWaitlistUID string `gorm:"-" json:"trn_waitlist_uid" example:""`
 	//
 	CreatedAt time.Time `gorm:"column:created_at" json:"-"`
 	CreatedBy string    `gorm:"column:created_by" json:"-"`
@@ -213,6 +218,7 @@ type VmsTrnRequestResponse struct {
 	RefTripType          VmsRefTripType `gorm:"foreignKey:RefTripTypeCode;references:RefTripTypeCode" json:"trip_type_name"`
 
 	WorkPlace          string `gorm:"column:work_place" json:"work_place" example:"Head Office"`
+	IsOutOfProvince    string `gorm:"column:is_out_of_province" json:"is_out_of_province" example:"0"`
 	WorkDescription    string `gorm:"column:work_description" json:"work_description" example:"Business Meeting"`
 	NumberOfPassengers int    `gorm:"column:number_of_passengers" json:"number_of_passengers" example:"3"`
 	Remark             string `gorm:"column:remark" json:"remark" example:"Urgent request"`
@@ -220,6 +226,9 @@ type VmsTrnRequestResponse struct {
 	DocFile            string `gorm:"column:doc_file" json:"doc_file" example:"https://vms-plus.pea.co.th/files/document.pdf"`
 	DocFileName        string `gorm:"column:doc_file_name" json:"doc_file_name" example:"document.pdf"`
 
+	IsHaveSubRequest    string  `gorm:"column:is_have_sub_request" json:"is_have_sub_request" example:"0"`
+	ParentTrnRequestUID *string `gorm:"column:parent_trn_request_uid" json:"parent_trn_request_uid"`
+
 	NumberOfAvailableDrivers int `gorm:"-" json:"number_of_available_drivers" example:"2"`
 
 	RefCostTypeCode int            `gorm:"column:ref_cost_type_code" json:"ref_cost_type_code" example:"1"`
@@ -288,6 +297,9 @@ type VmsTrnRequestResponse struct {
 	CarpoolName              string  `gorm:"column:carpool_name" json:"carpool_name"`
 	CanChooseVehicle         bool    `gorm:"-" json:"can_choose_vehicle"`
 	CanChooseDriver          bool    `gorm:"-" json:"can_choose_driver"`
+
+	ApprovalSteps []VmsTrnRequestApprovalStep `gorm:"-" json:"approval_steps"`
+	Attachments   []VmsTrnRequestAttachment   `gorm:"-" json:"attachments"`
 }
 
 func (VmsTrnRequestResponse) TableName() string {
@@ -339,6 +351,7 @@ type VmsTrnRequestTrip struct {
 	ReserveEndDatetime   TimeWithZone `gorm:"column:reserve_end_datetime" json:"end_datetime" swaggertype:"string" example:"2025-01-01T10:00:00Z"`
 	RefTripTypeCode      int          `gorm:"ref_trip_type_code" json:"trip_type" example:"1"`
 	WorkPlace            string       `gorm:"column:work_place" json:"work_place" example:"Head Office"`
+	IsOutOfProvince      string       `gorm:"column:is_out_of_province" json:"is_out_of_province" example:"0"`
 	WorkDescription      string       `gorm:"column:work_description" json:"work_description" example:"Business Meeting"`
 	NumberOfPassengers   int          `gorm:"column:number_of_passengers" json:"number_of_passengers" example:"3"`
 	Remark               string       `gorm:"column:remark" json:"remark" example:"Urgent request"`
@@ -572,3 +585,28 @@ func (VmsTrnRequestDriver) TableName() string {
 type VmsTrnRequestVehicleInfo struct {
 	NumberOfAvailableDrivers int `gorm:"-" json:"number_of_available_drivers" example:"2"`
 }
+
+// VmsTrnRequestDraft
+type VmsTrnRequestDraft struct {
+	TrnRequestUID string    `gorm:"column:trn_request_uid;primarykey" json:"trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	UpdatedAt     time.Time `gorm:"column:updated_at" json:"-"`
+	UpdatedBy     string    `gorm:"column:updated_by" json:"-"`
+}
+
+func (VmsTrnRequestDraft) TableName() string {
+	return "public.vms_trn_request"
+}
+
+// VmsTrnRequestDraftStep
+type VmsTrnRequestDraftStep struct {
+	Step          string   `json:"step" example:"trip"`
+	IsComplete    bool     `json:"is_complete" example:"false"`
+	MissingFields []string `json:"missing_fields" example:"work_place"`
+}
+
+// VmsTrnRequestDraftValidation
+type VmsTrnRequestDraftValidation struct {
+	TrnRequestUID string                   `json:"trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	IsComplete    bool                     `json:"is_complete" example:"false"`
+	Steps         []VmsTrnRequestDraftStep `json:"steps"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// VmsTrnRequestPassenger
+type VmsTrnRequestPassenger struct {
+	TrnRequestPassengerUID string    `gorm:"column:trn_request_passenger_uid;primaryKey" json:"trn_request_passenger_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	TrnRequestUID          string    `gorm:"column:trn_request_uid" json:"-"`
+	IsPEAEmployee          string    `gorm:"column:is_pea_employee" json:"is_pea_employee" example:"1"`
+	EmpID                  string    `gorm:"column:emp_id" json:"emp_id" example:"700001"`
+	PassengerName          string    `gorm:"column:passenger_name" json:"passenger_name" example:"John Doe"`
+	PassengerPosition      string    `gorm:"column:passenger_position" json:"passenger_position" example:"Engineer"`
+	PassengerDeptSAP       string    `gorm:"column:passenger_dept_sap" json:"passenger_dept_sap" example:"DPT001"`
+	PassengerDeptNameShort string    `gorm:"column:passenger_dept_name_short" json:"passenger_dept_name_short" example:"กบห."`
+	PassengerMobilePhone   string    `gorm:"column:passenger_mobile_phone" json:"passenger_mobile_phone" example:"0987654321"`
+	PassengerDeskPhone     string    `gorm:"column:passenger_desk_phone" json:"passenger_desk_phone" example:"1122"`
+	IsNotify               string    `gorm:"column:is_notify" json:"is_notify" example:"1"`
+	CreatedAt              time.Time `gorm:"column:created_at" json:"-"`
+	CreatedBy              string    `gorm:"column:created_by" json:"-"`
+	UpdatedAt              time.Time `gorm:"column:updated_at" json:"-"`
+	UpdatedBy              string    `gorm:"column:updated_by" json:"-"`
+	IsDeleted              string    `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsTrnRequestPassenger) TableName() string {
+	return "vms_trn_request_passenger"
+}
+
+// VmsTrnRequestPassengerInput
+type VmsTrnRequestPassengerInput struct {
+	IsPEAEmployee        string `json:"is_pea_employee" example:"1"`
+	EmpID                string `json:"emp_id" example:"700001"`
+	PassengerName        string `json:"passenger_name" example:"John Doe"`
+	PassengerMobilePhone string `json:"passenger_mobile_phone" example:"0987654321"`
+	IsNotify             string `json:"is_notify" example:"1"`
+}
+
+// VmsTrnRequestPassengerUpdate
+type VmsTrnRequestPassengerUpdate struct {
+	TrnRequestUID string                        `json:"trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	Passengers    []VmsTrnRequestPassengerInput `json:"passengers"`
+}
+
+// VmsTrnRequestPassengerSeat
+type VmsTrnRequestPassengerSeat struct {
+	TrnRequestUID        string  `gorm:"column:trn_request_uid;primaryKey" json:"trn_request_uid"`
+	MasVehicleUID        *string `gorm:"column:mas_vehicle_uid" json:"mas_vehicle_uid"`
+	MasCarPoolDriverUID  *string `gorm:"column:mas_carpool_driver_uid" json:"mas_carpool_driver_uid"`
+	IsPEAEmployeeDriver  string  `gorm:"column:is_pea_employee_driver" json:"is_pea_employee_driver"`
+	RequestedVehicleType string  `gorm:"column:requested_vehicle_type" json:"requested_vehicle_type"`
+	NumberOfPassengers   int     `gorm:"column:number_of_passengers" json:"number_of_passengers"`
+}
+
+func (VmsTrnRequestPassengerSeat) TableName() string {
+	return "public.vms_trn_request"
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// VmsTrnRequestTemplate
+type VmsTrnRequestTemplate struct {
+	TrnRequestTemplateUID string `gorm:"column:trn_request_template_uid;primaryKey" json:"trn_request_template_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	EmpID                 string `gorm:"column:emp_id" json:"-"`
+	TemplateName          string `gorm:"column:template_name" json:"template_name" example:"Weekly site visit"`
+
+	//Step1
+	VehicleUserEmpID       string `gorm:"column:vehicle_user_emp_id" json:"vehicle_user_emp_id" example:"990001"`
+	VehicleUserDeskPhone   string `gorm:"column:vehicle_user_desk_phone" json:"car_user_internal_contact_number" example:"1122"`
+	VehicleUserMobilePhone string `gorm:"column:vehicle_user_mobile_phone" json:"car_user_mobile_contact_number" example:"0987654321"`
+	RefTripTypeCode        int    `gorm:"column:ref_trip_type_code" json:"trip_type" example:"1"`
+	WorkPlace              string `gorm:"column:work_place" json:"work_place" example:"Head Office"`
+	WorkDescription        string `gorm:"column:work_description" json:"work_description" example:"Business Meeting"`
+	NumberOfPassengers     int    `gorm:"column:number_of_passengers" json:"number_of_passengers" example:"3"`
+	Remark                 string `gorm:"column:remark" json:"remark" example:"Urgent request"`
+	RefCostTypeCode        int    `gorm:"column:ref_cost_type_code" json:"ref_cost_type_code" example:"1"`
+	CostCenter             string `gorm:"column:cost_center" json:"cost_center" example:"B0002211"`
+	WbsNo                  string `gorm:"column:wbs_no" json:"wbs_no" example:"WBS12345"`
+	NetworkNo              string `gorm:"column:network_no" json:"network_no" example:"NET12345"`
+	ActivityNo             string `gorm:"column:activity_no" json:"activity_no" example:"A12345"`
+	PmOrderNo              string `gorm:"column:pm_order_no" json:"pm_order_no" example:"PM123456"`
+
+	//Step 2
+	MasCarpoolUID        *string `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	RequestedVehicleType string  `gorm:"column:requested_vehicle_type" json:"requested_vehicle_type" example:"Sedan"`
+	MasVehicleUID        *string `gorm:"column:mas_vehicle_uid" json:"mas_vehicle_uid" example:"21d2ea5a-4ad6-4a95-a64d-73b72d43bd55"`
+
+	//Step 3
+	MasCarPoolDriverUID  *string `gorm:"column:mas_carpool_driver_uid" json:"mas_carpool_driver_uid" example:"a6c8a34b-9245-49c8-a12b-45fae77a4e7d"`
+	IsPEAEmployeeDriver  string  `gorm:"column:is_pea_employee_driver" json:"is_pea_employee_driver" example:"1"`
+	DriverEmpID          string  `gorm:"column:driver_emp_id" json:"driver_emp_id" example:"700001"`
+	DriverEmpDeskPhone   string  `gorm:"column:driver_emp_desk_phone" json:"driver_internal_contact_number" example:"1221"`
+	DriverEmpMobilePhone string  `gorm:"column:driver_emp_mobile_phone" json:"driver_mobile_contact_number" example:"0987654321"`
+	PickupPlace          *string `gorm:"column:pickup_place" json:"pickup_place" example:"Main Office"`
+
+	//Step 4
+	ConfirmedRequestEmpID string `gorm:"column:confirmed_request_emp_id" json:"confirmed_request_emp_id" example:"501621"`
+
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	CreatedBy string    `gorm:"column:created_by" json:"-"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+	UpdatedBy string    `gorm:"column:updated_by" json:"-"`
+	IsDeleted string    `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsTrnRequestTemplate) TableName() string {
+	return "vms_trn_request_template"
+}
+
+// VmsTrnRequestTemplateDraft
+type VmsTrnRequestTemplateDraft struct {
+	TrnRequestTemplateUID string       `json:"trn_request_template_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	StartDatetime         TimeWithZone `json:"start_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	EndDatetime           TimeWithZone `json:"end_datetime" swaggertype:"string" example:"2025-01-01T16:00:00Z"`
+}
+
+// VmsTrnRequestDuplicate
+type VmsTrnRequestDuplicate struct {
+	TrnRequestUID string       `json:"trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	StartDatetime TimeWithZone `json:"start_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	EndDatetime   TimeWithZone `json:"end_datetime" swaggertype:"string" example:"2025-01-01T16:00:00Z"`
+}
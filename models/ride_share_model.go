@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// VmsTrnRideShare
+type VmsTrnRideShare struct {
+	TrnRideShareUID     string                   `gorm:"column:trn_ride_share_uid;primaryKey" json:"trn_ride_share_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	MasCarpoolUID       *string                  `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	HostTrnRequestUID   string                   `gorm:"column:host_trn_request_uid" json:"host_trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	WorkPlace           string                   `gorm:"column:work_place" json:"work_place" example:"Head Office"`
+	StartDatetime       TimeWithZone             `gorm:"column:start_datetime" json:"start_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	EndDatetime         TimeWithZone             `gorm:"column:end_datetime" json:"end_datetime" swaggertype:"string" example:"2025-01-01T16:00:00Z"`
+	TotalPassengers     int                      `gorm:"column:total_passengers" json:"total_passengers" example:"5"`
+	SeatCapacity        int                      `gorm:"column:seat_capacity" json:"seat_capacity" example:"6"`
+	RideShareStatusCode string                   `gorm:"column:ride_share_status_code" json:"ride_share_status_code" example:"0"`
+	RideShareStatusName string                   `gorm:"-" json:"ride_share_status_name" example:"รอพิจารณา"`
+	AcceptedEmpID       string                   `gorm:"column:accepted_emp_id" json:"accepted_emp_id" example:"700001"`
+	AcceptedEmpName     string                   `gorm:"column:accepted_emp_name" json:"accepted_emp_name" example:"John Doe"`
+	AcceptedDatetime    TimeWithZone             `gorm:"column:accepted_datetime" json:"accepted_datetime" swaggertype:"string" example:"2025-01-01T07:00:00Z"`
+	RejectedReason      string                   `gorm:"column:rejected_reason" json:"rejected_reason" example:"Different routes"`
+	Requests            []VmsTrnRideShareRequest `gorm:"foreignKey:TrnRideShareUID;references:TrnRideShareUID" json:"requests"`
+	CreatedAt           time.Time                `gorm:"column:created_at" json:"created_at"`
+	CreatedBy           string                   `gorm:"column:created_by" json:"-"`
+	UpdatedAt           time.Time                `gorm:"column:updated_at" json:"-"`
+	UpdatedBy           string                   `gorm:"column:updated_by" json:"-"`
+	IsDeleted           string                   `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsTrnRideShare) TableName() string {
+	return "vms_trn_ride_share"
+}
+
+// VmsTrnRideShareRequest
+type VmsTrnRideShareRequest struct {
+	TrnRideShareRequestUID string                    `gorm:"column:trn_ride_share_request_uid;primaryKey" json:"-"`
+	TrnRideShareUID        string                    `gorm:"column:trn_ride_share_uid" json:"-"`
+	TrnRequestUID          string                    `gorm:"column:trn_request_uid" json:"trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	IsHost                 string                    `gorm:"column:is_host" json:"is_host" example:"1"`
+	Request                *VmsTrnRideShareCandidate `gorm:"-" json:"request"`
+}
+
+func (VmsTrnRideShareRequest) TableName() string {
+	return "vms_trn_ride_share_request"
+}
+
+// VmsTrnRideShareCandidate
+type VmsTrnRideShareCandidate struct {
+	TrnRequestUID            string       `gorm:"column:trn_request_uid;primaryKey" json:"trn_request_uid"`
+	RequestNo                string       `gorm:"column:request_no" json:"request_no"`
+	RefRequestStatusCode     string       `gorm:"column:ref_request_status_code" json:"ref_request_status_code"`
+	VehicleUserEmpName       string       `gorm:"column:vehicle_user_emp_name" json:"vehicle_user_emp_name"`
+	VehicleUserDeptNameShort string       `gorm:"column:vehicle_user_dept_name_short" json:"vehicle_user_dept_name_short"`
+	WorkPlace                string       `gorm:"column:work_place" json:"work_place"`
+	ReserveStartDatetime     TimeWithZone `gorm:"column:reserve_start_datetime" json:"start_datetime"`
+	ReserveEndDatetime       TimeWithZone `gorm:"column:reserve_end_datetime" json:"end_datetime"`
+	RefTripTypeCode          int          `gorm:"column:ref_trip_type_code" json:"trip_type"`
+	NumberOfPassengers       int          `gorm:"column:number_of_passengers" json:"number_of_passengers"`
+	MasCarpoolUID            *string      `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid"`
+	MasVehicleUID            *string      `gorm:"column:mas_vehicle_uid" json:"mas_vehicle_uid"`
+	MasVehicleDepartmentUID  *string      `gorm:"column:mas_vehicle_department_uid" json:"-"`
+	MasCarPoolDriverUID      *string      `gorm:"column:mas_carpool_driver_uid" json:"mas_carpool_driver_uid"`
+	IsPEAEmployeeDriver      string       `gorm:"column:is_pea_employee_driver" json:"is_pea_employee_driver"`
+	DriverEmpID              string       `gorm:"column:driver_emp_id" json:"driver_emp_id"`
+	DriverEmpName            string       `gorm:"column:driver_emp_name" json:"driver_emp_name"`
+	DriverEmpDeptSAP         string       `gorm:"column:driver_emp_dept_sap" json:"-"`
+	DriverEmpDeptNameShort   string       `gorm:"column:driver_emp_dept_name_short" json:"-"`
+	DriverEmpDeptNameFull    string       `gorm:"column:driver_emp_dept_name_full" json:"-"`
+	IsHaveSubRequest         string       `gorm:"column:is_have_sub_request" json:"is_have_sub_request"`
+	ParentTrnRequestUID      *string      `gorm:"column:parent_trn_request_uid" json:"parent_trn_request_uid"`
+	SeatCapacity             int          `gorm:"-" json:"seat_capacity"`
+	UpdatedAt                time.Time    `gorm:"column:updated_at" json:"-"`
+	UpdatedBy                string       `gorm:"column:updated_by" json:"-"`
+}
+
+func (VmsTrnRideShareCandidate) TableName() string {
+	return "public.vms_trn_request"
+}
+
+// VmsTrnRideShareSearch
+type VmsTrnRideShareSearch struct {
+	StartDatetime TimeWithZone `json:"start_datetime" swaggertype:"string" example:"2025-01-01T00:00:00Z"`
+	EndDatetime   TimeWithZone `json:"end_datetime" swaggertype:"string" example:"2025-01-08T00:00:00Z"`
+}
+
+// VmsTrnRideShareAccepted
+type VmsTrnRideShareAccepted struct {
+	TrnRideShareUID string `json:"trn_ride_share_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+}
+
+// VmsTrnRideShareRejected
+type VmsTrnRideShareRejected struct {
+	TrnRideShareUID string `json:"trn_ride_share_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	RejectedReason  string `json:"rejected_reason" example:"Different routes"`
+}
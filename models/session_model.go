@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// VmsTrnSession is one login, every token refreshed from it belongs to the same session
+type VmsTrnSession struct {
+	SessionUID    string     `gorm:"column:session_uid;primaryKey" json:"session_uid"`
+	EmpID         string     `gorm:"column:emp_id" json:"emp_id"`
+	LoginBy       string     `gorm:"column:login_by" json:"login_by" example:"keycloak"`
+	Device        string     `gorm:"column:device" json:"device" example:"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"`
+	IPAddress     string     `gorm:"column:ip_address" json:"ip_address" example:"10.0.0.1"`
+	CreatedAt     time.Time  `gorm:"column:created_at" json:"created_at"`
+	LastUsedAt    time.Time  `gorm:"column:last_used_at" json:"last_used_at"`
+	ExpiresAt     time.Time  `gorm:"column:expires_at" json:"expires_at"`
+	IsRevoked     string     `gorm:"column:is_revoked" json:"-"`
+	RevokedAt     *time.Time `gorm:"column:revoked_at" json:"-"`
+	RevokedBy     string     `gorm:"column:revoked_by" json:"-"`
+	RevokedReason string     `gorm:"column:revoked_reason" json:"-"`
+	IsCurrent     bool       `gorm:"-" json:"is_current"`
+
+	ImpersonatorEmpID string `gorm:"column:impersonator_emp_id" json:"impersonator_emp_id,omitempty"`
+}
+
+func (VmsTrnSession) TableName() string {
+	return "vms_trn_session"
+}
+
+// VmsTrnSessionToken is a token issued for a session, a refresh token can be used once
+type VmsTrnSessionToken struct {
+	Jti        string     `gorm:"column:jti;primaryKey" json:"jti"`
+	SessionUID string     `gorm:"column:session_uid" json:"session_uid"`
+	TokenType  string     `gorm:"column:token_type" json:"token_type" example:"refresh"`
+	IssuedAt   time.Time  `gorm:"column:issued_at" json:"issued_at"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at" json:"expires_at"`
+	IsUsed     string     `gorm:"column:is_used" json:"is_used"`
+	UsedAt     *time.Time `gorm:"column:used_at" json:"used_at"`
+}
+
+func (VmsTrnSessionToken) TableName() string {
+	return "vms_trn_session_token"
+}
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// VmsMasSla
+type VmsMasSla struct {
+	MasSlaUID            string    `gorm:"column:mas_sla_uid;primaryKey" json:"mas_sla_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	RefRequestStatusCode string    `gorm:"column:ref_request_status_code" json:"ref_request_status_code" example:"20"`
+	MasCarpoolUID        *string   `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	SlaHours             int       `gorm:"column:sla_hours" json:"sla_hours" example:"16"`
+	ReminderHours        int       `gorm:"column:reminder_hours" json:"reminder_hours" example:"8"`
+	EscalateHours        int       `gorm:"column:escalate_hours" json:"escalate_hours" example:"24"`
+	IsAutoCancel         string    `gorm:"column:is_auto_cancel" json:"is_auto_cancel" example:"1"`
+	IsActive             string    `gorm:"column:is_active" json:"is_active" example:"1"`
+	CreatedAt            time.Time `gorm:"column:created_at" json:"-"`
+	CreatedBy            string    `gorm:"column:created_by" json:"-"`
+	UpdatedAt            time.Time `gorm:"column:updated_at" json:"-"`
+	UpdatedBy            string    `gorm:"column:updated_by" json:"-"`
+	IsDeleted            string    `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsMasSla) TableName() string {
+	return "vms_mas_sla"
+}
+
+// VmsMasSlaRequest
+type VmsMasSlaRequest struct {
+	MasSlaUID            string  `json:"mas_sla_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	RefRequestStatusCode string  `json:"ref_request_status_code" example:"20"`
+	MasCarpoolUID        *string `json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	SlaHours             int     `json:"sla_hours" example:"16"`
+	ReminderHours        int     `json:"reminder_hours" example:"8"`
+	EscalateHours        int     `json:"escalate_hours" example:"24"`
+	IsAutoCancel         string  `json:"is_auto_cancel" example:"1"`
+	IsActive             string  `json:"is_active" example:"1"`
+}
+
+// VmsTrnRequestSla tracks how long a request stays in one pending status
+type VmsTrnRequestSla struct {
+	TrnRequestSlaUID     string       `gorm:"column:trn_request_sla_uid;primaryKey" json:"trn_request_sla_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	TrnRequestUID        string       `gorm:"column:trn_request_uid" json:"trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	RefRequestStatusCode string       `gorm:"column:ref_request_status_code" json:"ref_request_status_code" example:"20"`
+	MasSlaUID            string       `gorm:"column:mas_sla_uid" json:"mas_sla_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	StatusStartDatetime  TimeWithZone `gorm:"column:status_start_datetime" json:"status_start_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	StatusEndDatetime    TimeWithZone `gorm:"column:status_end_datetime" json:"status_end_datetime" swaggertype:"string" example:"2025-01-02T08:00:00Z"`
+	ElapsedHours         float64      `gorm:"column:elapsed_hours" json:"elapsed_hours" example:"10.5"`
+	RemindedDatetime     TimeWithZone `gorm:"column:reminded_datetime" json:"reminded_datetime" swaggertype:"string" example:"2025-01-01T16:00:00Z"`
+	EscalatedDatetime    TimeWithZone `gorm:"column:escalated_datetime" json:"escalated_datetime" swaggertype:"string" example:"2025-01-02T08:00:00Z"`
+	EscalatedEmpID       string       `gorm:"column:escalated_emp_id" json:"escalated_emp_id" example:"700001"`
+	IsBreached           string       `gorm:"column:is_breached" json:"is_breached" example:"1"`
+	IsAutoCanceled       string       `gorm:"column:is_auto_canceled" json:"is_auto_canceled" example:"0"`
+	CreatedAt            time.Time    `gorm:"column:created_at" json:"-"`
+	UpdatedAt            time.Time    `gorm:"column:updated_at" json:"-"`
+}
+
+func (VmsTrnRequestSla) TableName() string {
+	return "vms_trn_request_sla"
+}
+
+// VmsTrnRequestSlaCheck is a pending request checked by the SLA job
+type VmsTrnRequestSlaCheck struct {
+	TrnRequestUID           string       `gorm:"column:trn_request_uid" json:"trn_request_uid"`
+	RequestNo               string       `gorm:"column:request_no" json:"request_no"`
+	RefRequestStatusCode    string       `gorm:"column:ref_request_status_code" json:"ref_request_status_code"`
+	MasCarpoolUID           *string      `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid"`
+	ConfirmedRequestEmpID   string       `gorm:"column:confirmed_request_emp_id" json:"confirmed_request_emp_id"`
+	ConfirmedRequestDeptSAP string       `gorm:"column:confirmed_request_dept_sap" json:"confirmed_request_dept_sap"`
+	ApprovedRequestEmpID    string       `gorm:"column:approved_request_emp_id" json:"approved_request_emp_id"`
+	ApprovedRequestDeptSAP  string       `gorm:"column:approved_request_dept_sap" json:"approved_request_dept_sap"`
+	ReserveStartDatetime    TimeWithZone `gorm:"column:reserve_start_datetime" json:"reserve_start_datetime"`
+}
+
+func (VmsTrnRequestSlaCheck) TableName() string {
+	return "vms_trn_request"
+}
+
+// VmsTrnRequestSlaReport
+type VmsTrnRequestSlaReport struct {
+	VmsTrnRequestSla
+	RequestNo            string       `gorm:"column:request_no" json:"request_no" example:"VA67RA000001"`
+	VehicleUserEmpName   string       `gorm:"column:vehicle_user_emp_name" json:"vehicle_user_emp_name" example:"John Doe"`
+	ReserveStartDatetime TimeWithZone `gorm:"column:reserve_start_datetime" json:"start_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	MasCarpoolUID        *string      `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	SlaHours             int          `gorm:"column:sla_hours" json:"sla_hours" example:"16"`
+}
@@ -196,6 +196,27 @@ func (VmsMasVehicleCanBooking) TableName() string {
 	return "vms_mas_vehicle_can_booking"
 }
 
+// VmsMasVehicleSuggestSlot
+type VmsMasVehicleSuggestSlot struct {
+	StartDatetime  TimeWithZone `json:"start_datetime" swaggertype:"string" example:"2025-05-30T09:00:00Z"`
+	EndDatetime    TimeWithZone `json:"end_datetime" swaggertype:"string" example:"2025-05-30T17:00:00Z"`
+	ShiftMinutes   int          `json:"shift_minutes" example:"60"`
+	AvailableCount int          `json:"available_count" example:"2"`
+}
+
+// VmsMasVehicleSuggestVehicle
+type VmsMasVehicleSuggestVehicle struct {
+	VmsMasVehicleList
+	MasCarpoolUID  string `gorm:"column:carpool_uid" json:"mas_carpool_uid"`
+	IsSameCarType  bool   `gorm:"-" json:"is_same_car_type"`
+	IsSameSeat     bool   `gorm:"-" json:"is_same_seat"`
+	DeviationScore int    `gorm:"-" json:"deviation_score"`
+}
+
+func (VmsMasVehicleSuggestVehicle) TableName() string {
+	return "vms_mas_vehicle"
+}
+
 type VmsMasVehicleImg struct {
 	MasVehicleUID         string `gorm:"column:mas_vehicle_uid" json:"-"`
 	RefVehicleImgSideCode int    `gorm:"column:ref_vehicle_img_side_code" json:"ref_vehicle_img_side_code"`
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// VmsTrnWaitlist
+type VmsTrnWaitlist struct {
+	TrnWaitlistUID        string       `gorm:"column:trn_waitlist_uid;primaryKey" json:"trn_waitlist_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	EmpID                 string       `gorm:"column:emp_id" json:"emp_id" example:"700001"`
+	EmpName               string       `gorm:"column:emp_name" json:"emp_name" example:"John Doe"`
+	DeptSAP               string       `gorm:"column:dept_sap" json:"dept_sap" example:"DPT001"`
+	BureauDeptSap         string       `gorm:"column:bureau_dept_sap" json:"-"`
+	BusinessArea          string       `gorm:"column:business_area" json:"-"`
+	MasCarpoolUID         *string      `gorm:"column:mas_carpool_uid" json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	MasVehicleUID         *string      `gorm:"column:mas_vehicle_uid" json:"mas_vehicle_uid" example:"21d2ea5a-4ad6-4a95-a64d-73b72d43bd55"`
+	RequestedVehicleType  string       `gorm:"column:requested_vehicle_type" json:"requested_vehicle_type" example:"Sedan"`
+	StartDatetime         TimeWithZone `gorm:"column:start_datetime" json:"start_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	EndDatetime           TimeWithZone `gorm:"column:end_datetime" json:"end_datetime" swaggertype:"string" example:"2025-01-01T16:00:00Z"`
+	WaitlistStatusCode    string       `gorm:"column:waitlist_status_code" json:"waitlist_status_code" example:"0"`
+	WaitlistStatusName    string       `gorm:"-" json:"waitlist_status_name" example:"รอคิว"`
+	OfferedMasVehicleUID  *string      `gorm:"column:offered_mas_vehicle_uid" json:"offered_mas_vehicle_uid" example:"21d2ea5a-4ad6-4a95-a64d-73b72d43bd55"`
+	OfferedTrnRequestUID  *string      `gorm:"column:offered_trn_request_uid" json:"-"`
+	OfferedDatetime       TimeWithZone `gorm:"column:offered_datetime" json:"offered_datetime" swaggertype:"string" example:"2025-01-01T07:00:00Z"`
+	HoldExpireDatetime    TimeWithZone `gorm:"column:hold_expire_datetime" json:"hold_expire_datetime" swaggertype:"string" example:"2025-01-01T07:30:00Z"`
+	AcceptedTrnRequestUID *string      `gorm:"column:accepted_trn_request_uid" json:"accepted_trn_request_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+	CreatedAt             time.Time    `gorm:"column:created_at" json:"created_at"`
+	CreatedBy             string       `gorm:"column:created_by" json:"-"`
+	UpdatedAt             time.Time    `gorm:"column:updated_at" json:"-"`
+	UpdatedBy             string       `gorm:"column:updated_by" json:"-"`
+	IsDeleted             string       `gorm:"column:is_deleted" json:"-"`
+}
+
+func (VmsTrnWaitlist) TableName() string {
+	return "vms_trn_waitlist"
+}
+
+// VmsTrnWaitlistCreate
+type VmsTrnWaitlistCreate struct {
+	MasCarpoolUID        *string      `json:"mas_carpool_uid" example:"389b0f63-4195-4ece-bf35-0011c2f5f28c"`
+	MasVehicleUID        *string      `json:"mas_vehicle_uid" example:"21d2ea5a-4ad6-4a95-a64d-73b72d43bd55"`
+	RequestedVehicleType string       `json:"requested_vehicle_type" example:"Sedan"`
+	StartDatetime        TimeWithZone `json:"start_datetime" swaggertype:"string" example:"2025-01-01T08:00:00Z"`
+	EndDatetime          TimeWithZone `json:"end_datetime" swaggertype:"string" example:"2025-01-01T16:00:00Z"`
+}
+
+// VmsTrnWaitlistUpdate
+type VmsTrnWaitlistUpdate struct {
+	TrnWaitlistUID string `json:"trn_waitlist_uid" example:"0b07440c-ab04-49d0-8730-d62ce0a9bab9"`
+}
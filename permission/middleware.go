@@ -0,0 +1,20 @@
+package permission
+
+import (
+	"vms_plus_be/funcs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Require lets the call through when the user holds one of the permissions and answers 403 otherwise,
+// the resolved user is kept for funcs.GetAuthenUser in the handler
+func Require(permissions ...string) gin.HandlerFunc {
+	roles := GetRoles(permissions...)
+	return func(c *gin.Context) {
+		funcs.GetAuthenUser(c, roles)
+		if c.IsAborted() {
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,171 @@
+package permission
+
+import (
+	"sort"
+	"strings"
+	"vms_plus_be/models"
+)
+
+// Permissions a route or a handler may require
+const (
+	// Authenticated is held by every signed in user, whatever roles they have
+	Authenticated = "authenticated"
+
+	BookingRequest  = "booking.request"
+	BookingConfirm  = "booking.confirm"
+	BookingAdmin    = "booking.admin"
+	BookingApprove  = "booking.approve"
+	DriverJob       = "driver.job"
+	VehicleInUseJob = "vehicle-in-use.job"
+
+	LicenseRequest = "license.request"
+	LicenseConfirm = "license.confirm"
+	LicenseApprove = "license.approve"
+
+	DelegationManage   = "delegation.manage"
+	AutoApprovalManage = "auto-approval.manage"
+	SlaManage          = "sla.manage"
+	RideShareManage    = "ride-share.manage"
+
+	MasterDataVehicle = "master-data.vehicle"
+	MasterDataDriver  = "master-data.driver"
+	MasterDataCarpool = "master-data.carpool"
+	AuditLogRead      = "audit-log.read"
+	PermissionRead    = "permission.read"
+	RegionAdminManage = "region-admin.manage"
+	SessionManage     = "session.manage"
+	ApiClientManage   = "api-client.manage"
+	Impersonate       = "impersonation.start"
+
+	// ScopeAll, ScopeRegion and ScopeDepartment tell how much master data and how many carpools the user sees, see scope.go
+	ScopeAll        = "scope.all"
+	ScopeRegion     = "scope.region"
+	ScopeDepartment = "scope.department"
+)
+
+// RolePermissions maps each role to the permissions it grants
+var RolePermissions = map[string][]string{
+	"vehicle-user": {
+		BookingRequest,
+		LicenseRequest,
+		VehicleInUseJob,
+	},
+	"driver": {
+		DriverJob,
+		VehicleInUseJob,
+	},
+	"level1-approval": {
+		BookingConfirm,
+		LicenseConfirm,
+		DelegationManage,
+	},
+	"approval-department": {
+		BookingApprove,
+		DelegationManage,
+	},
+	"approval-carpool": {
+		BookingApprove,
+		DelegationManage,
+	},
+	"license-approval": {
+		LicenseApprove,
+		DelegationManage,
+	},
+	"admin-carpool": {
+		BookingAdmin,
+		VehicleInUseJob,
+		AutoApprovalManage,
+		SlaManage,
+		RideShareManage,
+	},
+	"admin-department": {
+		BookingAdmin,
+		VehicleInUseJob,
+		MasterDataVehicle,
+		MasterDataDriver,
+		MasterDataCarpool,
+		ScopeDepartment,
+	},
+	"admin-department-main": {
+		BookingAdmin,
+		VehicleInUseJob,
+		MasterDataVehicle,
+		MasterDataDriver,
+		MasterDataCarpool,
+	},
+	"admin-region": {
+		MasterDataVehicle,
+		MasterDataDriver,
+		MasterDataCarpool,
+		ScopeRegion,
+	},
+	"support": {
+		Impersonate,
+	},
+	"admin-super": {
+		MasterDataVehicle,
+		MasterDataDriver,
+		MasterDataCarpool,
+		AutoApprovalManage,
+		SlaManage,
+		AuditLogRead,
+		PermissionRead,
+		RegionAdminManage,
+		SessionManage,
+		ApiClientManage,
+		ScopeAll,
+	},
+}
+
+// GetRoles returns the roles that grant any of the permissions as the comma-separated list funcs.GetAuthenUser takes
+func GetRoles(permissions ...string) string {
+	roles := []string{}
+	for _, permission := range permissions {
+		if permission == Authenticated {
+			return "*"
+		}
+		for role, rolePermissions := range RolePermissions {
+			if contains(rolePermissions, permission) && !contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	sort.Strings(roles)
+	return strings.Join(roles, ",")
+}
+
+// GetPermissions returns the permissions granted by the roles, sorted
+func GetPermissions(roles []string) []string {
+	permissions := []string{Authenticated}
+	for _, role := range roles {
+		for _, permission := range RolePermissions[role] {
+			if !contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	sort.Strings(permissions)
+	return permissions
+}
+
+// HasPermission tells whether one of the user's roles grants the permission
+func HasPermission(user *models.AuthenUserEmp, permission string) bool {
+	if permission == Authenticated {
+		return true
+	}
+	for _, role := range user.Roles {
+		if contains(RolePermissions[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
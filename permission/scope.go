@@ -0,0 +1,126 @@
+package permission
+
+import (
+	"strings"
+	"vms_plus_be/config"
+	"vms_plus_be/funcs"
+	"vms_plus_be/models"
+
+	"gorm.io/gorm"
+)
+
+// Scope levels, from the widest
+const (
+	ScopeLevelAll        = "all"
+	ScopeLevelRegion     = "region"
+	ScopeLevelDepartment = "department"
+	ScopeLevelNone       = "none"
+)
+
+// GetRegions returns the business-area prefixes an admin-region user manages. A user given admin-region
+// outside vms_mas_region_admin manages their own business area only, none when they have no business area.
+func GetRegions(user *models.AuthenUserEmp) []string {
+	if !HasPermission(user, ScopeRegion) {
+		return []string{}
+	}
+	if len(user.Regions) > 0 {
+		return user.Regions
+	}
+	if user.BusinessArea != "" {
+		return []string{user.BusinessArea}
+	}
+	return []string{}
+}
+
+// regionCondition matches column against every region of the user
+func regionCondition(user *models.AuthenUserEmp, column string) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	for _, region := range GetRegions(user) {
+		conditions = append(conditions, column+" like ?")
+		args = append(args, region+"%")
+	}
+	return "(" + strings.Join(conditions, " or ") + ")", args
+}
+
+// GetScopeLevel returns the widest master-data scope the user's roles grant
+func GetScopeLevel(user *models.AuthenUserEmp) string {
+	if HasPermission(user, ScopeAll) {
+		return ScopeLevelAll
+	}
+	if len(GetRegions(user)) > 0 {
+		return ScopeLevelRegion
+	}
+	if HasPermission(user, ScopeDepartment) {
+		return ScopeLevelDepartment
+	}
+	return ScopeLevelNone
+}
+
+// SetQueryDepartmentScope limits vehicles or drivers to the departments the user may see,
+// alias is the department table of the query with its bureau_ba and bureau_dept_sap columns
+func SetQueryDepartmentScope(user *models.AuthenUserEmp, query *gorm.DB, alias string) *gorm.DB {
+	switch GetScopeLevel(user) {
+	case ScopeLevelAll:
+		return query
+	case ScopeLevelRegion:
+		condition, args := regionCondition(user, alias+".bureau_ba")
+		return query.Where(condition, args...)
+	case ScopeLevelDepartment:
+		return query.Where(alias+".bureau_dept_sap = ?", user.BureauDeptSap)
+	}
+	return query.Where("1 = 0")
+}
+
+// SetQueryCarpoolScope limits carpools to those the user may see, alias is the vms_mas_carpool table of the query
+func SetQueryCarpoolScope(user *models.AuthenUserEmp, query *gorm.DB, alias string) *gorm.DB {
+	switch GetScopeLevel(user) {
+	case ScopeLevelAll:
+		return query
+	case ScopeLevelRegion:
+		condition, args := regionCondition(user, alias+".carpool_main_business_area")
+		return query.Where(condition, args...)
+	case ScopeLevelDepartment:
+		return query.Where("exists (select 1 from vms_mas_carpool_authorized_dept cad where cad.mas_carpool_uid="+alias+".mas_carpool_uid and public.fn_get_bureau_dept_sap(cad.dept_sap) = ?)", user.BureauDeptSap)
+	}
+	return query.Where("1 = 0")
+}
+
+// SetQueryAdminCarpoolScope limits rows to the carpools the user administers, carpoolColumn is the carpool uid column of the query
+func SetQueryAdminCarpoolScope(user *models.AuthenUserEmp, query *gorm.DB, carpoolColumn string) *gorm.DB {
+	if HasPermission(user, ScopeAll) {
+		return query
+	}
+	return query.Where(carpoolColumn+` in (
+			select ca.mas_carpool_uid from vms_mas_carpool_admin ca
+			where ca.admin_emp_no = ? and ca.is_deleted = '0' and ca.is_active = '1'
+		)`, user.EmpID)
+}
+
+// SetQueryRequestScope limits vms_trn_request to the requests the user may administer
+func SetQueryRequestScope(user *models.AuthenUserEmp, query *gorm.DB) *gorm.DB {
+	if HasPermission(user, ScopeAll) {
+		return query
+	}
+	return funcs.SetQueryAdminRole(user, query)
+}
+
+// GetCarpoolUIDs returns the carpools the user may see, nil when the user sees them all
+func GetCarpoolUIDs(user *models.AuthenUserEmp) []string {
+	if GetScopeLevel(user) == ScopeLevelAll {
+		return nil
+	}
+	carpoolUIDs := []string{}
+	query := config.DB.Table("vms_mas_carpool cp").Where("cp.is_deleted = ?", "0")
+	SetQueryCarpoolScope(user, query, "cp").Pluck("cp.mas_carpool_uid", &carpoolUIDs)
+	return carpoolUIDs
+}
+
+// GetAdminCarpoolUIDs returns the carpools the user administers, their requests are the requests the user may administer
+func GetAdminCarpoolUIDs(user *models.AuthenUserEmp) []string {
+	carpoolUIDs := []string{}
+	config.DB.Table("vms_mas_carpool_admin").
+		Where("admin_emp_no = ? and is_deleted = '0' and is_active = '1'", user.EmpID).
+		Pluck("mas_carpool_uid", &carpoolUIDs)
+	return carpoolUIDs
+}